@@ -0,0 +1,278 @@
+package bdd
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/types"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ir"
+)
+
+// Node identifies a BDD node within its Manager.
+type Node int
+
+const (
+	False Node = 0
+	True  Node = 1
+)
+
+// Op is a binary operator given by its truth table, as ir.Op.Table
+// lays it out: bit 2*a+b holds the result for operands a and b.
+type Op uint8
+
+func (op Op) Apply(a bool, b bool) bool {
+	idx := 0
+	if a {
+		idx += 2
+	}
+	if b {
+		idx++
+	}
+	return op&(1<<idx) != 0
+}
+
+type node struct {
+	level int
+	low   Node
+	high  Node
+}
+
+type applyKey struct {
+	op   Op
+	a, b Node
+}
+
+// Manager holds a reduced ordered BDD over a fixed variable order.
+type Manager struct {
+	Vars   []string
+	levels map[string]int
+	nodes  []node
+	unique map[node]Node
+	cache  map[applyKey]Node
+}
+
+func New(vars []string) *Manager {
+	m := &Manager{
+		Vars:   vars,
+		levels: map[string]int{},
+		unique: map[node]Node{},
+		cache:  map[applyKey]Node{},
+	}
+	for idx, name := range vars {
+		m.levels[name] = idx
+	}
+	// Terminals sit below every variable.
+	m.nodes = []node{{level: len(vars)}, {level: len(vars)}}
+	return m
+}
+
+func (m *Manager) mk(level int, low Node, high Node) Node {
+	if low == high {
+		return low
+	}
+	key := node{level: level, low: low, high: high}
+	if id, ok := m.unique[key]; ok {
+		return id
+	}
+	id := Node(len(m.nodes))
+	m.nodes = append(m.nodes, key)
+	m.unique[key] = id
+	return id
+}
+
+// Size is the number of nodes allocated so far, terminals included.
+func (m *Manager) Size() int {
+	return len(m.nodes)
+}
+
+func (m *Manager) Var(name string) (Node, error) {
+	level, ok := m.levels[name]
+	if !ok {
+		return False, fmt.Errorf("unknown variable '%s'", name)
+	}
+	return m.mk(level, False, True), nil
+}
+
+func (m *Manager) Not(a Node) Node {
+	xor, _ := TableOp(ir.XOR)
+	return m.Apply(xor, a, True)
+}
+
+func (m *Manager) Apply(op Op, a Node, b Node) Node {
+	if a <= True && b <= True {
+		if op.Apply(a == True, b == True) {
+			return True
+		}
+		return False
+	}
+	key := applyKey{op: op, a: a, b: b}
+	if id, ok := m.cache[key]; ok {
+		return id
+	}
+	level := min(m.nodes[a].level, m.nodes[b].level)
+	aLow, aHigh := m.cofactors(a, level)
+	bLow, bHigh := m.cofactors(b, level)
+	id := m.mk(level, m.Apply(op, aLow, bLow), m.Apply(op, aHigh, bHigh))
+	m.cache[key] = id
+	return id
+}
+
+func (m *Manager) cofactors(a Node, level int) (Node, Node) {
+	if m.nodes[a].level != level {
+		return a, a
+	}
+	return m.nodes[a].low, m.nodes[a].high
+}
+
+// Regd. Construction
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+// TableOp is the table of a binary connective; temporal operators have
+// none.
+func TableOp(op ir.Op) (Op, bool) {
+	table, ok := op.Table()
+	return Op(table), ok
+}
+
+func (m *Manager) FromExpr(expr *booleanAst.Expr) (Node, error) {
+	node, err := ir.Lower(expr)
+	if err != nil {
+		return False, err
+	}
+	return m.FromNode(node)
+}
+
+// FromNode builds the BDD of a semantic tree.
+func (m *Manager) FromNode(node ir.Node) (Node, error) {
+	switch n := node.(type) {
+	case *ir.Const:
+		if n.Value {
+			return True, nil
+		}
+		return False, nil
+	case *ir.Var:
+		id, err := m.Var(n.Name)
+		if err != nil {
+			return False, errorAt(n.Pos, "%s", err.Error())
+		}
+		return id, nil
+	case *ir.Cmp:
+		return False, errorAt(n.Pos, "integer comparison '%s' must be lowered to bits first", arith.Format(n.Cmp))
+	case *ir.Degree:
+		return False, errorAt(n.Pos, "truth degree '%s' needs a fuzzy logic", n.Text)
+	case *ir.Unary:
+		acc, err := m.FromNode(n.X)
+		if err != nil {
+			return False, err
+		}
+		switch n.Op {
+		case ir.NOT:
+			return m.Not(acc), nil
+		case ir.NULLIFY:
+			return False, nil
+		case ir.TRUIFY:
+			return True, nil
+		case ir.ID:
+			return acc, nil
+		default:
+			return False, errorAt(n.Pos, "invalid unary operator '%s'", n.Text)
+		}
+	case *ir.Binary:
+		left, err := m.FromNode(n.X)
+		if err != nil {
+			return False, err
+		}
+		right, err := m.FromNode(n.Y)
+		if err != nil {
+			return False, err
+		}
+		op, ok := TableOp(n.Op)
+		if !ok {
+			return False, errorAt(n.OpPos, "invalid binary operation '%s'", n.Text)
+		}
+		return m.Apply(op, left, right), nil
+	default:
+		return False, fmt.Errorf("invalid boolean expression '%v'", node)
+	}
+}
+
+// Regd. Counting
+
+// SatCount is the number of assignments to all of m.Vars under which a
+// is true.
+func (m *Manager) SatCount(a Node) *big.Int {
+	memo := map[Node]*big.Int{}
+	var count func(Node) *big.Int
+	// count(n) counts assignments to the variables at or below n's level.
+	count = func(n Node) *big.Int {
+		if n == False {
+			return big.NewInt(0)
+		}
+		if n == True {
+			return big.NewInt(1)
+		}
+		if acc, ok := memo[n]; ok {
+			return acc
+		}
+		level := m.nodes[n].level
+		low, high := m.nodes[n].low, m.nodes[n].high
+		acc := new(big.Int).Lsh(count(low), uint(m.nodes[low].level-level-1))
+		acc.Add(acc, new(big.Int).Lsh(count(high), uint(m.nodes[high].level-level-1)))
+		memo[n] = acc
+		return acc
+	}
+	return new(big.Int).Lsh(count(a), uint(m.nodes[a].level))
+}
+
+// Probability is the chance that a is true when every variable is
+// independently true with its probability in weights, or 0.5 when it has
+// none.
+func (m *Manager) Probability(a Node, weights map[string]float64) float64 {
+	memo := map[Node]float64{}
+	var prob func(Node) float64
+	prob = func(n Node) float64 {
+		if n <= True {
+			return float64(n)
+		}
+		if acc, ok := memo[n]; ok {
+			return acc
+		}
+		p, ok := weights[m.Vars[m.nodes[n].level]]
+		if !ok {
+			p = 0.5
+		}
+		acc := (1-p)*prob(m.nodes[n].low) + p*prob(m.nodes[n].high)
+		memo[n] = acc
+		return acc
+	}
+	return prob(a)
+}
+
+// ParseWeights reads a comma separated list of `name ~ probability`.
+func ParseWeights(input string) (map[string]float64, error) {
+	weights := map[string]float64{}
+	if strings.TrimSpace(input) == "" {
+		return weights, nil
+	}
+	for _, part := range strings.Split(input, ",") {
+		name, value, ok := strings.Cut(part, "~")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid weight '%s', expected 'name ~ probability'", strings.TrimSpace(part))
+		}
+		p, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || math.IsNaN(p) || p < 0 || 1 < p {
+			return nil, fmt.Errorf("invalid probability '%s' for '%s'", strings.TrimSpace(value), name)
+		}
+		weights[name] = p
+	}
+	return weights, nil
+}
@@ -0,0 +1,107 @@
+package bdd
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ir"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildFromString(t *testing.T, input string) (*Manager, Node) {
+	expr, err := boolean.ExprParser.ParseString("", input)
+	assert.NoError(t, err)
+	m := New(boolean.FreeVars(expr))
+	root, err := m.FromExpr(expr)
+	assert.NoError(t, err)
+	return m, root
+}
+
+func TestOpTablesAgreeWithInterpreter(t *testing.T) {
+	for _, op := range []string{
+		"and", "nand", "or", "nor", "xor", "xnor", "iff", "implies",
+		"is implied by", "inhibits", "is inhibited by", "left", "right",
+		"not left", "not right",
+	} {
+		irOp, ok := ir.ParseOp(op)
+		assert.True(t, ok)
+		table, ok := TableOp(irOp)
+		assert.True(t, ok)
+		for _, a := range []bool{false, true} {
+			for _, b := range []bool{false, true} {
+				expr, err := boolean.ExprParser.ParseString("", "p "+op+" q")
+				assert.NoError(t, err)
+				res := boolean.EvalExpr(expr, boolean.Env{"p": a, "q": b})
+				assert.Equal(t, res.Payload, types.Bool(table.Apply(a, b)), op)
+			}
+		}
+	}
+}
+
+func TestSatCount(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"a and b", 1},
+		{"a or b", 3},
+		{"a xor b xor c", 4},
+		{"a implies (b and c)", 5},
+		{"a and not a and b", 0},
+		{"a or not a or b or c", 8},
+		{"a left b", 2},
+		{"nullify a or b", 2},
+	}
+	for _, test := range tests {
+		m, root := buildFromString(t, test.input)
+		assert.Equal(t, big.NewInt(test.expected), m.SatCount(root), test.input)
+	}
+}
+
+func TestSatCountPastThirtyVariables(t *testing.T) {
+	vars := []string{}
+	for idx := range 80 {
+		vars = append(vars, "x"+strings.Repeat("x", idx))
+	}
+	m, root := buildFromString(t, strings.Join(vars, " xor "))
+	expected := new(big.Int).Lsh(big.NewInt(1), 79)
+	assert.Equal(t, expected, m.SatCount(root))
+	assert.Less(t, m.Size(), 1000)
+}
+
+func TestProbability(t *testing.T) {
+	m, root := buildFromString(t, "p and (q or r)")
+	weights := map[string]float64{"p": 0.3, "q": 0.5, "r": 0.2}
+	assert.InDelta(t, 0.3*(1-0.5*0.8), m.Probability(root, weights), 1e-12)
+	assert.InDelta(t, 0.5*0.75, m.Probability(root, map[string]float64{}), 1e-12)
+}
+
+func TestParseWeights(t *testing.T) {
+	weights, err := ParseWeights("p ~ 0.3, q~1")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"p": 0.3, "q": 1}, weights)
+
+	_, err = ParseWeights("p 0.3")
+	assert.EqualError(t, err, "invalid weight 'p 0.3', expected 'name ~ probability'")
+	_, err = ParseWeights("p ~ 1.5")
+	assert.EqualError(t, err, "invalid probability '1.5' for 'p'")
+	_, err = ParseWeights("p ~ NaN")
+	assert.EqualError(t, err, "invalid probability 'NaN' for 'p'")
+}
+
+func TestUnknownVariableFail(t *testing.T) {
+	expr, err := boolean.ExprParser.ParseString("", "p and q")
+	assert.NoError(t, err)
+	_, err = New([]string{"p"}).FromExpr(expr)
+	assert.EqualError(t, err, "1:7: unknown variable 'q'")
+}
+
+func TestDegreeFail(t *testing.T) {
+	expr, err := boolean.ExprParser.ParseString("", "p and 0.5")
+	assert.NoError(t, err)
+	_, err = New([]string{"p"}).FromExpr(expr)
+	assert.EqualError(t, err, "1:7: truth degree '0.5' needs a fuzzy logic")
+}
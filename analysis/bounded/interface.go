@@ -0,0 +1,374 @@
+package bounded
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+)
+
+const (
+	// MAX_RANGE_BITS bounds the size of a single range.
+	MAX_RANGE_BITS int = 16
+	// MAX_COMPARISON_BITS bounds the bits a single comparison may read, as
+	// every combination of its variables is enumerated.
+	MAX_COMPARISON_BITS int = 12
+)
+
+// Range is the inclusive set of values Lo..Hi an integer variable ranges
+// over.
+type Range struct {
+	Name string
+	Lo   int64
+	Hi   int64
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%s : %d%s%d", r.Name, r.Lo, lexer.RANGE_SYMB, r.Hi)
+}
+
+func (r Range) Size() int64 {
+	return r.Hi - r.Lo + 1
+}
+
+// Width is the number of bits needed to tell the values apart.
+func (r Range) Width() int {
+	return bits.Len64(uint64(r.Size() - 1))
+}
+
+// Bit names bit idx of the variable's offset from Lo. The brackets keep
+// it from clashing with any name that can be written in source.
+func (r Range) Bit(idx int) string {
+	return fmt.Sprintf("%s[%d]", r.Name, idx)
+}
+
+// Bits lists the bit names, most significant first.
+func (r Range) Bits() []string {
+	acc := []string{}
+	for idx := r.Width() - 1; 0 <= idx; idx-- {
+		acc = append(acc, r.Bit(idx))
+	}
+	return acc
+}
+
+// ParseRanges reads a comma separated list of `name : lo..hi`.
+func ParseRanges(input string) ([]Range, error) {
+	acc := []Range{}
+	if strings.TrimSpace(input) == "" {
+		return acc, nil
+	}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		name, bounds, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		loText, hiText, ok2 := strings.Cut(bounds, lexer.RANGE_SYMB)
+		if !ok || !ok2 || name == "" {
+			return nil, fmt.Errorf("invalid range '%s', expected 'name : lo..hi'", part)
+		}
+		lo, err := strconv.ParseInt(strings.TrimSpace(loText), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lower bound '%s' for '%s'", strings.TrimSpace(loText), name)
+		}
+		hi, err := strconv.ParseInt(strings.TrimSpace(hiText), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upper bound '%s' for '%s'", strings.TrimSpace(hiText), name)
+		}
+		r := Range{Name: name, Lo: lo, Hi: hi}
+		switch {
+		case hi < lo:
+			return nil, fmt.Errorf("range '%s' is empty", r)
+		case MAX_RANGE_BITS < r.Width():
+			return nil, fmt.Errorf("range '%s' is larger than %d values", r, 1<<MAX_RANGE_BITS)
+		case seen[name]:
+			return nil, fmt.Errorf("'%s' has two ranges", name)
+		}
+		seen[name] = true
+		acc = append(acc, r)
+	}
+	return acc, nil
+}
+
+// Regd. Lowering
+
+// Lowered is an expression over truth values only: every integer
+// variable is replaced by the bits of its offset from its lower bound.
+type Lowered struct {
+	Expr *booleanAst.Expr
+	// Vars are the truth-valued variables followed by the bits of every
+	// integer variable, so each assignment to Vars that satisfies Expr
+	// stands for exactly one assignment to the source variables.
+	Vars []string
+	// Total is the number of assignments to the source variables.
+	Total *big.Int
+}
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+// Lower bit-blasts every integer comparison in expr, so truth tables,
+// BDDs and SAT encodings can enumerate bounded integers. Each comparison
+// is evaluated on every value of its variables and becomes a formula over
+// the bits they are stored in; a range whose size is not a power of two
+// adds a conjunct that rules out the bit patterns beyond it.
+func Lower(expr *booleanAst.Expr, ranges []Range) (Lowered, error) {
+	byName := map[string]Range{}
+	for _, r := range ranges {
+		byName[r.Name] = r
+	}
+	boolVars := boolean.FreeVars(expr)
+	intVars := boolean.IntVars(expr)
+	for _, name := range boolVars {
+		if _, ok := byName[name]; ok {
+			return Lowered{}, fmt.Errorf("'%s' is used both as a truth value and as an integer", name)
+		}
+	}
+	lowered, err := lowerExpr(expr, byName)
+	if err != nil {
+		return Lowered{}, err
+	}
+	acc := Lowered{
+		Expr:  lowered,
+		Vars:  boolVars,
+		Total: new(big.Int).Lsh(big.NewInt(1), uint(len(boolVars))),
+	}
+	for _, name := range intVars {
+		r := byName[name]
+		acc.Vars = append(acc.Vars, r.Bits()...)
+		acc.Total.Mul(acc.Total, big.NewInt(r.Size()))
+		if r.Size() == 1<<r.Width() {
+			continue
+		}
+		table := make([]cell, 1<<r.Width())
+		for code := range table {
+			if int64(code) < r.Size() {
+				table[code] = HOLDS
+			}
+		}
+		acc.Expr = conjoin(acc.Expr, expand(r.Bits(), table, lowered.Pos))
+	}
+	return acc, nil
+}
+
+func lowerExpr(expr *booleanAst.Expr, ranges map[string]Range) (*booleanAst.Expr, error) {
+	primary, err := lowerPrimary(expr.Unary.Expr, ranges)
+	if err != nil {
+		return nil, err
+	}
+	acc := &booleanAst.Expr{
+		Pos:   expr.Pos,
+		Unary: &booleanAst.UnaryExpr{Pos: expr.Unary.Pos, Ops: expr.Unary.Ops, Expr: primary},
+	}
+	if expr.Rest != nil {
+		rest, err := lowerExpr(expr.Rest.Expr, ranges)
+		if err != nil {
+			return nil, err
+		}
+		acc.Rest = &booleanAst.ExprRest{Pos: expr.Rest.Pos, Op: expr.Rest.Op, Expr: rest}
+	}
+	return acc, nil
+}
+
+func lowerPrimary(expr *booleanAst.PrimaryExpr, ranges map[string]Range) (*booleanAst.PrimaryExpr, error) {
+	switch {
+	case expr.Paren != nil:
+		inner, err := lowerExpr(expr.Paren.Expr, ranges)
+		if err != nil {
+			return nil, err
+		}
+		return &booleanAst.PrimaryExpr{Pos: expr.Pos, Paren: &booleanAst.ParenExpr{Pos: expr.Paren.Pos, Expr: inner}}, nil
+	case expr.Cmp != nil:
+		return lowerComparison(expr, ranges)
+	default:
+		return expr, nil
+	}
+}
+
+func lowerComparison(expr *booleanAst.PrimaryExpr, ranges map[string]Range) (*booleanAst.PrimaryExpr, error) {
+	vars := []Range{}
+	names := []string{}
+	width := 0
+	for _, name := range arith.Vars(expr.Cmp) {
+		r, ok := ranges[name]
+		if !ok {
+			return nil, errorAt(expr.Pos, "'%s' needs a range, such as '%s : 0..7'", name, name)
+		}
+		vars = append(vars, r)
+		names = append(names, r.Bits()...)
+		width += r.Width()
+	}
+	if MAX_COMPARISON_BITS < width {
+		return nil, errorAt(
+			expr.Pos,
+			"'%s' reads %d bits, more than the %d that can be enumerated",
+			arith.Format(expr.Cmp),
+			width,
+			MAX_COMPARISON_BITS,
+		)
+	}
+	table := make([]cell, 1<<width)
+	for minterm := range table {
+		env, ok := decode(vars, uint(minterm))
+		if !ok {
+			table[minterm] = DONT_CARE
+			continue
+		}
+		holds, err := arith.EvalComparison(expr.Cmp, env)
+		if err != nil {
+			return nil, fmt.Errorf("%s, at %s", err.Error(), assignment(vars, env))
+		}
+		if holds {
+			table[minterm] = HOLDS
+		}
+	}
+	lowered := expand(names, table, expr.Pos)
+	if lowered.Rest == nil && len(lowered.Unary.Ops) == 0 {
+		return lowered.Unary.Expr, nil
+	}
+	return &booleanAst.PrimaryExpr{Pos: expr.Pos, Paren: &booleanAst.ParenExpr{Pos: expr.Pos, Expr: lowered}}, nil
+}
+
+// decode reads the value of each variable from its bits in minterm; it
+// fails on a bit pattern beyond a variable's range.
+func decode(vars []Range, minterm uint) (arith.Env, bool) {
+	env := arith.Env{}
+	shift := 0
+	for idx := len(vars) - 1; 0 <= idx; idx-- {
+		r := vars[idx]
+		code := int64(minterm>>shift) & (1<<r.Width() - 1)
+		if r.Size() <= code {
+			return nil, false
+		}
+		env[r.Name] = r.Lo + code
+		shift += r.Width()
+	}
+	return env, true
+}
+
+func assignment(vars []Range, env arith.Env) string {
+	parts := []string{}
+	for _, r := range vars {
+		parts = append(parts, fmt.Sprintf("%s = %d", r.Name, env[r.Name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Regd. Building
+
+func literal(name string, negated bool, pos types.Position) *booleanAst.Expr {
+	ops := []booleanAst.UnaryOp{}
+	if negated {
+		ops = append(ops, booleanAst.UnaryOp{Pos: pos, Op: lexer.NOT_TEXT})
+	}
+	return &booleanAst.Expr{Pos: pos, Unary: &booleanAst.UnaryExpr{
+		Pos:  pos,
+		Ops:  ops,
+		Expr: &booleanAst.PrimaryExpr{Pos: pos, Var: name},
+	}}
+}
+
+func constant(val bool, pos types.Position) *booleanAst.Expr {
+	return &booleanAst.Expr{Pos: pos, Unary: &booleanAst.UnaryExpr{
+		Pos:  pos,
+		Expr: &booleanAst.PrimaryExpr{Pos: pos, Lit: types.Bool(val).String()},
+	}}
+}
+
+// chain joins operands with op; a binary operand is parenthesized.
+func chain(operands []*booleanAst.Expr, op string, pos types.Position) *booleanAst.Expr {
+	var acc *booleanAst.Expr
+	for idx := len(operands) - 1; 0 <= idx; idx-- {
+		operand := operands[idx]
+		if operand.Rest != nil {
+			operand = &booleanAst.Expr{Pos: pos, Unary: &booleanAst.UnaryExpr{Pos: pos, Expr: &booleanAst.PrimaryExpr{
+				Pos:   pos,
+				Paren: &booleanAst.ParenExpr{Pos: pos, Expr: operand},
+			}}}
+		}
+		if acc != nil {
+			operand = &booleanAst.Expr{Pos: pos, Unary: operand.Unary, Rest: &booleanAst.ExprRest{Pos: pos, Op: op, Expr: acc}}
+		}
+		acc = operand
+	}
+	return acc
+}
+
+type cell uint8
+
+const (
+	FAILS cell = iota
+	HOLDS
+	DONT_CARE
+)
+
+// expand builds a formula over names, most significant first, that
+// agrees with table wherever it cares. It splits on the first name: when
+// both halves agree that name is not needed, and a constant half folds
+// into a plain `and` or `or`.
+func expand(names []string, table []cell, pos types.Position) *booleanAst.Expr {
+	if val, ok := constantOf(table); ok {
+		return constant(val, pos)
+	}
+	half := len(table) / 2
+	low, high := table[:half], table[half:]
+	if merged, ok := merge(low, high); ok {
+		return expand(names[1:], merged, pos)
+	}
+	bit, rest := names[0], names[1:]
+	lowVal, lowConst := constantOf(low)
+	highVal, highConst := constantOf(high)
+	switch {
+	case highConst && lowConst:
+		return literal(bit, !highVal, pos)
+	case highConst && highVal:
+		return chain([]*booleanAst.Expr{literal(bit, false, pos), expand(rest, low, pos)}, lexer.OR_TEXT, pos)
+	case highConst:
+		return chain([]*booleanAst.Expr{literal(bit, true, pos), expand(rest, low, pos)}, lexer.AND_TEXT, pos)
+	case lowConst && lowVal:
+		return chain([]*booleanAst.Expr{literal(bit, true, pos), expand(rest, high, pos)}, lexer.OR_TEXT, pos)
+	case lowConst:
+		return chain([]*booleanAst.Expr{literal(bit, false, pos), expand(rest, high, pos)}, lexer.AND_TEXT, pos)
+	}
+	return chain([]*booleanAst.Expr{
+		chain([]*booleanAst.Expr{literal(bit, false, pos), expand(rest, high, pos)}, lexer.AND_TEXT, pos),
+		chain([]*booleanAst.Expr{literal(bit, true, pos), expand(rest, low, pos)}, lexer.AND_TEXT, pos),
+	}, lexer.OR_TEXT, pos)
+}
+
+// constantOf reports the value every cared-for cell has, if they agree;
+// a table of don't-cares alone is False.
+func constantOf(table []cell) (bool, bool) {
+	holds, fails := false, false
+	for _, c := range table {
+		holds = holds || c == HOLDS
+		fails = fails || c == FAILS
+	}
+	return holds, !(holds && fails)
+}
+
+func merge(a []cell, b []cell) ([]cell, bool) {
+	acc := make([]cell, len(a))
+	for idx := range a {
+		switch {
+		case a[idx] == DONT_CARE:
+			acc[idx] = b[idx]
+		case b[idx] == DONT_CARE || a[idx] == b[idx]:
+			acc[idx] = a[idx]
+		default:
+			return nil, false
+		}
+	}
+	return acc, true
+}
+
+func conjoin(expr *booleanAst.Expr, constraint *booleanAst.Expr) *booleanAst.Expr {
+	return chain([]*booleanAst.Expr{expr, constraint}, lexer.AND_TEXT, expr.Pos)
+}
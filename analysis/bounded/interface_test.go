@@ -0,0 +1,104 @@
+package bounded
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/analysis/bdd"
+	"acornlang.dev/lang/analysis/rewrite"
+	"acornlang.dev/lang/parser/boolean"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ir"
+	"github.com/stretchr/testify/assert"
+)
+
+func lower(t *testing.T, input string, ranges string) Lowered {
+	expr, err := boolean.ExprParser.ParseString("", input)
+	assert.NoError(t, err, input)
+	parsed, err := ParseRanges(ranges)
+	assert.NoError(t, err, ranges)
+	lowered, err := Lower(expr, parsed)
+	assert.NoError(t, err, input)
+	return lowered
+}
+
+func count(t *testing.T, lowered Lowered) string {
+	m := bdd.New(lowered.Vars)
+	root, err := m.FromExpr(lowered.Expr)
+	assert.NoError(t, err)
+	return m.SatCount(root).String() + "/" + lowered.Total.String()
+}
+
+func format(t *testing.T, expr *booleanAst.Expr) string {
+	node, err := ir.Lower(expr)
+	assert.NoError(t, err)
+	return rewrite.Format(node)
+}
+
+func TestLowerCounts(t *testing.T) {
+	tests := []struct {
+		input    string
+		ranges   string
+		expected string
+	}{
+		{"x + y > 3", "x : 0..3, y : 0..3", "6/16"},
+		{"x > 2", "x : 1..5", "3/5"},
+		{"x > 2 and not flag", "x : 1..5", "3/10"},
+		{"x * x == 4", "x : -3..3", "2/7"},
+		{"x mod 3 == 0 or p", "x : 0..9", "14/20"},
+		{"x < 0", "x : 0..9", "0/10"},
+		{"x >= 0", "x : 0..9", "10/10"},
+		{"x == y and y == z", "x : 0..2, y : 0..2, z : 0..2", "3/27"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, count(t, lower(t, test.input, test.ranges)), test.input)
+	}
+}
+
+func TestLowerFormat(t *testing.T) {
+	lowered := lower(t, "x > 3 and p", "x : 0..7")
+	assert.Equal(t, "x[2] and p", format(t, lowered.Expr))
+	assert.Equal(t, []string{"p", "x[2]", "x[1]", "x[0]"}, lowered.Vars)
+
+	lowered = lower(t, "x >= 2", "x : 0..2")
+	assert.Equal(t, "x[1] and (not x[1] or not x[0])", format(t, lowered.Expr))
+}
+
+func TestLowerErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		ranges   string
+		expected string
+	}{
+		{"x > 3", "", "1:1: 'x' needs a range, such as 'x : 0..7'"},
+		{"x > 3 and x", "x : 0..7", "'x' is used both as a truth value and as an integer"},
+		{"p or 10 / x > 1", "x : 0..3", "1:9: division by zero, at x = 0"},
+		{"x + y + z > 1", "x : 0..31, y : 0..31, z : 0..31", "1:1: 'x + y + z > 1' reads 15 bits, more than the 12 that can be enumerated"},
+	}
+	for _, test := range tests {
+		expr, err := boolean.ExprParser.ParseString("", test.input)
+		assert.NoError(t, err, test.input)
+		ranges, err := ParseRanges(test.ranges)
+		assert.NoError(t, err, test.ranges)
+		_, err = Lower(expr, ranges)
+		assert.EqualError(t, err, test.expected, test.input)
+	}
+}
+
+func TestParseRanges(t *testing.T) {
+	ranges, err := ParseRanges("x : -2..5, count:0..100")
+	assert.NoError(t, err)
+	assert.Equal(t, []Range{{"x", -2, 5}, {"count", 0, 100}}, ranges)
+	assert.Equal(t, 3, ranges[0].Width())
+	assert.Equal(t, 7, ranges[1].Width())
+
+	for input, expected := range map[string]string{
+		"x 0..7":             "invalid range 'x 0..7', expected 'name : lo..hi'",
+		"x : 5..2":           "range 'x : 5..2' is empty",
+		"x : a..2":           "invalid lower bound 'a' for 'x'",
+		"x : 0..1, x : 1..2": "'x' has two ranges",
+		"x : 0..70000":       "range 'x : 0..70000' is larger than 65536 values",
+	} {
+		_, err := ParseRanges(input)
+		assert.EqualError(t, err, expected, input)
+	}
+}
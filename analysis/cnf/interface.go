@@ -0,0 +1,307 @@
+package cnf
+
+import (
+	"fmt"
+	"strings"
+
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ir"
+)
+
+// Lit is a DIMACS literal: variable v is v and its negation is -v.
+type Lit int
+
+func (lit Lit) Var() int {
+	if lit < 0 {
+		return int(-lit)
+	}
+	return int(lit)
+}
+
+type Clause []Lit
+
+// CNF is a clause set over Vars, where Vars[v-1] names variable v. The
+// first Original variables are the free variables of the source
+// expression; the rest are auxiliary.
+type CNF struct {
+	Vars     []string
+	Original int
+	Clauses  []Clause
+}
+
+// Regd. Tseitin
+
+type encoder struct {
+	cnf  *CNF
+	vars map[string]Lit
+	top  Lit
+}
+
+// Tseitin encodes expr as a clause set that is satisfiable exactly when
+// expr is, introducing one auxiliary variable per binary operator, so the
+// result grows linearly even across chains of `<=>` and `xor`.
+func Tseitin(expr *booleanAst.Expr) (*CNF, error) {
+	e := encoder{cnf: &CNF{}, vars: map[string]Lit{}}
+	for _, name := range boolean.FreeVars(expr) {
+		e.vars[name] = e.fresh(name)
+	}
+	e.cnf.Original = len(e.cnf.Vars)
+	node, err := ir.Lower(expr)
+	if err != nil {
+		return nil, err
+	}
+	root, err := e.node(node)
+	if err != nil {
+		return nil, err
+	}
+	e.clause(root)
+	return e.cnf, nil
+}
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+func (e *encoder) fresh(name string) Lit {
+	e.cnf.Vars = append(e.cnf.Vars, name)
+	return Lit(len(e.cnf.Vars))
+}
+
+// aux names an auxiliary variable t[n] after its number, which the lexer
+// cannot produce, so it never shares a name with a source variable.
+func (e *encoder) aux() Lit {
+	return e.fresh(fmt.Sprintf("t[%d]", len(e.cnf.Vars)+1))
+}
+
+func (e *encoder) clause(lits ...Lit) {
+	e.cnf.Clauses = append(e.cnf.Clauses, Clause(lits))
+}
+
+func (e *encoder) constant(val bool) Lit {
+	if e.top == 0 {
+		e.top = e.aux()
+		e.clause(e.top)
+	}
+	if val {
+		return e.top
+	}
+	return -e.top
+}
+
+func (e *encoder) and(a Lit, b Lit) Lit {
+	x := e.aux()
+	e.clause(-x, a)
+	e.clause(-x, b)
+	e.clause(x, -a, -b)
+	return x
+}
+
+func (e *encoder) or(a Lit, b Lit) Lit {
+	x := e.aux()
+	e.clause(x, -a)
+	e.clause(x, -b)
+	e.clause(-x, a, b)
+	return x
+}
+
+func (e *encoder) xor(a Lit, b Lit) Lit {
+	x := e.aux()
+	e.clause(-x, a, b)
+	e.clause(-x, -a, -b)
+	e.clause(x, -a, b)
+	e.clause(x, a, -b)
+	return x
+}
+
+func (e *encoder) node(node ir.Node) (Lit, error) {
+	switch n := node.(type) {
+	case *ir.Const:
+		return e.constant(n.Value), nil
+	case *ir.Var:
+		return e.vars[n.Name], nil
+	case *ir.Cmp:
+		return 0, errorAt(n.Pos, "integer comparison '%s' must be lowered to bits first", arith.Format(n.Cmp))
+	case *ir.Degree:
+		return 0, errorAt(n.Pos, "truth degree '%s' needs a fuzzy logic", n.Text)
+	case *ir.Unary:
+		return e.unary(n)
+	case *ir.Binary:
+		return e.binary(n)
+	default:
+		return 0, fmt.Errorf("invalid boolean expression '%v'", node)
+	}
+}
+
+func (e *encoder) binary(n *ir.Binary) (Lit, error) {
+	left, err := e.node(n.X)
+	if err != nil {
+		return 0, err
+	}
+	right, err := e.node(n.Y)
+	if err != nil {
+		return 0, err
+	}
+	switch n.Op {
+	case ir.AND:
+		return e.and(left, right), nil
+	case ir.NAND:
+		return -e.and(left, right), nil
+	case ir.OR:
+		return e.or(left, right), nil
+	case ir.NOR:
+		return -e.or(left, right), nil
+	case ir.IMPLIES:
+		return e.or(-left, right), nil
+	case ir.IMPLIED_BY:
+		return e.or(left, -right), nil
+	case ir.INHIBITS:
+		return e.and(left, -right), nil
+	case ir.INHIBITED_BY:
+		return e.and(-left, right), nil
+	case ir.LEFT:
+		return left, nil
+	case ir.RIGHT:
+		return right, nil
+	case ir.NOT_LEFT:
+		return -left, nil
+	case ir.NOT_RIGHT:
+		return -right, nil
+	case ir.IFF:
+		return -e.xor(left, right), nil
+	case ir.XOR:
+		return e.xor(left, right), nil
+	default:
+		return 0, errorAt(n.OpPos, "invalid binary operation '%s'", n.Text)
+	}
+}
+
+func (e *encoder) unary(n *ir.Unary) (Lit, error) {
+	acc, err := e.node(n.X)
+	if err != nil {
+		return 0, err
+	}
+	switch n.Op {
+	case ir.NOT:
+		return -acc, nil
+	case ir.NULLIFY:
+		return e.constant(false), nil
+	case ir.TRUIFY:
+		return e.constant(true), nil
+	case ir.ID:
+		return acc, nil
+	default:
+		return 0, errorAt(n.Pos, "invalid unary operator '%s'", n.Text)
+	}
+}
+
+// Regd. Models
+
+// Model restricts an assignment to every variable of c, indexed by
+// variable number minus one, to the original variables.
+func (c *CNF) Model(assignment []bool) boolean.Env {
+	env := boolean.Env{}
+	for idx := 0; idx < c.Original; idx++ {
+		env[c.Vars[idx]] = assignment[idx]
+	}
+	return env
+}
+
+// Solve searches for a satisfying assignment with DPLL and unit
+// propagation. It is meant for checking encodings, not for large problems.
+func (c *CNF) Solve() ([]bool, bool) {
+	values := make([]int8, len(c.Vars)+1)
+	if !dpll(c.Clauses, values) {
+		return nil, false
+	}
+	assignment := make([]bool, len(c.Vars))
+	for idx := range assignment {
+		assignment[idx] = values[idx+1] > 0
+	}
+	return assignment, true
+}
+
+func litValue(values []int8, lit Lit) int8 {
+	if lit < 0 {
+		return -values[-lit]
+	}
+	return values[lit]
+}
+
+func assign(values []int8, lit Lit) {
+	if lit < 0 {
+		values[-lit] = -1
+	} else {
+		values[lit] = 1
+	}
+}
+
+func dpll(clauses []Clause, values []int8) bool {
+	trail := []int{}
+	undo := func() {
+		for _, v := range trail {
+			values[v] = 0
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, clause := range clauses {
+			satisfied := false
+			unassigned := []Lit{}
+			for _, lit := range clause {
+				switch litValue(values, lit) {
+				case 1:
+					satisfied = true
+				case 0:
+					unassigned = append(unassigned, lit)
+				}
+			}
+			if satisfied {
+				continue
+			}
+			if len(unassigned) == 0 {
+				undo()
+				return false
+			}
+			if len(unassigned) == 1 {
+				assign(values, unassigned[0])
+				trail = append(trail, unassigned[0].Var())
+				changed = true
+			}
+		}
+	}
+	for v := 1; v < len(values); v++ {
+		if values[v] != 0 {
+			continue
+		}
+		for _, lit := range []Lit{Lit(v), Lit(-v)} {
+			assign(values, lit)
+			if dpll(clauses, values) {
+				return true
+			}
+			values[v] = 0
+		}
+		undo()
+		return false
+	}
+	return true
+}
+
+// DIMACS renders c in the DIMACS CNF format read by external solvers,
+// naming every variable in a comment line.
+func (c *CNF) DIMACS() string {
+	var sb strings.Builder
+	for idx, name := range c.Vars {
+		fmt.Fprintf(&sb, "c %d %s\n", idx+1, name)
+	}
+	fmt.Fprintf(&sb, "p cnf %d %d\n", len(c.Vars), len(c.Clauses))
+	for _, clause := range c.Clauses {
+		for _, lit := range clause {
+			fmt.Fprintf(&sb, "%d ", lit)
+		}
+		sb.WriteString("0\n")
+	}
+	return sb.String()
+}
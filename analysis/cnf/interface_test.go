@@ -0,0 +1,144 @@
+package cnf
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	"github.com/stretchr/testify/assert"
+)
+
+var binops = []string{
+	lexer.AND_TEXT, lexer.AND_SYMB, lexer.NAND_TEXT, lexer.NAND_SYMB,
+	lexer.OR_TEXT, lexer.OR_SYMB, lexer.NOR_TEXT, lexer.NOR_SYMB,
+	lexer.XNOR_TEXT, lexer.IFF_TEXT, lexer.XNOR_SYMB, lexer.XOR_TEXT, lexer.XOR_SYMB,
+	lexer.IMPLIES_TEXT, lexer.IMPLIES_SYMB, lexer.IMPLIED_BY_TEXT, lexer.IMPLIED_BY_SYMB,
+	lexer.INHIBITS_TEXT, lexer.INHIBITS_SYMB, lexer.INHIBITED_BY_TEXT, lexer.INHIBITED_BY_SYMB,
+	lexer.LEFT_TEXT, lexer.LEFT_SYMB, lexer.RIGHT_TEXT, lexer.RIGHT_SYMB,
+	lexer.NOT_LEFT_TEXT, lexer.NOT_LEFT_SYMB, lexer.NOT_RIGHT_TEXT, lexer.NOT_RIGHT_SYMB,
+}
+
+var unops = []string{
+	lexer.NOT_TEXT, lexer.NOT_SYMB, lexer.NULLIFY_TEXT, lexer.TRUIFY_TEXT, lexer.ID_TEXT,
+}
+
+var atoms = []string{"a", "b", "c", "d", lexer.TRUE, lexer.FALSE}
+
+func randomFormula(rng *rand.Rand, depth int) string {
+	switch {
+	case depth == 0 || rng.Intn(4) == 0:
+		return atoms[rng.Intn(len(atoms))]
+	case rng.Intn(4) == 0:
+		return unops[rng.Intn(len(unops))] + " " + randomFormula(rng, depth-1)
+	default:
+		left := randomFormula(rng, depth-1)
+		right := randomFormula(rng, depth-1)
+		return "(" + left + " " + binops[rng.Intn(len(binops))] + " " + right + ")"
+	}
+}
+
+func bruteForceSatisfiable(t *testing.T, expr *booleanAst.Expr) bool {
+	vars := boolean.FreeVars(expr)
+	for bits := 0; bits < 1<<len(vars); bits++ {
+		env := boolean.Env{}
+		for idx, name := range vars {
+			env[name] = bits&(1<<idx) != 0
+		}
+		res := boolean.EvalExpr(expr, env)
+		assert.NoError(t, res.Err)
+		if res.Payload == types.Bool(true) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRandomFormulasAreEquisatisfiable(t *testing.T) {
+	rng := rand.New(rand.NewSource(26))
+	satisfiable := 0
+	for range 500 {
+		input := randomFormula(rng, 5)
+		expr, err := boolean.ExprParser.ParseString("", input)
+		assert.NoError(t, err, input)
+		c, err := Tseitin(expr)
+		assert.NoError(t, err)
+		assignment, ok := c.Solve()
+		assert.Equal(t, bruteForceSatisfiable(t, expr), ok, input)
+		if !ok {
+			continue
+		}
+		satisfiable++
+		res := boolean.EvalExpr(expr, c.Model(assignment))
+		assert.NoError(t, res.Err)
+		assert.Equal(t, types.Bool(true), res.Payload, input)
+	}
+	// Guard against a generator that only ever produces one kind of formula.
+	assert.Less(t, 100, satisfiable)
+	assert.Less(t, satisfiable, 490)
+}
+
+func TestXorChainIsLinear(t *testing.T) {
+	for _, n := range []int{4, 8, 16, 32} {
+		vars := []string{}
+		for idx := range n {
+			vars = append(vars, "x"+strings.Repeat("x", idx))
+		}
+		expr, err := boolean.ExprParser.ParseString("", strings.Join(vars, " <=> "))
+		assert.NoError(t, err)
+		c, err := Tseitin(expr)
+		assert.NoError(t, err)
+		assert.Equal(t, n, c.Original)
+		assert.Len(t, c.Vars, 2*n-1)
+		assert.Len(t, c.Clauses, 4*(n-1)+1)
+	}
+}
+
+func TestOriginalVarsComeFirst(t *testing.T) {
+	expr, err := boolean.ExprParser.ParseString("", "p and (q or not p)")
+	assert.NoError(t, err)
+	c, err := Tseitin(expr)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"p", "q", "t[3]", "t[4]"}, c.Vars)
+	assert.Equal(t, 2, c.Original)
+	assignment, ok := c.Solve()
+	assert.True(t, ok)
+	assert.Equal(t, boolean.Env{"p": true, "q": true}, c.Model(assignment))
+}
+
+func TestAuxiliaryNamesAreFresh(t *testing.T) {
+	expr, err := boolean.ExprParser.ParseString("", "_t3 and not q")
+	assert.NoError(t, err)
+	c, err := Tseitin(expr)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"_t3", "q", "t[3]"}, c.Vars)
+}
+
+func TestUnsatisfiable(t *testing.T) {
+	expr, err := boolean.ExprParser.ParseString("", "(p xor q) and (p iff q)")
+	assert.NoError(t, err)
+	c, err := Tseitin(expr)
+	assert.NoError(t, err)
+	_, ok := c.Solve()
+	assert.False(t, ok)
+}
+
+func TestDIMACS(t *testing.T) {
+	expr, err := boolean.ExprParser.ParseString("", "p and not q")
+	assert.NoError(t, err)
+	c, err := Tseitin(expr)
+	assert.NoError(t, err)
+	expected := `c 1 p
+c 2 q
+c 3 t[3]
+p cnf 3 4
+-3 1 0
+-3 -2 0
+3 -1 2 0
+3 0
+`
+	assert.Equal(t, expected, c.DIMACS())
+}
@@ -0,0 +1,552 @@
+package decision
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"acornlang.dev/lang/analysis/minimize"
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+)
+
+const (
+	MAX_CONDITIONS = 8
+	MAX_TREE_VARS  = 10
+)
+
+// Regd. Tables
+
+const (
+	CELL_TRUE  string = "T"
+	CELL_FALSE string = "F"
+	CELL_ANY   string = "-"
+
+	COLUMN_SEPARATOR string = "|"
+	OUTPUT_SEPARATOR string = "||"
+	ROW_SEPARATOR    string = ";"
+	COMMENT_PREFIX   string = "#"
+)
+
+type Cell int
+
+const (
+	ANY Cell = iota
+	YES
+	NO
+)
+
+func (c Cell) String() string {
+	switch c {
+	case YES:
+		return CELL_TRUE
+	case NO:
+		return CELL_FALSE
+	default:
+		return CELL_ANY
+	}
+}
+
+// Row is a rule: when every condition cell agrees with an assignment, the
+// row gives its outputs. Outputs may be `-` when the row does not care.
+type Row struct {
+	Line    int
+	Conds   []Cell
+	Outputs []Cell
+}
+
+// Table is read top to bottom and the first matching row wins.
+type Table struct {
+	Conditions []string
+	Outputs    []string
+	Rows       []Row
+}
+
+var name = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ParseTable reads a header of condition names, `||`, and output names,
+// then one row of `T`, `F` or `-` cells per line:
+//
+//	income_ok | flagged || approve
+//	T         | F       || T
+//	-         | T       || F
+//
+// Rows may also be separated by `;`, and lines starting with `#` are
+// comments. Errors name the line, and for a row also its number.
+func ParseTable(input string) (*Table, error) {
+	var table *Table
+	for idx, text := range strings.Split(input, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(text), COMMENT_PREFIX) {
+			continue
+		}
+		for _, part := range strings.Split(text, ROW_SEPARATOR) {
+			part = strings.TrimSpace(part)
+			if part == "" || strings.HasPrefix(part, COMMENT_PREFIX) {
+				continue
+			}
+			at := fmt.Sprintf("line %d", idx+1)
+			if table != nil {
+				at = fmt.Sprintf("line %d, row %d", idx+1, len(table.Rows)+1)
+			}
+			conds, outputs, ok := strings.Cut(part, OUTPUT_SEPARATOR)
+			if !ok {
+				return nil, fmt.Errorf("%s: expected '%s' between conditions and outputs", at, OUTPUT_SEPARATOR)
+			}
+			if table == nil {
+				table = &Table{Conditions: columns(conds), Outputs: columns(outputs)}
+				if err := table.checkHeader(at); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			row, err := table.parseRow(at, columns(conds), columns(outputs))
+			if err != nil {
+				return nil, err
+			}
+			row.Line = idx + 1
+			table.Rows = append(table.Rows, row)
+		}
+	}
+	if table == nil {
+		return nil, fmt.Errorf("empty decision table")
+	}
+	return table, nil
+}
+
+func columns(text string) []string {
+	acc := []string{}
+	for _, col := range strings.Split(text, COLUMN_SEPARATOR) {
+		acc = append(acc, strings.TrimSpace(col))
+	}
+	return acc
+}
+
+func (t *Table) checkHeader(at string) error {
+	if len(t.Conditions) > MAX_CONDITIONS {
+		return fmt.Errorf("%s: a decision table takes at most %d conditions, got %d", at, MAX_CONDITIONS, len(t.Conditions))
+	}
+	seen := map[string]bool{}
+	for _, col := range append(append([]string{}, t.Conditions...), t.Outputs...) {
+		if !name.MatchString(col) {
+			return fmt.Errorf("%s: invalid column name '%s'", at, col)
+		}
+		if seen[col] {
+			return fmt.Errorf("%s: column '%s' appears twice", at, col)
+		}
+		seen[col] = true
+	}
+	return nil
+}
+
+func (t *Table) parseRow(at string, conds []string, outputs []string) (Row, error) {
+	if len(conds) != len(t.Conditions) || len(outputs) != len(t.Outputs) {
+		return Row{}, fmt.Errorf(
+			"%s: expected %d conditions and %d outputs, got %d and %d",
+			at, len(t.Conditions), len(t.Outputs), len(conds), len(outputs),
+		)
+	}
+	row := Row{}
+	for _, text := range conds {
+		cell, err := parseCell(at, text)
+		if err != nil {
+			return Row{}, err
+		}
+		row.Conds = append(row.Conds, cell)
+	}
+	for _, text := range outputs {
+		cell, err := parseCell(at, text)
+		if err != nil {
+			return Row{}, err
+		}
+		row.Outputs = append(row.Outputs, cell)
+	}
+	return row, nil
+}
+
+func parseCell(at string, text string) (Cell, error) {
+	switch text {
+	case CELL_TRUE:
+		return YES, nil
+	case CELL_FALSE:
+		return NO, nil
+	case CELL_ANY:
+		return ANY, nil
+	default:
+		return ANY, fmt.Errorf("%s: invalid cell '%s', expected %s, %s or %s", at, text, CELL_TRUE, CELL_FALSE, CELL_ANY)
+	}
+}
+
+// Matches reports whether the row applies to an assignment, given as a
+// minterm with condition 0 in its most significant bit.
+func (r Row) Matches(minterm uint) bool {
+	for idx, cell := range r.Conds {
+		bit := minterm&(1<<(len(r.Conds)-1-idx)) != 0
+		if (cell == YES && !bit) || (cell == NO && bit) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Table) minterms() uint {
+	return 1 << len(t.Conditions)
+}
+
+func (t *Table) assignment(minterm uint) string {
+	acc := []string{}
+	for idx, cond := range t.Conditions {
+		cell := CELL_FALSE
+		if minterm&(1<<(len(t.Conditions)-1-idx)) != 0 {
+			cell = CELL_TRUE
+		}
+		acc = append(acc, cond+" = "+cell)
+	}
+	return strings.Join(acc, ", ")
+}
+
+// Regd. Checks
+
+type IssueKind int
+
+const (
+	INCOMPLETE IssueKind = iota
+	UNREACHABLE
+	CONFLICT
+)
+
+// Issue is a problem with a table; Rows are the 1-based rows involved.
+type Issue struct {
+	Kind IssueKind
+	Rows []int
+	Msg  string
+}
+
+func (i Issue) String() string {
+	return i.Msg
+}
+
+// Check finds the assignments no row covers, the rows that never win
+// because earlier rows cover them, and the pairs of rows that both cover
+// an assignment but disagree on an output.
+func (t *Table) Check() []Issue {
+	issues := []Issue{}
+	uncovered := []uint{}
+	for minterm := uint(0); minterm < t.minterms(); minterm++ {
+		if t.firstMatch(minterm) == -1 {
+			uncovered = append(uncovered, minterm)
+		}
+	}
+	if len(uncovered) > 0 {
+		issues = append(issues, Issue{
+			Kind: INCOMPLETE,
+			Msg: fmt.Sprintf(
+				"incomplete: no row covers %s (%d of %d assignments uncovered)",
+				t.assignment(uncovered[0]), len(uncovered), t.minterms(),
+			),
+		})
+	}
+	for idx := range t.Rows {
+		if covering, ok := t.shadowed(idx); ok {
+			verb := "covers"
+			if len(covering) > 1 {
+				verb = "cover"
+			}
+			issues = append(issues, Issue{
+				Kind: UNREACHABLE,
+				Rows: []int{idx + 1},
+				Msg:  fmt.Sprintf("row %d is unreachable, %s %s it", idx+1, rowList(covering), verb),
+			})
+		}
+		for later := idx + 1; later < len(t.Rows); later++ {
+			if msg, ok := t.conflict(idx, later); ok {
+				issues = append(issues, Issue{Kind: CONFLICT, Rows: []int{idx + 1, later + 1}, Msg: msg})
+			}
+		}
+	}
+	return issues
+}
+
+func (t *Table) firstMatch(minterm uint) int {
+	for idx, row := range t.Rows {
+		if row.Matches(minterm) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// shadowed lists the earlier rows that win every assignment row idx
+// covers, or reports false when row idx wins one.
+func (t *Table) shadowed(idx int) ([]int, bool) {
+	winners := map[int]bool{}
+	for minterm := uint(0); minterm < t.minterms(); minterm++ {
+		if !t.Rows[idx].Matches(minterm) {
+			continue
+		}
+		first := t.firstMatch(minterm)
+		if first == idx {
+			return nil, false
+		}
+		winners[first] = true
+	}
+	acc := []int{}
+	for row := 0; row < idx; row++ {
+		if winners[row] {
+			acc = append(acc, row+1)
+		}
+	}
+	return acc, true
+}
+
+func (t *Table) conflict(a int, b int) (string, bool) {
+	for minterm := uint(0); minterm < t.minterms(); minterm++ {
+		if !t.Rows[a].Matches(minterm) || !t.Rows[b].Matches(minterm) {
+			continue
+		}
+		for out, name := range t.Outputs {
+			x, y := t.Rows[a].Outputs[out], t.Rows[b].Outputs[out]
+			if x != ANY && y != ANY && x != y {
+				return fmt.Sprintf(
+					"rows %d and %d conflict on %s: %s is %s in row %d and %s in row %d",
+					a+1, b+1, t.assignment(minterm), name, x, a+1, y, b+1,
+				), true
+			}
+		}
+	}
+	return "", false
+}
+
+func rowList(rows []int) string {
+	if len(rows) == 1 {
+		return fmt.Sprintf("row %d", rows[0])
+	}
+	acc := []string{}
+	for _, row := range rows[:len(rows)-1] {
+		acc = append(acc, fmt.Sprint(row))
+	}
+	return fmt.Sprintf("rows %s and %d", strings.Join(acc, ", "), rows[len(rows)-1])
+}
+
+// Regd. Expressions
+
+// Exprs gives each output as a minimal sum of products over the
+// conditions. The first matching row decides an assignment; an output
+// is False where no row matches, and `-` lets it be either.
+func (t *Table) Exprs() ([]*booleanAst.Expr, error) {
+	acc := []*booleanAst.Expr{}
+	for out := range t.Outputs {
+		minterms, dontCares := []uint{}, []uint{}
+		for minterm := uint(0); minterm < t.minterms(); minterm++ {
+			first := t.firstMatch(minterm)
+			if first == -1 {
+				continue
+			}
+			switch t.Rows[first].Outputs[out] {
+			case YES:
+				minterms = append(minterms, minterm)
+			case ANY:
+				dontCares = append(dontCares, minterm)
+			}
+		}
+		source := sumOfProducts(t.Conditions, minimize.Minimize(len(t.Conditions), minterms, dontCares))
+		expr, err := boolean.ExprParser.ParseString("", source)
+		if err != nil {
+			return nil, err
+		}
+		acc = append(acc, expr)
+	}
+	return acc, nil
+}
+
+// sumOfProducts parenthesizes products for the language's
+// right-associative operators.
+func sumOfProducts(vars []string, imps []minimize.Implicant) string {
+	if len(imps) == 0 {
+		return lexer.FALSE
+	}
+	terms := []string{}
+	for _, imp := range imps {
+		literals := []string{}
+		for idx, name := range vars {
+			bit := uint(1) << (len(vars) - 1 - idx)
+			switch {
+			case imp.Mask&bit != 0:
+				continue
+			case imp.Value&bit != 0:
+				literals = append(literals, name)
+			default:
+				literals = append(literals, lexer.NOT_TEXT+" "+name)
+			}
+		}
+		term := strings.Join(literals, " "+lexer.AND_TEXT+" ")
+		switch {
+		case len(literals) == 0:
+			term = lexer.TRUE
+		case len(literals) > 1 && len(imps) > 1:
+			term = "(" + term + ")"
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " "+lexer.OR_TEXT+" ")
+}
+
+// Regd. Trees
+
+const (
+	IF_TEXT   string = "if"
+	THEN_TEXT string = "then"
+	ELSE_TEXT string = "else"
+)
+
+// Node is a decision tree: it tests Var and continues with Then or Else,
+// or is a leaf holding Leaf when Var is empty.
+type Node struct {
+	Var  string
+	Then *Node
+	Else *Node
+	Leaf bool
+}
+
+// Size is the number of tests in the tree.
+func (n *Node) Size() int {
+	if n.Var == "" {
+		return 0
+	}
+	return 1 + n.Then.Size() + n.Else.Size()
+}
+
+// String prints the tree as nested `if`, `then` and `else`, each branch
+// indented under its test.
+func (n *Node) String() string {
+	var sb strings.Builder
+	n.write(&sb, 0)
+	return sb.String()
+}
+
+func (n *Node) write(sb *strings.Builder, depth int) {
+	if n.Var == "" {
+		sb.WriteString(types.Bool(n.Leaf).String())
+		return
+	}
+	indent := strings.Repeat("  ", depth+1)
+	sb.WriteString(IF_TEXT + " " + n.Var + "\n" + indent + THEN_TEXT + " ")
+	n.Then.write(sb, depth+1)
+	sb.WriteString("\n" + indent + ELSE_TEXT + " ")
+	n.Else.write(sb, depth+1)
+}
+
+// Tree builds a decision tree for expr with as few tests as possible,
+// trying every order of the variables along every path. Ties go to the
+// variable that appears first in expr.
+func Tree(expr *booleanAst.Expr) (*Node, error) {
+	vars := boolean.FreeVars(expr)
+	if len(vars) > MAX_TREE_VARS {
+		return nil, fmt.Errorf("decision trees take at most %d variables, got %d", MAX_TREE_VARS, len(vars))
+	}
+	b := &treeBuilder{vars: vars, table: make([]bool, 1<<len(vars)), memo: map[int]treeCost{}}
+	for minterm := range b.table {
+		env := boolean.Env{}
+		for idx, name := range vars {
+			env[name] = minterm&(1<<(len(vars)-1-idx)) != 0
+		}
+		res := boolean.EvalExpr(expr, env)
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		b.table[minterm] = res.Payload == types.Bool(true)
+	}
+	start := 0
+	for idx := range vars {
+		start += FREE * pow3(idx)
+	}
+	return b.node(start), nil
+}
+
+// A cube fixes some variables and leaves the rest free; it is numbered
+// in base 3 with one digit per variable, variable i at 3^i.
+const (
+	ZERO = 0
+	ONE  = 1
+	FREE = 2
+)
+
+type treeCost struct {
+	size int
+	// split is the variable tested first, or -1 when the cube is a leaf.
+	split int
+	leaf  bool
+}
+
+type treeBuilder struct {
+	vars  []string
+	table []bool
+	memo  map[int]treeCost
+}
+
+func pow3(n int) int {
+	acc := 1
+	for ; n > 0; n-- {
+		acc *= 3
+	}
+	return acc
+}
+
+func digit(cube int, idx int) int {
+	return cube / pow3(idx) % 3
+}
+
+func (b *treeBuilder) cofactor(cube int, idx int, val int) int {
+	return cube + (val-FREE)*pow3(idx)
+}
+
+func (b *treeBuilder) cost(cube int) treeCost {
+	if cost, ok := b.memo[cube]; ok {
+		return cost
+	}
+	free := []int{}
+	minterm := 0
+	for idx := range b.vars {
+		switch digit(cube, idx) {
+		case FREE:
+			free = append(free, idx)
+		case ONE:
+			minterm |= 1 << (len(b.vars) - 1 - idx)
+		}
+	}
+	var cost treeCost
+	if len(free) == 0 {
+		cost = treeCost{split: -1, leaf: b.table[minterm]}
+	} else {
+		low := b.cost(b.cofactor(cube, free[0], ZERO))
+		high := b.cost(b.cofactor(cube, free[0], ONE))
+		if low.split == -1 && high.split == -1 && low.leaf == high.leaf {
+			cost = low
+		} else {
+			cost = treeCost{size: -1}
+			for _, idx := range free {
+				low := b.cost(b.cofactor(cube, idx, ZERO))
+				high := b.cost(b.cofactor(cube, idx, ONE))
+				size := 1 + low.size + high.size
+				if cost.size == -1 || size < cost.size {
+					cost = treeCost{size: size, split: idx}
+				}
+			}
+		}
+	}
+	b.memo[cube] = cost
+	return cost
+}
+
+func (b *treeBuilder) node(cube int) *Node {
+	cost := b.cost(cube)
+	if cost.split == -1 {
+		return &Node{Leaf: cost.leaf}
+	}
+	return &Node{
+		Var:  b.vars[cost.split],
+		Then: b.node(b.cofactor(cube, cost.split, ONE)),
+		Else: b.node(b.cofactor(cube, cost.split, ZERO)),
+	}
+}
@@ -0,0 +1,119 @@
+package decision
+
+import (
+	"testing"
+	"time"
+
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const loanTable = `
+# conditions || outputs
+income_ok | credit_ok | flagged || approve | review
+T         | T         | F       || T       | F
+-         | -         | T       || F       | T
+T         | F         | -       || F       | -
+`
+
+func TestTableExprs(t *testing.T) {
+	table, err := ParseTable(loanTable)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"income_ok", "credit_ok", "flagged"}, table.Conditions)
+	assert.Equal(t, 5, table.Rows[1].Line)
+	exprs, err := table.Exprs()
+	assert.NoError(t, err)
+	// Every assignment a row decides keeps its output.
+	for minterm := uint(0); minterm < 8; minterm++ {
+		first := table.firstMatch(minterm)
+		env := boolean.Env{
+			"income_ok": minterm&4 != 0,
+			"credit_ok": minterm&2 != 0,
+			"flagged":   minterm&1 != 0,
+		}
+		for out, expr := range exprs {
+			got := boolean.EvalExpr(expr, env).Payload == types.Bool(true)
+			switch {
+			case first == -1:
+				assert.False(t, got, "%s at %03b", table.Outputs[out], minterm)
+			case table.Rows[first].Outputs[out] != ANY:
+				assert.Equal(t, table.Rows[first].Outputs[out] == YES, got, "%s at %03b", table.Outputs[out], minterm)
+			}
+		}
+	}
+}
+
+func TestRowLines(t *testing.T) {
+	table, err := ParseTable("a || out; T || T\nF || F; - || F")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 2}, []int{table.Rows[0].Line, table.Rows[1].Line, table.Rows[2].Line})
+}
+
+func TestTableChecks(t *testing.T) {
+	table, err := ParseTable("a | b || out; T | - || T; F | T || F; T | T || F; - | T || T")
+	assert.NoError(t, err)
+	expected := []string{
+		"incomplete: no row covers a = F, b = F (1 of 4 assignments uncovered)",
+		"rows 1 and 3 conflict on a = T, b = T: out is T in row 1 and F in row 3",
+		"rows 2 and 4 conflict on a = F, b = T: out is F in row 2 and T in row 4",
+		"rows 3 and 4 conflict on a = T, b = T: out is F in row 3 and T in row 4",
+		"row 3 is unreachable, row 1 covers it",
+		"row 4 is unreachable, rows 1 and 2 cover it",
+	}
+	actual := []string{}
+	for _, issue := range table.Check() {
+		actual = append(actual, issue.String())
+	}
+	assert.ElementsMatch(t, expected, actual)
+}
+
+func TestTableErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "empty decision table"},
+		{"a | b", "line 1: expected '||' between conditions and outputs"},
+		{"a | a || out", "line 1: column 'a' appears twice"},
+		{"a || out\nT | F || T", "line 2, row 1: expected 1 conditions and 1 outputs, got 2 and 1"},
+		{"a || out\nyes || T", "line 2, row 1: invalid cell 'yes', expected T, F or -"},
+		{"a || out; T || F; yes || T", "line 1, row 2: invalid cell 'yes', expected T, F or -"},
+		{"a || out\n# T; F\nT || F; F\n", "line 3, row 2: expected '||' between conditions and outputs"},
+		{"a || 2out", "line 1: invalid column name '2out'"},
+	}
+	for _, test := range tests {
+		_, err := ParseTable(test.input)
+		assert.EqualError(t, err, test.expected, test.input)
+	}
+}
+
+func TestTree(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		size     int
+	}{
+		{"p or not p", "True", 0},
+		{"p and q", "if p\n  then if q\n    then True\n    else False\n  else False", 2},
+		// Testing s first needs one test on the left and two on the right.
+		{"(p and q) or s", "if s\n  then True\n  else if p\n    then if q\n      then True\n      else False\n    else False", 3},
+	}
+	for _, test := range tests {
+		expr, err := boolean.ExprParser.ParseString("", test.input)
+		assert.NoError(t, err)
+		tree, err := Tree(expr)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, tree.String(), test.input)
+		assert.Equal(t, test.size, tree.Size(), test.input)
+	}
+}
+
+func TestTreeIsFastAtTheLimit(t *testing.T) {
+	expr, err := boolean.ExprParser.ParseString("", "(a xor b xor c xor d xor e) and (g or h or i or j or k)")
+	assert.NoError(t, err)
+	start := time.Now()
+	_, err = Tree(expr)
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
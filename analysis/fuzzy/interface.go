@@ -0,0 +1,220 @@
+package fuzzy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ir"
+)
+
+// Family selects the t-norm every connective is derived from.
+type Family int
+
+const (
+	Godel Family = iota
+	Product
+	Lukasiewicz
+)
+
+var familyNames = map[Family]string{
+	Godel:       "godel",
+	Product:     "product",
+	Lukasiewicz: "lukasiewicz",
+}
+
+func (f Family) String() string {
+	return familyNames[f]
+}
+
+func ParseFamily(name string) (Family, error) {
+	switch strings.ToLower(name) {
+	case "godel", "gödel", "goedel", "min":
+		return Godel, nil
+	case "product", "goguen":
+		return Product, nil
+	case "lukasiewicz", "łukasiewicz":
+		return Lukasiewicz, nil
+	default:
+		return Godel, fmt.Errorf("unknown t-norm family '%s'", name)
+	}
+}
+
+// T is the t-norm, the conjunction of the family.
+func (f Family) T(a float64, b float64) float64 {
+	switch f {
+	case Product:
+		return a * b
+	case Lukasiewicz:
+		return math.Max(0, a+b-1)
+	default:
+		return math.Min(a, b)
+	}
+}
+
+// S is the t-conorm dual to T under the standard negation.
+func (f Family) S(a float64, b float64) float64 {
+	return 1 - f.T(1-a, 1-b)
+}
+
+// I is the residuum of T, the family's implication.
+func (f Family) I(a float64, b float64) float64 {
+	if a <= b {
+		return 1
+	}
+	switch f {
+	case Product:
+		return b / a
+	case Lukasiewicz:
+		return 1 - a + b
+	default:
+		return b
+	}
+}
+
+// Binary applies a binary operator. Conjunction, disjunction and
+// implication come from T, S and I; `iff` is the biresiduum
+// T(I(a, b), I(b, a)), `inhibits` is T(a, 1-b), and the negated forms
+// apply the standard negation 1-x.
+func (f Family) Binary(op ir.Op, a float64, b float64) (float64, bool) {
+	switch op {
+	case ir.AND:
+		return f.T(a, b), true
+	case ir.NAND:
+		return 1 - f.T(a, b), true
+	case ir.OR:
+		return f.S(a, b), true
+	case ir.NOR:
+		return 1 - f.S(a, b), true
+	case ir.IMPLIES:
+		return f.I(a, b), true
+	case ir.IMPLIED_BY:
+		return f.I(b, a), true
+	case ir.INHIBITS:
+		return f.T(a, 1-b), true
+	case ir.INHIBITED_BY:
+		return f.T(1-a, b), true
+	case ir.LEFT:
+		return a, true
+	case ir.RIGHT:
+		return b, true
+	case ir.NOT_LEFT:
+		return 1 - a, true
+	case ir.NOT_RIGHT:
+		return 1 - b, true
+	case ir.IFF:
+		return f.T(f.I(a, b), f.I(b, a)), true
+	case ir.XOR:
+		return 1 - f.T(f.I(a, b), f.I(b, a)), true
+	default:
+		return 0, false
+	}
+}
+
+// Regd. Evaluation
+
+type Env map[string]float64
+
+func errorEvalResult(pos types.Position, format string, args ...any) boolean.EvalResult {
+	return boolean.EvalResult{
+		Pos:     pos,
+		Payload: types.Degree(0),
+		Err:     fmt.Errorf(format, args...),
+	}
+}
+
+func successEvalResult(pos types.Position, payload float64) boolean.EvalResult {
+	return boolean.EvalResult{
+		Pos:     pos,
+		Payload: types.Degree(payload),
+		Err:     nil,
+	}
+}
+
+func degree(res boolean.EvalResult) float64 {
+	return float64(res.Payload.(types.Degree))
+}
+
+// EvalExpr evaluates expr with every value a truth degree: True is 1,
+// False is 0 and variables take their degree from env.
+func EvalExpr(expr *booleanAst.Expr, env Env, f Family) boolean.EvalResult {
+	node, err := ir.Lower(expr)
+	if err != nil {
+		var lowerErr *ir.Error
+		if errors.As(err, &lowerErr) {
+			return errorEvalResult(lowerErr.Pos, "%s", lowerErr.Msg)
+		}
+		return errorEvalResult(types.Position{}, "%s", err.Error())
+	}
+	return Eval(node, env, f)
+}
+
+func Eval(node ir.Node, env Env, f Family) boolean.EvalResult {
+	switch n := node.(type) {
+	case *ir.Const:
+		if n.Value {
+			return successEvalResult(n.Pos, 1)
+		}
+		return successEvalResult(n.Pos, 0)
+	case *ir.Var:
+		val, ok := env[n.Name]
+		if !ok {
+			return errorEvalResult(n.Pos, "unbound variable '%s'", n.Name)
+		}
+		return successEvalResult(n.Pos, val)
+	case *ir.Degree:
+		val, err := strconv.ParseFloat(n.Text, 64)
+		if err != nil || val < 0 || 1 < val {
+			return errorEvalResult(n.Pos, "truth degree '%s' is outside [0, 1]", n.Text)
+		}
+		return successEvalResult(n.Pos, val)
+	case *ir.Cmp:
+		val, err := arith.EvalComparison(n.Cmp, nil)
+		if err != nil {
+			return errorEvalResult(n.Pos, "%s", err.Error())
+		}
+		if val {
+			return successEvalResult(n.Pos, 1)
+		}
+		return successEvalResult(n.Pos, 0)
+	case *ir.Unary:
+		res := Eval(n.X, env, f)
+		if res.Err != nil {
+			return res
+		}
+		switch n.Op {
+		case ir.NOT:
+			return successEvalResult(n.Pos, 1-degree(res))
+		case ir.NULLIFY:
+			return successEvalResult(n.Pos, 0)
+		case ir.TRUIFY:
+			return successEvalResult(n.Pos, 1)
+		case ir.ID:
+			return successEvalResult(n.Pos, degree(res))
+		default:
+			return errorEvalResult(n.Pos, "invalid unary operator '%s'", n.Text)
+		}
+	case *ir.Binary:
+		left := Eval(n.X, env, f)
+		if left.Err != nil {
+			return left
+		}
+		right := Eval(n.Y, env, f)
+		if right.Err != nil {
+			return right
+		}
+		acc, ok := f.Binary(n.Op, degree(left), degree(right))
+		if !ok {
+			return errorEvalResult(n.OpPos, "invalid binary operation '%s'", n.Text)
+		}
+		return successEvalResult(n.Pos, acc)
+	default:
+		return errorEvalResult(types.Position{}, "invalid boolean expression '%v'", node)
+	}
+}
@@ -0,0 +1,98 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func evalString(t *testing.T, input string, env Env, f Family) boolean.EvalResult {
+	expr, err := boolean.ExprParser.ParseString("", input)
+	assert.NoError(t, err)
+	return EvalExpr(expr, env, f)
+}
+
+func TestFamilies(t *testing.T) {
+	env := Env{"p": 0.7, "q": 0.4}
+	tests := []struct {
+		input    string
+		expected [3]float64
+	}{
+		{"p and q", [3]float64{0.4, 0.28, 0.1}},
+		{"p or q", [3]float64{0.7, 0.82, 1}},
+		{"p implies q", [3]float64{0.4, 0.4 / 0.7, 0.7}},
+		{"q implies p", [3]float64{1, 1, 1}},
+		{"p iff q", [3]float64{0.4, 0.4 / 0.7, 0.7}},
+		{"p xor q", [3]float64{0.6, 1 - 0.4/0.7, 0.3}},
+		{"p inhibits q", [3]float64{0.6, 0.42, 0.3}},
+		{"p nand q", [3]float64{0.6, 0.72, 0.9}},
+		{"p not right q", [3]float64{0.6, 0.6, 0.6}},
+		{"not p", [3]float64{0.3, 0.3, 0.3}},
+		{"p and 0.5", [3]float64{0.5, 0.35, 0.2}},
+	}
+	for _, test := range tests {
+		for idx, f := range []Family{Godel, Product, Lukasiewicz} {
+			res := evalString(t, test.input, env, f)
+			assert.NoError(t, res.Err)
+			assert.InDelta(t, test.expected[idx], float64(res.Payload.(types.Degree)), 1e-9, "%s in %s", test.input, f)
+		}
+	}
+}
+
+func TestCrispValuesAgreeWithBooleanEvaluation(t *testing.T) {
+	ops := []string{
+		"and", "nand", "or", "nor", "xor", "iff", "implies", "is implied by",
+		"inhibits", "is inhibited by", "left", "right", "not left", "not right",
+	}
+	for _, op := range ops {
+		for _, f := range []Family{Godel, Product, Lukasiewicz} {
+			for _, p := range []bool{false, true} {
+				for _, q := range []bool{false, true} {
+					expr, err := boolean.ExprParser.ParseString("", "p "+op+" q")
+					assert.NoError(t, err)
+					crisp := boolean.EvalExpr(expr, boolean.Env{"p": p, "q": q})
+					env := Env{"p": 0, "q": 0}
+					if p {
+						env["p"] = 1
+					}
+					if q {
+						env["q"] = 1
+					}
+					res := EvalExpr(expr, env, f)
+					assert.NoError(t, res.Err)
+					expected := types.Degree(0)
+					if crisp.Payload == types.Bool(true) {
+						expected = 1
+					}
+					assert.Equal(t, expected, res.Payload, "%s in %s", op, f)
+				}
+			}
+		}
+	}
+}
+
+func TestParseFamily(t *testing.T) {
+	f, err := ParseFamily("Łukasiewicz")
+	assert.NoError(t, err)
+	assert.Equal(t, Lukasiewicz, f)
+	_, err = ParseFamily("hamacher")
+	assert.EqualError(t, err, "unknown t-norm family 'hamacher'")
+}
+
+func TestEvalFail(t *testing.T) {
+	res := evalString(t, "True and 1.5", Env{}, Godel)
+	assert.EqualError(t, res.Err, "truth degree '1.5' is outside [0, 1]")
+	res = evalString(t, "p or q", Env{"p": 1}, Godel)
+	assert.EqualError(t, res.Err, "unbound variable 'q'")
+	res = evalString(t, "□p", Env{"p": 1}, Godel)
+	assert.EqualError(t, res.Err, "invalid unary operator '□'")
+}
+
+func TestBooleanEvaluationRejectsDegrees(t *testing.T) {
+	expr, err := boolean.ExprParser.ParseString("", "True and 0.5")
+	assert.NoError(t, err)
+	res := boolean.EvalExpr(expr, boolean.Env{})
+	assert.EqualError(t, res.Err, "truth degree '0.5' needs a fuzzy logic")
+}
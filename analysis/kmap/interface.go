@@ -0,0 +1,253 @@
+package kmap
+
+import (
+	"fmt"
+	"strings"
+
+	"acornlang.dev/lang/analysis/minimize"
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+)
+
+const (
+	MIN_VARS = 2
+	MAX_VARS = 6
+)
+
+type Group struct {
+	Implicant minimize.Implicant
+	Term      string
+}
+
+// KMap is a Karnaugh map. The first len(RowVars) variables select the row
+// and the rest select the column; Rows and Cols hold the Gray code of each
+// row and column in display order.
+type KMap struct {
+	Vars    []string
+	RowVars []string
+	ColVars []string
+	Rows    []uint
+	Cols    []uint
+	Cells   [][]bool
+	Groups  []Group
+}
+
+// Line is one line of a rendered map. Marks holds, for every rune of Text,
+// the index of the group it belongs to or -1.
+type Line struct {
+	Text  string
+	Marks []int
+}
+
+func Build(expr *booleanAst.Expr) (*KMap, error) {
+	vars := boolean.FreeVars(expr)
+	if len(vars) < MIN_VARS || MAX_VARS < len(vars) {
+		return nil, fmt.Errorf(
+			"Karnaugh maps need %d to %d variables, got %d",
+			MIN_VARS,
+			MAX_VARS,
+			len(vars),
+		)
+	}
+	rowCount := len(vars) / 2
+	m := &KMap{
+		Vars:    vars,
+		RowVars: vars[:rowCount],
+		ColVars: vars[rowCount:],
+		Rows:    grayCodes(rowCount),
+		Cols:    grayCodes(len(vars) - rowCount),
+	}
+	minterms := []uint{}
+	for minterm := uint(0); minterm < 1<<len(vars); minterm++ {
+		env := boolean.Env{}
+		for idx, name := range vars {
+			env[name] = minterm&(1<<(len(vars)-1-idx)) != 0
+		}
+		res := boolean.EvalExpr(expr, env)
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		if res.Payload == types.Bool(true) {
+			minterms = append(minterms, minterm)
+		}
+	}
+	m.Cells = make([][]bool, len(m.Rows))
+	for row, rowCode := range m.Rows {
+		m.Cells[row] = make([]bool, len(m.Cols))
+		for col, colCode := range m.Cols {
+			m.Cells[row][col] = contains(minterms, m.minterm(rowCode, colCode))
+		}
+	}
+	for _, imp := range minimize.Minimize(len(vars), minterms, nil) {
+		m.Groups = append(m.Groups, Group{Implicant: imp, Term: term(vars, imp)})
+	}
+	return m, nil
+}
+
+func grayCodes(width int) []uint {
+	acc := make([]uint, 1<<width)
+	for idx := range acc {
+		acc[idx] = uint(idx) ^ uint(idx)>>1
+	}
+	return acc
+}
+
+func contains(minterms []uint, minterm uint) bool {
+	for _, candidate := range minterms {
+		if candidate == minterm {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *KMap) minterm(rowCode uint, colCode uint) uint {
+	return rowCode<<len(m.ColVars) | colCode
+}
+
+func term(vars []string, imp minimize.Implicant) string {
+	literals := []string{}
+	for idx, name := range vars {
+		bit := uint(1) << (len(vars) - 1 - idx)
+		switch {
+		case imp.Mask&bit != 0:
+			continue
+		case imp.Value&bit != 0:
+			literals = append(literals, name)
+		default:
+			literals = append(literals, lexer.NOT_SYMB+name)
+		}
+	}
+	if len(literals) == 0 {
+		return lexer.TRUE
+	}
+	return strings.Join(literals, " "+lexer.AND_SYMB+" ")
+}
+
+// GroupsAt returns the indices of the groups covering a cell.
+func (m *KMap) GroupsAt(row int, col int) []int {
+	minterm := m.minterm(m.Rows[row], m.Cols[col])
+	acc := []int{}
+	for idx, group := range m.Groups {
+		if group.Implicant.Covers(minterm) {
+			acc = append(acc, idx)
+		}
+	}
+	return acc
+}
+
+// Minimal is the sum of the chosen groups, parenthesized for the
+// language's right-associative operators.
+func (m *KMap) Minimal() string {
+	if len(m.Groups) == 0 {
+		return lexer.FALSE
+	}
+	terms := []string{}
+	for _, group := range m.Groups {
+		if len(m.Groups) > 1 && strings.Contains(group.Term, lexer.AND_SYMB) {
+			terms = append(terms, "("+group.Term+")")
+		} else {
+			terms = append(terms, group.Term)
+		}
+	}
+	return strings.Join(terms, " "+lexer.OR_SYMB+" ")
+}
+
+// Regd. Rendering
+
+func groupLetter(idx int) string {
+	return string(rune('A' + idx%26))
+}
+
+func joinVars(vars []string) string {
+	for _, name := range vars {
+		if len(name) > 1 {
+			return strings.Join(vars, ",")
+		}
+	}
+	return strings.Join(vars, "")
+}
+
+func code(val uint, width int) string {
+	return fmt.Sprintf("%0*b", width, val)
+}
+
+type lineBuilder struct {
+	text  strings.Builder
+	marks []int
+}
+
+func (lb *lineBuilder) write(text string, mark int) {
+	lb.text.WriteString(text)
+	for range text {
+		lb.marks = append(lb.marks, mark)
+	}
+}
+
+// pad writes spaces up to a column, counted in runes.
+func (lb *lineBuilder) pad(width int) {
+	for len(lb.marks) < width {
+		lb.write(" ", -1)
+	}
+}
+
+func (lb *lineBuilder) line() Line {
+	return Line{Text: lb.text.String(), Marks: lb.marks}
+}
+
+func (m *KMap) Lines() []Line {
+	corner := joinVars(m.RowVars) + `\` + joinVars(m.ColVars)
+	rowHeader := max(len(corner), len(m.RowVars)) + 2
+	cellWidth := len(m.ColVars) + 2
+	for row := range m.Rows {
+		for col := range m.Cols {
+			cellWidth = max(cellWidth, len(m.GroupsAt(row, col))+3)
+		}
+	}
+
+	lines := []Line{}
+	header := lineBuilder{}
+	header.write(corner, -1)
+	for idx, colCode := range m.Cols {
+		header.pad(rowHeader + idx*cellWidth)
+		header.write(code(colCode, len(m.ColVars)), -1)
+	}
+	lines = append(lines, header.line())
+	for row, rowCode := range m.Rows {
+		lb := lineBuilder{}
+		lb.pad(rowHeader - 2 - len(m.RowVars))
+		lb.write(code(rowCode, len(m.RowVars)), -1)
+		for col := range m.Cols {
+			lb.pad(rowHeader + col*cellWidth)
+			groups := m.GroupsAt(row, col)
+			if !m.Cells[row][col] {
+				lb.write("0", -1)
+				continue
+			}
+			lb.write("1", groups[0])
+			for _, idx := range groups {
+				lb.write(groupLetter(idx), idx)
+			}
+		}
+		lines = append(lines, lb.line())
+	}
+	for idx, group := range m.Groups {
+		lb := lineBuilder{}
+		lb.write(groupLetter(idx), idx)
+		lb.write(": "+group.Term, -1)
+		lines = append(lines, lb.line())
+	}
+	minimal := lineBuilder{}
+	minimal.write("= "+m.Minimal(), -1)
+	return append(lines, minimal.line())
+}
+
+func (m *KMap) String() string {
+	acc := []string{}
+	for _, line := range m.Lines() {
+		acc = append(acc, line.Text)
+	}
+	return strings.Join(acc, "\n") + "\n"
+}
@@ -0,0 +1,90 @@
+package kmap
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"acornlang.dev/lang/parser/boolean"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildFromString(t *testing.T, input string) (*KMap, error) {
+	expr, err := boolean.ExprParser.ParseString("", input)
+	assert.NoError(t, err)
+	return Build(expr)
+}
+
+func TestTwoVariables(t *testing.T) {
+	m, err := buildFromString(t, "a or b")
+	assert.NoError(t, err)
+	expected := `a\b  0    1
+  0  0    1B
+  1  1A   1AB
+A: a
+B: b
+= a \/ b
+`
+	assert.Equal(t, expected, m.String())
+}
+
+func TestFourVariablesWrapAround(t *testing.T) {
+	m, err := buildFromString(t, "(b xnor d) and (a or c or b)")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "d", "a", "c"}, m.Vars)
+	assert.Equal(t, []uint{0, 1, 3, 2}, m.Rows)
+	expected := `bd\ac  00   01   11   10
+   00  0    1C   1BC  1B
+   01  0    0    0    0
+   11  1A   1A   1A   1A
+   10  0    0    0    0
+A: b /\ d
+B: ~b /\ ~d /\ a
+C: ~b /\ ~d /\ c
+= (b /\ d) \/ (~b /\ ~d /\ a) \/ (~b /\ ~d /\ c)
+`
+	assert.Equal(t, expected, m.String())
+}
+
+func TestMarksMatchLetters(t *testing.T) {
+	m, err := buildFromString(t, "a or b")
+	assert.NoError(t, err)
+	for _, line := range m.Lines() {
+		assert.Len(t, line.Marks, utf8.RuneCountInString(line.Text))
+	}
+	last := m.Lines()[2]
+	assert.Equal(t, "  1  1A   1AB", last.Text)
+	assert.Equal(t, []int{-1, -1, -1, -1, -1, 0, 0, -1, -1, -1, 0, 0, 1}, last.Marks)
+}
+
+func TestSixVariables(t *testing.T) {
+	m, err := buildFromString(t, "a and b and c and d and e and g")
+	assert.NoError(t, err)
+	assert.Len(t, m.Cells, 8)
+	assert.Len(t, m.Cells[0], 8)
+	assert.True(t, m.Cells[5][5])
+	assert.Equal(t, "a /\\ b /\\ c /\\ d /\\ e /\\ g", m.Minimal())
+}
+
+func TestConstantFunctions(t *testing.T) {
+	m, err := buildFromString(t, "a and not a and b")
+	assert.NoError(t, err)
+	assert.Empty(t, m.Groups)
+	assert.Equal(t, "False", m.Minimal())
+	m, err = buildFromString(t, "a or not a or b")
+	assert.NoError(t, err)
+	assert.Equal(t, "True", m.Minimal())
+}
+
+func TestVariableCountFail(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a", "Karnaugh maps need 2 to 6 variables, got 1"},
+		{"a and b and c and d and e and g and h", "Karnaugh maps need 2 to 6 variables, got 7"},
+	}
+	for _, test := range tests {
+		_, err := buildFromString(t, test.input)
+		assert.EqualError(t, err, test.expected)
+	}
+}
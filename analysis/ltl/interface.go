@@ -0,0 +1,370 @@
+package ltl
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ir"
+	participleLexer "github.com/alecthomas/participle/v2/lexer"
+)
+
+// Trace is a finite sequence of states, one assignment per step.
+type Trace []boolean.Env
+
+type Result struct {
+	Holds bool
+	// Step is where the property is first violated, or -1 when it holds.
+	Step int
+}
+
+// Check evaluates expr at the first step of trace under finite-trace
+// semantics: `next` is strong, so it is false at the last step, and
+// `globally`, `finally`, `until` and `release` only range over the steps
+// the trace has. When the property fails, Step points below any outer
+// `globally` or `and` to the step that breaks it.
+func Check(expr *booleanAst.Expr, trace Trace) (Result, error) {
+	if len(trace) == 0 {
+		return Result{}, errors.New("empty trace")
+	}
+	node, err := ir.Lower(expr)
+	if err != nil {
+		return Result{}, err
+	}
+	e := evaluator{trace: trace}
+	values, err := e.node(node)
+	if err != nil {
+		return Result{}, err
+	}
+	if values[0] {
+		return Result{Holds: true, Step: -1}, nil
+	}
+	step, err := e.blame(node, 0)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Holds: false, Step: step}, nil
+}
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+type evaluator struct {
+	trace Trace
+}
+
+func (e *evaluator) steps() int {
+	return len(e.trace)
+}
+
+func (e *evaluator) fill(val bool) []bool {
+	acc := make([]bool, e.steps())
+	for idx := range acc {
+		acc[idx] = val
+	}
+	return acc
+}
+
+// node gives the value of node at every step.
+func (e *evaluator) node(node ir.Node) ([]bool, error) {
+	switch n := node.(type) {
+	case *ir.Const:
+		return e.fill(n.Value), nil
+	case *ir.Var:
+		acc := make([]bool, e.steps())
+		for step, state := range e.trace {
+			val, ok := state[n.Name]
+			if !ok {
+				return nil, errorAt(n.Pos, "step %d has no value for '%s'", step, n.Name)
+			}
+			acc[step] = val
+		}
+		return acc, nil
+	case *ir.Cmp:
+		return nil, errorAt(n.Pos, "integer comparison '%s' cannot be checked on a trace of truth values", arith.Format(n.Cmp))
+	case *ir.Degree:
+		return nil, errorAt(n.Pos, "truth degree '%s' needs a fuzzy logic", n.Text)
+	case *ir.Unary:
+		return e.unary(n)
+	case *ir.Binary:
+		return e.binary(n)
+	default:
+		return nil, fmt.Errorf("invalid boolean expression '%v'", node)
+	}
+}
+
+func (e *evaluator) binary(n *ir.Binary) ([]bool, error) {
+	left, err := e.node(n.X)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.node(n.Y)
+	if err != nil {
+		return nil, err
+	}
+	steps := e.steps()
+	acc := make([]bool, steps)
+	switch n.Op {
+	case ir.UNTIL:
+		acc[steps-1] = right[steps-1]
+		for idx := steps - 2; idx >= 0; idx-- {
+			acc[idx] = right[idx] || left[idx] && acc[idx+1]
+		}
+		return acc, nil
+	case ir.RELEASE:
+		acc[steps-1] = right[steps-1]
+		for idx := steps - 2; idx >= 0; idx-- {
+			acc[idx] = right[idx] && (left[idx] || acc[idx+1])
+		}
+		return acc, nil
+	}
+	if _, ok := n.Op.Table(); !ok {
+		return nil, errorAt(n.OpPos, "invalid binary operation '%s'", n.Text)
+	}
+	for idx := range acc {
+		acc[idx] = n.Op.Apply(left[idx], right[idx])
+	}
+	return acc, nil
+}
+
+func (e *evaluator) unary(n *ir.Unary) ([]bool, error) {
+	in, err := e.node(n.X)
+	if err != nil {
+		return nil, err
+	}
+	steps := e.steps()
+	acc := make([]bool, steps)
+	switch n.Op {
+	case ir.NEXT:
+		for step := 0; step < steps-1; step++ {
+			acc[step] = in[step+1]
+		}
+	case ir.GLOBALLY:
+		acc[steps-1] = in[steps-1]
+		for step := steps - 2; step >= 0; step-- {
+			acc[step] = in[step] && acc[step+1]
+		}
+	case ir.FINALLY:
+		acc[steps-1] = in[steps-1]
+		for step := steps - 2; step >= 0; step-- {
+			acc[step] = in[step] || acc[step+1]
+		}
+	default:
+		for step := range acc {
+			acc[step] = n.Op.ApplyUnary(in[step])
+		}
+	}
+	return acc, nil
+}
+
+// Regd. Blame
+
+// blame returns the step responsible for node being false at step.
+func (e *evaluator) blame(node ir.Node, step int) (int, error) {
+	switch n := node.(type) {
+	case *ir.Binary:
+		if n.Op != ir.AND {
+			return step, nil
+		}
+		left, err := e.node(n.X)
+		if err != nil {
+			return 0, err
+		}
+		if !left[step] {
+			return e.blame(n.X, step)
+		}
+		return e.blame(n.Y, step)
+	case *ir.Unary:
+		switch n.Op {
+		case ir.GLOBALLY:
+			values, err := e.node(n.X)
+			if err != nil {
+				return 0, err
+			}
+			for idx := step; idx < len(values); idx++ {
+				if !values[idx] {
+					return e.blame(n.X, idx)
+				}
+			}
+		case ir.NEXT:
+			if step+1 < e.steps() {
+				return e.blame(n.X, step+1)
+			}
+		case ir.ID:
+			return e.blame(n.X, step)
+		}
+	}
+	return step, nil
+}
+
+// Regd. Letters
+
+var letters = map[string]string{
+	"X": lexer.NEXT_SYMB,
+	"G": lexer.GLOBALLY_SYMB,
+	"F": lexer.FINALLY_SYMB,
+	"U": lexer.UNTIL_SYMB,
+	"R": lexer.RELEASE_SYMB,
+}
+
+// FromLetters rewrites the one-letter spellings of the literature, X, G and
+// F before an operand and U and R between two, into the temporal symbols,
+// so `G (req => F ack)` reads as `□ (req => ◇ ack)`. Anywhere else the
+// letters stay names: `F and X` is unchanged. Each symbol is one character,
+// so error columns still point into input. Input the lexer rejects comes
+// back unchanged.
+func FromLetters(input string) string {
+	lex, err := lexer.BooleanLexer.LexString("", input)
+	if err != nil {
+		return input
+	}
+	symbols := lexer.BooleanLexer.Symbols()
+	starts := map[participleLexer.TokenType]bool{
+		symbols["Ident"]:         true,
+		symbols["LitString"]:     true,
+		symbols["IntString"]:     true,
+		symbols["LParen"]:        true,
+		symbols["UnaryOpString"]: true,
+	}
+	ends := map[participleLexer.TokenType]bool{
+		symbols["Ident"]:     true,
+		symbols["LitString"]: true,
+		symbols["IntString"]: true,
+		symbols["RParen"]:    true,
+	}
+	var tokens []participleLexer.Token
+	for {
+		token, err := lex.Next()
+		if err != nil {
+			return input
+		}
+		if token.EOF() {
+			break
+		}
+		tokens = append(tokens, token)
+	}
+	next := func(i int) (participleLexer.Token, bool) {
+		for i++; i < len(tokens); i++ {
+			if tokens[i].Type != symbols["Whitespace"] {
+				return tokens[i], true
+			}
+		}
+		return participleLexer.Token{}, false
+	}
+	var sb strings.Builder
+	// afterOperand is whether the last token written ends an operand.
+	afterOperand := false
+	for i, token := range tokens {
+		if token.Type == symbols["Whitespace"] {
+			sb.WriteString(token.Value)
+			continue
+		}
+		value := token.Value
+		if symb, ok := letters[value]; ok && token.Type == symbols["Ident"] {
+			following, ok := next(i)
+			binary := value == "U" || value == "R"
+			if ok && starts[following.Type] && afterOperand == binary {
+				value = symb
+			}
+		}
+		sb.WriteString(value)
+		afterOperand = value == token.Value && ends[token.Type]
+	}
+	return sb.String()
+}
+
+// Regd. Traces
+
+var assignmentPattern = regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)\s*=\s*([a-zA-Z0-9]+)`)
+
+func parseValue(value string) (bool, error) {
+	switch value {
+	case lexer.TRUE, "true", "T", "1":
+		return true, nil
+	case lexer.FALSE, "false", "F", "0":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid truth value '%s'", value)
+	}
+}
+
+// ParseTrace reads a trace either as one assignment per line
+// (`req = True, ack = False`) or as CSV with a header row of variable
+// names. Blank lines and lines starting with `#` are skipped.
+func ParseTrace(r io.Reader) (Trace, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("empty trace")
+	}
+	if strings.Contains(lines[0], "=") {
+		return parseAssignments(lines)
+	}
+	return parseCSV(lines)
+}
+
+func parseAssignments(lines []string) (Trace, error) {
+	trace := Trace{}
+	for step, line := range lines {
+		state := boolean.Env{}
+		leftover := assignmentPattern.ReplaceAllString(line, "")
+		if strings.Trim(leftover, ", \t") != "" {
+			return nil, fmt.Errorf("step %d: invalid assignment '%s'", step, line)
+		}
+		for _, match := range assignmentPattern.FindAllStringSubmatch(line, -1) {
+			val, err := parseValue(match[2])
+			if err != nil {
+				return nil, fmt.Errorf("step %d: %s", step, err.Error())
+			}
+			state[match[1]] = val
+		}
+		trace = append(trace, state)
+	}
+	return trace, nil
+}
+
+func parseCSV(lines []string) (Trace, error) {
+	reader := csv.NewReader(strings.NewReader(strings.Join(lines, "\n")))
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	header := records[0]
+	trace := Trace{}
+	for step, record := range records[1:] {
+		state := boolean.Env{}
+		for idx, name := range header {
+			val, err := parseValue(strings.TrimSpace(record[idx]))
+			if err != nil {
+				return nil, fmt.Errorf("step %d: %s", step, err.Error())
+			}
+			state[strings.TrimSpace(name)] = val
+		}
+		trace = append(trace, state)
+	}
+	if len(trace) == 0 {
+		return nil, errors.New("empty trace")
+	}
+	return trace, nil
+}
@@ -0,0 +1,132 @@
+package ltl
+
+import (
+	"strings"
+	"testing"
+
+	"acornlang.dev/lang/parser/boolean"
+	"github.com/stretchr/testify/assert"
+)
+
+const requestTrace = `# req/ack handshake
+req = True,  ack = False
+req = False, ack = True
+req = True,  ack = False
+req = False, ack = False
+`
+
+func checkString(t *testing.T, input string, trace Trace) Result {
+	expr, err := boolean.ExprParser.ParseString("", input)
+	assert.NoError(t, err)
+	res, err := Check(expr, trace)
+	assert.NoError(t, err)
+	return res
+}
+
+func TestRequestIsEventuallyAcknowledged(t *testing.T) {
+	trace, err := ParseTrace(strings.NewReader(requestTrace))
+	assert.NoError(t, err)
+	assert.Len(t, trace, 4)
+	res := checkString(t, "□(req => ◇ack)", trace)
+	assert.Equal(t, Result{Holds: false, Step: 2}, res)
+	res = checkString(t, "globally (req implies finally ack)", trace[:2])
+	assert.Equal(t, Result{Holds: true, Step: -1}, res)
+}
+
+func TestOperators(t *testing.T) {
+	// p holds at steps 0 and 1, q only at step 2.
+	trace, err := ParseTrace(strings.NewReader("p,q\n1,0\n1,0\n0,1\n0,0\n"))
+	assert.NoError(t, err)
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"p", true},
+		{"○p", true},
+		{"○○○p", false},
+		{"○○○○True", false},
+		{"◇q", true},
+		{"□p", false},
+		{"□(p or q or ○True)", false},
+		{"□(p or q or not ○True)", true},
+		{"p 𝒰 q", true},
+		{"q 𝒰 p", true},
+		{"(○p) until q", false},
+		{"q ℛ (p or q)", true},
+		{"q release p", false},
+		{"False ℛ p", false},
+		{"next (p and not q)", true},
+		{"□◇q", false},
+		{"◇□not q", true},
+	}
+	for _, test := range tests {
+		res := checkString(t, test.input, trace)
+		assert.Equal(t, test.expected, res.Holds, test.input)
+	}
+}
+
+func TestBlameLooksThroughConjunctionAndNext(t *testing.T) {
+	trace, err := ParseTrace(strings.NewReader("a,b\n1,1\n1,1\n1,0\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, checkString(t, "□a and □b", trace).Step)
+	assert.Equal(t, 2, checkString(t, "○○b", trace).Step)
+	assert.Equal(t, 0, checkString(t, "a => ○○b", trace).Step)
+}
+
+func TestMissingValueFail(t *testing.T) {
+	trace, err := ParseTrace(strings.NewReader("p = 1\np = 0, q = 1\n"))
+	assert.NoError(t, err)
+	expr, err := boolean.ExprParser.ParseString("", "◇ q")
+	assert.NoError(t, err)
+	_, err = Check(expr, trace)
+	assert.EqualError(t, err, "1:3: step 0 has no value for 'q'")
+}
+
+func TestParseTraceFail(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "empty trace"},
+		{"# nothing\n", "empty trace"},
+		{"p,q\n", "empty trace"},
+		{"p = 1\np = maybe\n", "step 1: invalid truth value 'maybe'"},
+		{"p = 1\np\n", "step 1: invalid assignment 'p'"},
+		{"p,q\n1,2\n", "step 0: invalid truth value '2'"},
+	}
+	for _, test := range tests {
+		_, err := ParseTrace(strings.NewReader(test.input))
+		assert.EqualError(t, err, test.expected, test.input)
+	}
+}
+
+func TestTemporalOperatorsOutsideTraceFail(t *testing.T) {
+	expr, err := boolean.ExprParser.ParseString("", "True and □True")
+	assert.NoError(t, err)
+	res := boolean.EvalExpr(expr, boolean.Env{})
+	assert.EqualError(t, res.Err, "temporal operator '□' can only be evaluated over a trace")
+}
+
+func TestFromLetters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"G (req => F ack)", "□ (req => ◇ ack)"},
+		{"G F p", "□ ◇ p"},
+		{"X(p)", "○(p)"},
+		{"p U q", "p 𝒰 q"},
+		{"(p) R not q", "(p) ℛ not q"},
+		{"F and X", "F and X"},
+		{"G U", "□ U"},
+		{"p and U", "p and U"},
+		{"X", "X"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, FromLetters(test.input), test.input)
+	}
+	trace, err := ParseTrace(strings.NewReader(requestTrace))
+	assert.NoError(t, err)
+	res := checkString(t, FromLetters("G (req => F ack)"), trace)
+	assert.Equal(t, Result{Holds: false, Step: 2}, res)
+}
@@ -0,0 +1,179 @@
+package minimize
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// Implicant is a product term over n variables. Bits set in Mask are
+// don't-cares; the remaining bits of Value give the required polarity.
+// Bit n-1-i corresponds to variable i, so variable 0 is the most
+// significant bit of a minterm index.
+type Implicant struct {
+	Value uint
+	Mask  uint
+}
+
+func (imp Implicant) Covers(minterm uint) bool {
+	return minterm&^imp.Mask == imp.Value
+}
+
+// Literals returns the number of variables the implicant mentions.
+func (imp Implicant) Literals(n int) int {
+	return n - bits.OnesCount(imp.Mask)
+}
+
+// Minterms lists, in ascending order, every minterm of n variables the
+// implicant covers.
+func (imp Implicant) Minterms(n int) []uint {
+	acc := []uint{}
+	for minterm := uint(0); minterm < 1<<n; minterm++ {
+		if imp.Covers(minterm) {
+			acc = append(acc, minterm)
+		}
+	}
+	return acc
+}
+
+// PrimeImplicants runs the Quine-McCluskey merge over the minterms and
+// don't-cares of an n variable function.
+func PrimeImplicants(n int, minterms []uint, dontCares []uint) []Implicant {
+	current := map[Implicant]bool{}
+	for _, minterm := range append(append([]uint{}, minterms...), dontCares...) {
+		current[Implicant{Value: minterm}] = true
+	}
+	primes := []Implicant{}
+	for len(current) > 0 {
+		next := map[Implicant]bool{}
+		merged := map[Implicant]bool{}
+		for a := range current {
+			for b := range current {
+				if a.Mask != b.Mask || a.Value >= b.Value {
+					continue
+				}
+				diff := a.Value ^ b.Value
+				if bits.OnesCount(diff) != 1 {
+					continue
+				}
+				next[Implicant{Value: a.Value &^ diff, Mask: a.Mask | diff}] = true
+				merged[a] = true
+				merged[b] = true
+			}
+		}
+		for imp := range current {
+			if !merged[imp] {
+				primes = append(primes, imp)
+			}
+		}
+		current = next
+	}
+	sortImplicants(primes)
+	return primes
+}
+
+// Minimize returns a minimum sum-of-products cover of the minterms: the
+// fewest prime implicants, then the fewest literals. Essential primes are
+// taken first and the rest of the cover is found by branch and bound.
+func Minimize(n int, minterms []uint, dontCares []uint) []Implicant {
+	if len(minterms) == 0 {
+		return []Implicant{}
+	}
+	primes := PrimeImplicants(n, minterms, dontCares)
+	chosen := []Implicant{}
+	uncovered := append([]uint{}, minterms...)
+	sort.Slice(uncovered, func(i, j int) bool { return uncovered[i] < uncovered[j] })
+	for idx := 0; idx < len(uncovered); idx++ {
+		covering := coveringPrimes(primes, uncovered[idx])
+		if len(covering) == 1 {
+			chosen = append(chosen, covering[0])
+			uncovered = removeCovered(uncovered, covering[0])
+			idx = -1
+		}
+	}
+	s := search{n: n, primes: primes, best: nil}
+	s.run(uncovered, []Implicant{})
+	chosen = append(chosen, s.best...)
+	sortImplicants(chosen)
+	return chosen
+}
+
+type search struct {
+	n      int
+	primes []Implicant
+	best   []Implicant
+}
+
+func (s *search) cost(imps []Implicant) (int, int) {
+	literals := 0
+	for _, imp := range imps {
+		literals += imp.Literals(s.n)
+	}
+	return len(imps), literals
+}
+
+func (s *search) better(imps []Implicant) bool {
+	if s.best == nil {
+		return true
+	}
+	count, literals := s.cost(imps)
+	bestCount, bestLiterals := s.cost(s.best)
+	return count < bestCount || count == bestCount && literals < bestLiterals
+}
+
+func (s *search) run(uncovered []uint, acc []Implicant) {
+	if s.best != nil && len(acc) > len(s.best) {
+		return
+	}
+	if len(uncovered) == 0 {
+		if s.better(acc) {
+			s.best = append([]Implicant{}, acc...)
+		}
+		return
+	}
+	// Branch on the minterm with the fewest ways to cover it.
+	var pivot []Implicant
+	for _, minterm := range uncovered {
+		covering := coveringPrimes(s.primes, minterm)
+		if pivot == nil || len(covering) < len(pivot) {
+			pivot = covering
+		}
+	}
+	for _, imp := range pivot {
+		next := append(acc[:len(acc):len(acc)], imp)
+		s.run(removeCovered(uncovered, imp), next)
+	}
+}
+
+func coveringPrimes(primes []Implicant, minterm uint) []Implicant {
+	acc := []Implicant{}
+	for _, imp := range primes {
+		if imp.Covers(minterm) {
+			acc = append(acc, imp)
+		}
+	}
+	return acc
+}
+
+func removeCovered(uncovered []uint, imp Implicant) []uint {
+	acc := []uint{}
+	for _, minterm := range uncovered {
+		if !imp.Covers(minterm) {
+			acc = append(acc, minterm)
+		}
+	}
+	return acc
+}
+
+func sortImplicants(imps []Implicant) {
+	// Larger implicants first, then those fixing earlier variables.
+	sort.Slice(imps, func(i, j int) bool {
+		iSize, jSize := bits.OnesCount(imps[i].Mask), bits.OnesCount(imps[j].Mask)
+		if iSize != jSize {
+			return iSize > jSize
+		}
+		if imps[i].Mask != imps[j].Mask {
+			return imps[i].Mask < imps[j].Mask
+		}
+		return imps[i].Value > imps[j].Value
+	})
+}
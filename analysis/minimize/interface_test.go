@@ -0,0 +1,69 @@
+package minimize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimeImplicants(t *testing.T) {
+	// f(a, b, c) = sum of minterms 0, 1, 2, 5, 6, 7
+	primes := PrimeImplicants(3, []uint{0, 1, 2, 5, 6, 7}, nil)
+	assert.Equal(t, []Implicant{
+		{Value: 0b110, Mask: 0b001},
+		{Value: 0b000, Mask: 0b001},
+		{Value: 0b101, Mask: 0b010},
+		{Value: 0b000, Mask: 0b010},
+		{Value: 0b010, Mask: 0b100},
+		{Value: 0b001, Mask: 0b100},
+	}, primes)
+}
+
+func TestMinimizeCyclicCover(t *testing.T) {
+	// The cyclic function above has no essential primes and two minimum
+	// covers of three implicants each.
+	cover := Minimize(3, []uint{0, 1, 2, 5, 6, 7}, nil)
+	assert.Len(t, cover, 3)
+	assertCovers(t, 3, cover, []uint{0, 1, 2, 5, 6, 7})
+}
+
+func TestMinimizeEssentialPrimes(t *testing.T) {
+	// f(a, b, c, d) = sum of minterms 4, 8, 10, 11, 12, 15 + d(9, 14)
+	cover := Minimize(4, []uint{4, 8, 10, 11, 12, 15}, []uint{9, 14})
+	assert.Equal(t, []Implicant{
+		{Value: 0b1000, Mask: 0b0011},
+		{Value: 0b1010, Mask: 0b0101},
+		{Value: 0b0100, Mask: 0b1000},
+	}, cover)
+}
+
+func TestMinimizeConstants(t *testing.T) {
+	assert.Equal(t, []Implicant{}, Minimize(2, []uint{}, nil))
+	assert.Equal(t, []Implicant{{Value: 0, Mask: 0b11}}, Minimize(2, []uint{0, 1, 2, 3}, nil))
+}
+
+func TestMinimizeIsExhaustivelyCorrect(t *testing.T) {
+	for truthTable := uint(0); truthTable < 1<<8; truthTable++ {
+		minterms := []uint{}
+		for minterm := uint(0); minterm < 8; minterm++ {
+			if truthTable&(1<<minterm) != 0 {
+				minterms = append(minterms, minterm)
+			}
+		}
+		assertCovers(t, 3, Minimize(3, minterms, nil), minterms)
+	}
+}
+
+func assertCovers(t *testing.T, n int, cover []Implicant, minterms []uint) {
+	expected := map[uint]bool{}
+	for _, minterm := range minterms {
+		expected[minterm] = true
+	}
+	for minterm := uint(0); minterm < 1<<n; minterm++ {
+		covered := false
+		for _, imp := range cover {
+			covered = covered || imp.Covers(minterm)
+		}
+		assert.Equal(t, expected[minterm], covered, "minterm %d", minterm)
+	}
+}
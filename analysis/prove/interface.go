@@ -0,0 +1,385 @@
+package prove
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"acornlang.dev/lang/analysis/rewrite"
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ir"
+)
+
+const (
+	// DEFAULT_MAX_STEPS bounds the length of the proofs Prove looks for.
+	DEFAULT_MAX_STEPS int = 8
+	// DEFAULT_MAX_TERMS bounds how many distinct expressions it visits.
+	DEFAULT_MAX_TERMS int = 50000
+	// MAX_CHECKED_VARS bounds the truth table used to refute false equations.
+	MAX_CHECKED_VARS int = 16
+)
+
+// Law is a named algebraic equation; a, b and c stand for any subexpression.
+type Law struct {
+	Name string
+	Lhs  string
+	Rhs  string
+}
+
+var Laws = []Law{
+	{"commutativity", "a and b", "b and a"},
+	{"commutativity", "a or b", "b or a"},
+	{"commutativity", "a xor b", "b xor a"},
+	{"commutativity", "a iff b", "b iff a"},
+	{"commutativity", "a nand b", "b nand a"},
+	{"commutativity", "a nor b", "b nor a"},
+	{"associativity", "a and b and c", "(a and b) and c"},
+	{"associativity", "a or b or c", "(a or b) or c"},
+	{"double negation", "not not a", "a"},
+	{"De Morgan", "not (a and b)", "not a or not b"},
+	{"De Morgan", "not (a or b)", "not a and not b"},
+	{"absorption", "a and (a or b)", "a"},
+	{"absorption", "a or (a and b)", "a"},
+	{"distribution", "a and (b or c)", "(a and b) or (a and c)"},
+	{"distribution", "a or (b and c)", "(a or b) and (a or c)"},
+	{"distribution", "(a or b) and c", "(a and c) or (b and c)"},
+	{"distribution", "(a and b) or c", "(a or c) and (b or c)"},
+	{"idempotence", "a and a", "a"},
+	{"idempotence", "a or a", "a"},
+	{"identity", "a and True", "a"},
+	{"identity", "True and a", "a"},
+	{"identity", "a or False", "a"},
+	{"identity", "False or a", "a"},
+	{"domination", "a and False", "False"},
+	{"domination", "False and a", "False"},
+	{"domination", "a or True", "True"},
+	{"domination", "True or a", "True"},
+	{"complement", "a and not a", "False"},
+	{"complement", "a or not a", "True"},
+	{"negation", "not True", "False"},
+	{"negation", "not False", "True"},
+	{"contrapositive", "a implies b", "not b implies not a"},
+	{"converse", "a is implied by b", "b implies a"},
+	{"definition of nand", "a nand b", "not (a and b)"},
+	{"definition of nor", "a nor b", "not (a or b)"},
+	{"definition of inhibits", "a inhibits b", "a and not b"},
+	{"definition of is inhibited by", "a is inhibited by b", "not a and b"},
+	{"definition of implies", "a implies b", "not a or b"},
+	{"definition of is implied by", "a is implied by b", "a or not b"},
+	{"definition of xor", "a xor b", "(a and not b) or (not a and b)"},
+	{"definition of iff", "a iff b", "(a implies b) and (b implies a)"},
+}
+
+// rules turns every law into a rewrite rule in each direction it can be
+// used: a direction whose left-hand side is a lone variable would match
+// everything, and one that introduces variables cannot be applied.
+func rules() []rewrite.Rule {
+	acc := []rewrite.Rule{}
+	for _, law := range Laws {
+		lhs := rewrite.Canonical(mustParse(law.Lhs))
+		rhs := rewrite.Canonical(mustParse(law.Rhs))
+		for _, pair := range [][2]ir.Node{{lhs, rhs}, {rhs, lhs}} {
+			if _, ok := pair[0].(*ir.Var); ok || !covers(pair[0], pair[1]) {
+				continue
+			}
+			acc = append(acc, rewrite.Rule{Name: law.Name, Lhs: pair[0], Rhs: pair[1]})
+		}
+	}
+	return acc
+}
+
+func mustParse(input string) ir.Node {
+	expr, err := boolean.ExprParser.ParseString("", input)
+	if err != nil {
+		panic("invalid law " + input + ": " + err.Error())
+	}
+	node, err := ir.Lower(expr)
+	if err != nil {
+		panic("invalid law " + input + ": " + err.Error())
+	}
+	return node
+}
+
+func covers(lhs ir.Node, rhs ir.Node) bool {
+	bound := map[string]bool{}
+	for _, name := range ir.FreeVars(lhs) {
+		bound[name] = true
+	}
+	for _, name := range ir.FreeVars(rhs) {
+		if !bound[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// Regd. Proofs
+
+// Step rewrites the previous expression into Result by one use of Law.
+type Step struct {
+	Law    string
+	Result ir.Node
+}
+
+type Proof struct {
+	Start ir.Node
+	Steps []Step
+}
+
+// CounterexampleError reports an assignment on which the two sides differ.
+type CounterexampleError struct {
+	Vars  []string
+	Env   boolean.Env
+	Left  bool
+	Right bool
+}
+
+func (e *CounterexampleError) Error() string {
+	parts := []string{}
+	for _, name := range e.Vars {
+		parts = append(parts, fmt.Sprintf("%s = %s", name, types.Bool(e.Env[name])))
+	}
+	at := strings.Join(parts, ", ")
+	if at == "" {
+		at = "every assignment"
+	}
+	return fmt.Sprintf(
+		"not equal: at %s the left side is %s and the right side is %s",
+		at,
+		types.Bool(e.Left),
+		types.Bool(e.Right),
+	)
+}
+
+// Prove searches, from both ends at once, for the shortest chain of laws
+// that rewrites lhs into rhs. Both sides are first put in canonical form,
+// so spelling never needs a step. An equation that is false is refuted
+// with a counterexample instead.
+func Prove(lhs ir.Node, rhs ir.Node) (Proof, error) {
+	return ProveWithin(lhs, rhs, DEFAULT_MAX_STEPS, DEFAULT_MAX_TERMS)
+}
+
+func ProveWithin(lhs ir.Node, rhs ir.Node, maxSteps int, maxTerms int) (Proof, error) {
+	checked, err := refute(lhs, rhs)
+	if err != nil {
+		return Proof{}, err
+	}
+	s := newSearch(rewrite.Canonical(lhs), rewrite.Canonical(rhs), maxTerms)
+	for depth := 0; ; depth++ {
+		if meet, ok := s.meeting(); ok {
+			return s.proof(meet), nil
+		}
+		if depth >= maxSteps || s.full() {
+			return Proof{}, errNoProof(maxSteps, checked)
+		}
+		forward, backward := len(s.forward.frontier), len(s.backward.frontier)
+		switch {
+		case forward == 0 && backward == 0:
+			return Proof{}, errNoProof(maxSteps, checked)
+		case forward != 0 && (backward == 0 || forward <= backward):
+			s.forward.expand(s)
+		default:
+			s.backward.expand(s)
+		}
+	}
+}
+
+func errNoProof(maxSteps int, checked bool) error {
+	if checked {
+		return fmt.Errorf("no proof found within %d steps, though both sides are equal", maxSteps)
+	}
+	return fmt.Errorf("no proof found within %d steps", maxSteps)
+}
+
+// refute looks for an assignment on which the sides differ. It reports
+// whether it could check every assignment.
+func refute(lhs ir.Node, rhs ir.Node) (bool, error) {
+	vars := ir.FreeVars(lhs)
+	seen := map[string]bool{}
+	for _, name := range vars {
+		seen[name] = true
+	}
+	for _, name := range ir.FreeVars(rhs) {
+		if !seen[name] {
+			vars = append(vars, name)
+		}
+	}
+	if len(vars) > MAX_CHECKED_VARS {
+		return false, nil
+	}
+	for row := 0; row < 1<<len(vars); row++ {
+		env := boolean.Env{}
+		for idx, name := range vars {
+			env[name] = row&(1<<(len(vars)-1-idx)) != 0
+		}
+		left, err := evalBool(lhs, env)
+		if err != nil {
+			return false, err
+		}
+		right, err := evalBool(rhs, env)
+		if err != nil {
+			return false, err
+		}
+		if left != right {
+			return true, &CounterexampleError{Vars: vars, Env: env, Left: left, Right: right}
+		}
+	}
+	return true, nil
+}
+
+func evalBool(node ir.Node, env boolean.Env) (bool, error) {
+	res := boolean.Eval(node, env)
+	if res.Err != nil {
+		return false, fmt.Errorf("%d:%d: %s", res.Pos.Line, res.Pos.Column, res.Err.Error())
+	}
+	return bool(res.Payload.(types.Bool)), nil
+}
+
+// Regd. Search
+
+type visit struct {
+	expr ir.Node
+	// parent is the key of the expression this one was reached from, by
+	// one use of law; it is empty at the root.
+	parent string
+	law    string
+}
+
+type side struct {
+	seen     map[string]visit
+	frontier []string
+}
+
+type search struct {
+	rules    []rewrite.Rule
+	forward  *side
+	backward *side
+	maxTerms int
+	maxLen   int
+}
+
+func newSearch(lhs ir.Node, rhs ir.Node, maxTerms int) *search {
+	newSide := func(root ir.Node) *side {
+		key := rewrite.Format(root)
+		return &side{seen: map[string]visit{key: {expr: root}}, frontier: []string{key}}
+	}
+	// Proofs may pass through larger expressions than either side, but not
+	// arbitrarily large ones.
+	maxLen := 2*max(len(rewrite.Format(lhs)), len(rewrite.Format(rhs))) + 16
+	return &search{
+		rules:    rules(),
+		forward:  newSide(lhs),
+		backward: newSide(rhs),
+		maxTerms: maxTerms,
+		maxLen:   maxLen,
+	}
+}
+
+func (s *search) full() bool {
+	return len(s.forward.seen)+len(s.backward.seen) >= s.maxTerms
+}
+
+// meeting finds an expression both sides have reached, preferring the
+// forward side's order so results are deterministic.
+func (s *search) meeting() (string, bool) {
+	for _, key := range s.forward.frontier {
+		if _, ok := s.backward.seen[key]; ok {
+			return key, true
+		}
+	}
+	for _, key := range s.backward.frontier {
+		if _, ok := s.forward.seen[key]; ok {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func (sd *side) expand(s *search) {
+	next := []string{}
+	for _, key := range sd.frontier {
+		for _, step := range rewrite.Rewrites(sd.seen[key].expr, s.rules) {
+			result := rewrite.Canonical(step.Result)
+			resultKey := rewrite.Format(result)
+			if _, ok := sd.seen[resultKey]; ok || len(resultKey) > s.maxLen {
+				continue
+			}
+			sd.seen[resultKey] = visit{expr: result, parent: key, law: step.Rule}
+			next = append(next, resultKey)
+			if s.full() {
+				sd.frontier = next
+				return
+			}
+		}
+	}
+	sd.frontier = next
+}
+
+func (s *search) proof(meet string) Proof {
+	// Walk back to lhs, then forward again along the backward side's
+	// parents, which lead to rhs.
+	forwardPath := []string{}
+	for key := meet; key != ""; key = s.forward.seen[key].parent {
+		forwardPath = append([]string{key}, forwardPath...)
+	}
+	proof := Proof{Start: s.forward.seen[forwardPath[0]].expr}
+	for _, key := range forwardPath[1:] {
+		v := s.forward.seen[key]
+		proof.Steps = append(proof.Steps, Step{Law: v.law, Result: v.expr})
+	}
+	for key := meet; s.backward.seen[key].parent != ""; key = s.backward.seen[key].parent {
+		v := s.backward.seen[key]
+		proof.Steps = append(proof.Steps, Step{Law: v.law, Result: s.backward.seen[v.parent].expr})
+	}
+	return proof
+}
+
+// Regd. Equations
+
+// SplitEquation splits `lhs = rhs` at the only `=` the lexer does not read
+// as part of an operator such as `=>`, `>=` or `==`.
+func SplitEquation(input string) (string, string, error) {
+	at := -1
+	for start := 0; start < len(input); {
+		end := lexUntilStuck(input[start:])
+		if end == -1 {
+			break
+		}
+		stuck := start + end
+		_, size := utf8.DecodeRuneInString(input[stuck:])
+		start = stuck + size
+		if input[stuck] != '=' {
+			continue
+		}
+		if at != -1 {
+			return "", "", fmt.Errorf("expected a single '=' between two expressions")
+		}
+		at = stuck
+	}
+	if at == -1 {
+		return "", "", fmt.Errorf("expected 'lhs = rhs'")
+	}
+	return strings.TrimSpace(input[:at]), strings.TrimSpace(input[at+1:]), nil
+}
+
+// lexUntilStuck gives the offset of the first text the lexer cannot read,
+// or -1 if it reads all of input.
+func lexUntilStuck(input string) int {
+	lex, err := lexer.BooleanLexer.LexString("", input)
+	if err != nil {
+		return 0
+	}
+	end := 0
+	for {
+		tok, err := lex.Next()
+		if err != nil {
+			return end
+		}
+		if tok.EOF() {
+			return -1
+		}
+		end = tok.Pos.Offset + len(tok.Value)
+	}
+}
@@ -0,0 +1,96 @@
+package prove
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/analysis/rewrite"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types/ir"
+	"github.com/stretchr/testify/assert"
+)
+
+func lowerString(t *testing.T, input string) ir.Node {
+	expr, err := boolean.ExprParser.ParseString("", input)
+	assert.NoError(t, err)
+	node, err := ir.Lower(expr)
+	assert.NoError(t, err)
+	return node
+}
+
+func proveString(t *testing.T, equation string) ([]string, error) {
+	lhsInput, rhsInput, err := SplitEquation(equation)
+	assert.NoError(t, err)
+	proof, err := Prove(lowerString(t, lhsInput), lowerString(t, rhsInput))
+	if err != nil {
+		return nil, err
+	}
+	lines := []string{rewrite.Format(proof.Start)}
+	for _, step := range proof.Steps {
+		lines = append(lines, step.Law+": "+rewrite.Format(step.Result))
+	}
+	return lines, nil
+}
+
+func TestProofs(t *testing.T) {
+	tests := []struct {
+		equation string
+		expected []string
+	}{
+		{"p and q = q /\\ p", []string{"p and q", "commutativity: q and p"}},
+		{"not (p and q) = ~p \\/ ~q", []string{"not (p and q)", "De Morgan: not p or not q"}},
+		{"p nand q = not p or not q", []string{
+			"p nand q",
+			"definition of nand: not (p and q)",
+			"De Morgan: not p or not q",
+		}},
+		{"p inhibits q = not (p implies q)", []string{
+			"p inhibits q",
+			"definition of inhibits: p and not q",
+			"double negation: not not p and not q",
+			"De Morgan: not (not p or q)",
+			"definition of implies: not (p implies q)",
+		}},
+		{"p and (p or q) = p", []string{"p and (p or q)", "absorption: p"}},
+		{"(p) and ((q)) = p and q", []string{"p and q"}},
+		{"True and p = p", []string{"True and p", "identity: p"}},
+		{"p or True = True or q", []string{"p or True", "domination: True", "domination: True or q"}},
+		{"p implies q = not q implies not p", []string{"p implies q", "contrapositive: not q implies not p"}},
+		{"p is implied by q = q implies p", []string{"p is implied by q", "converse: q implies p"}},
+	}
+	for _, test := range tests {
+		lines, err := proveString(t, test.equation)
+		assert.NoError(t, err, test.equation)
+		assert.Equal(t, test.expected, lines, test.equation)
+	}
+}
+
+func TestFalseEquationIsRefuted(t *testing.T) {
+	_, err := proveString(t, "p implies q = q implies p")
+	assert.EqualError(t, err, "not equal: at p = False, q = True the left side is True and the right side is False")
+	var counter *CounterexampleError
+	assert.ErrorAs(t, err, &counter)
+}
+
+func TestSplitEquation(t *testing.T) {
+	lhs, rhs, err := SplitEquation("p => q = q <= p")
+	assert.NoError(t, err)
+	assert.Equal(t, "p => q", lhs)
+	assert.Equal(t, "q <= p", rhs)
+	lhs, rhs, err = SplitEquation("x >= 1 = x == 1 or x != 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "x >= 1", lhs)
+	assert.Equal(t, "x == 1 or x != 1", rhs)
+	lhs, rhs, err = SplitEquation("p ∧ q = q")
+	assert.NoError(t, err)
+	assert.Equal(t, "p ∧ q", lhs)
+	assert.Equal(t, "q", rhs)
+	_, _, err = SplitEquation("p <=> q")
+	assert.EqualError(t, err, "expected 'lhs = rhs'")
+	_, _, err = SplitEquation("p = q = r")
+	assert.EqualError(t, err, "expected a single '=' between two expressions")
+}
+
+func TestGiveUp(t *testing.T) {
+	_, err := proveString(t, "p xor q = (p or q) and not (p and q)")
+	assert.EqualError(t, err, "no proof found within 8 steps, though both sides are equal")
+}
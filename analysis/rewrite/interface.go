@@ -0,0 +1,408 @@
+package rewrite
+
+import (
+	"fmt"
+	"strings"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ast"
+	"acornlang.dev/lang/types/ir"
+)
+
+// DEFAULT_LIMIT caps how many steps Normalize takes before it gives up on a
+// rule set as non-terminating.
+const DEFAULT_LIMIT int = 1000
+
+// Rule rewrites any subexpression matching Lhs into Rhs. Identifiers in Lhs
+// are pattern variables: one that occurs on its own as an operand matches a
+// whole subexpression, and one that occurs more than once must match the
+// same subexpression each time.
+type Rule struct {
+	Name string
+	Lhs  ir.Node
+	Rhs  ir.Node
+}
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+// FromAST lowers both sides of rule and checks that every variable on the
+// right-hand side is bound by the left-hand side.
+func FromAST(rule *ast.Rule) (Rule, error) {
+	bound := map[string]bool{}
+	for _, name := range boolean.FreeVars(rule.Lhs) {
+		bound[name] = true
+	}
+	for _, name := range boolean.FreeVars(rule.Rhs) {
+		if !bound[name] {
+			return Rule{}, errorAt(
+				rule.Rhs.Pos,
+				"rule '%s': '%s' does not appear on the left-hand side",
+				rule.Name,
+				name,
+			)
+		}
+	}
+	lhs, err := ir.Lower(rule.Lhs)
+	if err != nil {
+		return Rule{}, err
+	}
+	rhs, err := ir.Lower(rule.Rhs)
+	if err != nil {
+		return Rule{}, err
+	}
+	return Rule{Name: rule.Name, Lhs: lhs, Rhs: rhs}, nil
+}
+
+// FromFile collects the rules declared in file, in order.
+func FromFile(file *ast.File) ([]Rule, error) {
+	exprs := []*ast.Expr{file.Head}
+	for _, tail := range file.Tail {
+		exprs = append(exprs, tail.Expr)
+	}
+	acc := []Rule{}
+	seen := map[string]bool{}
+	for _, expr := range exprs {
+		if expr == nil || expr.Rule == nil {
+			continue
+		}
+		if seen[expr.Rule.Name] {
+			return nil, errorAt(expr.Rule.Pos, "rule '%s' already defined", expr.Rule.Name)
+		}
+		seen[expr.Rule.Name] = true
+		rule, err := FromAST(expr.Rule)
+		if err != nil {
+			return nil, err
+		}
+		acc = append(acc, rule)
+	}
+	return acc, nil
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("rule %s: %s -> %s", r.Name, Format(r.Lhs), Format(r.Rhs))
+}
+
+// Regd. Matching
+
+type bindings map[string]ir.Node
+
+func (b bindings) bind(name string, node ir.Node) bool {
+	if prev, ok := b[name]; ok {
+		return same(prev, node)
+	}
+	b[name] = node
+	return true
+}
+
+// match lets a pattern like `not a` match `not not x`, binding a to
+// `not x`, since a variable matches any subexpression.
+func (b bindings) match(pattern ir.Node, subject ir.Node) bool {
+	switch p := pattern.(type) {
+	case *ir.Var:
+		return b.bind(p.Name, subject)
+	case *ir.Unary:
+		s, ok := subject.(*ir.Unary)
+		return ok && p.Op == s.Op && b.match(p.X, s.X)
+	case *ir.Binary:
+		s, ok := subject.(*ir.Binary)
+		return ok && p.Op == s.Op && b.match(p.X, s.X) && b.match(p.Y, s.Y)
+	default:
+		return same(pattern, subject)
+	}
+}
+
+// same reports whether a and b are the same expression, however their
+// operators are spelled and wherever they are.
+func same(a ir.Node, b ir.Node) bool {
+	switch x := a.(type) {
+	case *ir.Var:
+		y, ok := b.(*ir.Var)
+		return ok && x.Name == y.Name
+	case *ir.Const:
+		y, ok := b.(*ir.Const)
+		return ok && x.Value == y.Value
+	case *ir.Degree:
+		y, ok := b.(*ir.Degree)
+		return ok && x.Text == y.Text
+	case *ir.Cmp:
+		y, ok := b.(*ir.Cmp)
+		return ok && arith.Format(x.Cmp) == arith.Format(y.Cmp)
+	case *ir.Unary:
+		y, ok := b.(*ir.Unary)
+		return ok && x.Op == y.Op && same(x.X, y.X)
+	case *ir.Binary:
+		y, ok := b.(*ir.Binary)
+		return ok && x.Op == y.Op && same(x.X, y.X) && same(x.Y, y.Y)
+	default:
+		return false
+	}
+}
+
+// Regd. Substitution
+
+// subst builds node with its variables replaced by what they are bound
+// to, placing every node it makes at pos.
+func (b bindings) subst(node ir.Node, pos types.Position) ir.Node {
+	switch n := node.(type) {
+	case *ir.Var:
+		return b[n.Name]
+	case *ir.Unary:
+		return &ir.Unary{Pos: pos, Op: n.Op, Text: n.Text, X: b.subst(n.X, pos)}
+	case *ir.Binary:
+		return &ir.Binary{
+			Pos:   pos,
+			OpPos: pos,
+			Op:    n.Op,
+			Text:  n.Text,
+			X:     b.subst(n.X, pos),
+			Y:     b.subst(n.Y, pos),
+		}
+	case *ir.Const:
+		return &ir.Const{Pos: pos, Value: n.Value}
+	case *ir.Degree:
+		return &ir.Degree{Pos: pos, Text: n.Text}
+	case *ir.Cmp:
+		return &ir.Cmp{Pos: pos, Cmp: n.Cmp}
+	default:
+		return node
+	}
+}
+
+// apply rewrites node itself, not its subexpressions, with the first rule
+// whose left-hand side matches.
+func apply(node ir.Node, rules []Rule) (ir.Node, string, bool) {
+	for _, rule := range rules {
+		b := bindings{}
+		if b.match(rule.Lhs, node) {
+			return b.subst(rule.Rhs, node.Position()), rule.Name, true
+		}
+	}
+	return nil, "", false
+}
+
+// Regd. Strategies
+
+// Step is one rule application.
+type Step struct {
+	Rule   string
+	Result ir.Node
+}
+
+// StepOnce applies one rule at the outermost, leftmost position where any
+// rule matches. It reports false when node is already in normal form.
+func StepOnce(node ir.Node, rules []Rule) (Step, bool) {
+	step := Step{}
+	result, ok := stepNode(node, rules, &step)
+	if !ok {
+		return Step{}, false
+	}
+	step.Result = result
+	return step, true
+}
+
+func stepNode(node ir.Node, rules []Rule, step *Step) (ir.Node, bool) {
+	if result, name, ok := apply(node, rules); ok {
+		step.Rule = name
+		return result, true
+	}
+	switch n := node.(type) {
+	case *ir.Unary:
+		if x, ok := stepNode(n.X, rules, step); ok {
+			return withX(n, x), true
+		}
+	case *ir.Binary:
+		if x, ok := stepNode(n.X, rules, step); ok {
+			return withOperands(n, x, n.Y), true
+		}
+		if y, ok := stepNode(n.Y, rules, step); ok {
+			return withOperands(n, n.X, y), true
+		}
+	}
+	return nil, false
+}
+
+// Once rewrites every outermost match in a single top-down pass without
+// revisiting what it produced, so each rule fires at most once per position.
+func Once(node ir.Node, rules []Rule) (ir.Node, []string) {
+	applied := []string{}
+	return onceNode(node, rules, &applied), applied
+}
+
+func onceNode(node ir.Node, rules []Rule, applied *[]string) ir.Node {
+	if result, name, ok := apply(node, rules); ok {
+		*applied = append(*applied, name)
+		return result
+	}
+	switch n := node.(type) {
+	case *ir.Unary:
+		return withX(n, onceNode(n.X, rules, applied))
+	case *ir.Binary:
+		x := onceNode(n.X, rules, applied)
+		return withOperands(n, x, onceNode(n.Y, rules, applied))
+	default:
+		return node
+	}
+}
+
+func withX(n *ir.Unary, x ir.Node) *ir.Unary {
+	acc := *n
+	acc.X = x
+	return &acc
+}
+
+func withOperands(n *ir.Binary, x ir.Node, y ir.Node) *ir.Binary {
+	acc := *n
+	acc.X, acc.Y = x, y
+	return &acc
+}
+
+// LoopError reports a rule set that did not reach a normal form. Cycle
+// holds the steps of the repeating part when a term recurred, otherwise
+// the last steps taken before the limit.
+type LoopError struct {
+	Limit int
+	Cycle []Step
+	Start ir.Node
+}
+
+func (e *LoopError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "rewriting did not terminate within %d steps: %s", e.Limit, Format(e.Start))
+	for _, step := range e.Cycle {
+		fmt.Fprintf(&sb, " -[%s]-> %s", step.Rule, Format(step.Result))
+	}
+	return sb.String()
+}
+
+// Normalize applies StepOnce until no rule matches. It gives up with a
+// *LoopError after limit steps, or earlier as soon as a term repeats.
+func Normalize(node ir.Node, rules []Rule, limit int) (ir.Node, []Step, error) {
+	steps := []Step{}
+	seen := map[string]int{Format(node): 0}
+	terms := []ir.Node{node}
+	current := node
+	for len(steps) < limit {
+		step, ok := StepOnce(current, rules)
+		if !ok {
+			return current, steps, nil
+		}
+		steps = append(steps, step)
+		current = step.Result
+		key := Format(current)
+		if first, ok := seen[key]; ok {
+			return nil, steps, &LoopError{Limit: limit, Cycle: steps[first:], Start: terms[first]}
+		}
+		seen[key] = len(steps)
+		terms = append(terms, current)
+	}
+	if _, ok := StepOnce(current, rules); !ok {
+		return current, steps, nil
+	}
+	tail := max(0, len(steps)-3)
+	return nil, steps, &LoopError{Limit: limit, Cycle: steps[tail:], Start: terms[tail]}
+}
+
+// Rewrites lists every single rule application to node, at every position
+// and for every matching rule, outermost first.
+func Rewrites(node ir.Node, rules []Rule) []Step {
+	acc := []Step{}
+	for _, rule := range rules {
+		b := bindings{}
+		if b.match(rule.Lhs, node) {
+			acc = append(acc, Step{Rule: rule.Name, Result: b.subst(rule.Rhs, node.Position())})
+		}
+	}
+	switch n := node.(type) {
+	case *ir.Unary:
+		for _, step := range Rewrites(n.X, rules) {
+			acc = append(acc, Step{Rule: step.Rule, Result: withX(n, step.Result)})
+		}
+	case *ir.Binary:
+		for _, step := range Rewrites(n.X, rules) {
+			acc = append(acc, Step{Rule: step.Rule, Result: withOperands(n, step.Result, n.Y)})
+		}
+		for _, step := range Rewrites(n.Y, rules) {
+			acc = append(acc, Step{Rule: step.Rule, Result: withOperands(n, n.X, step.Result)})
+		}
+	}
+	return acc
+}
+
+// Regd. Canonical form
+
+// Canonical spells every operator in its textual form, so two expressions
+// that differ only in spelling or redundant parentheses print alike.
+func Canonical(node ir.Node) ir.Node {
+	switch n := node.(type) {
+	case *ir.Unary:
+		acc := withX(n, Canonical(n.X))
+		acc.Text = n.Op.String()
+		return acc
+	case *ir.Binary:
+		acc := withOperands(n, Canonical(n.X), Canonical(n.Y))
+		acc.Text = n.Op.String()
+		return acc
+	default:
+		return node
+	}
+}
+
+// Regd. Printing
+
+// Format prints node back as source, keeping the operator spellings it
+// was written with. Parentheses go around a binary left operand, which
+// needs them, around a binary right operand whose operator differs from
+// its parent's (`p and (q or r)`), and around the binary operand of a
+// unary operator; a chain of one operator reads without them
+// (`p and q and r`).
+func Format(node ir.Node) string {
+	var sb strings.Builder
+	format(&sb, node)
+	return sb.String()
+}
+
+func format(sb *strings.Builder, node ir.Node) {
+	switch n := node.(type) {
+	case *ir.Unary:
+		sb.WriteString(n.Text)
+		// A symbol such as `~` touches its operand; a word does not.
+		if n.Text != n.Op.Symbol() || n.Op.Symbol() == n.Op.String() {
+			sb.WriteString(" ")
+		}
+		_, bracket := n.X.(*ir.Binary)
+		formatOperand(sb, n.X, bracket)
+	case *ir.Binary:
+		_, bracket := n.X.(*ir.Binary)
+		formatOperand(sb, n.X, bracket)
+		sb.WriteString(" " + n.Text + " ")
+		right, bracket := n.Y.(*ir.Binary)
+		formatOperand(sb, n.Y, bracket && right.Op != n.Op)
+	case *ir.Var:
+		sb.WriteString(n.Name)
+	case *ir.Const:
+		if n.Value {
+			sb.WriteString(lexer.TRUE)
+		} else {
+			sb.WriteString(lexer.FALSE)
+		}
+	case *ir.Degree:
+		sb.WriteString(n.Text)
+	case *ir.Cmp:
+		sb.WriteString(arith.Format(n.Cmp))
+	}
+}
+
+func formatOperand(sb *strings.Builder, node ir.Node, bracket bool) {
+	if bracket {
+		sb.WriteString("(")
+	}
+	format(sb, node)
+	if bracket {
+		sb.WriteString(")")
+	}
+}
@@ -0,0 +1,167 @@
+package rewrite
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/parser"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types/ir"
+	"github.com/stretchr/testify/assert"
+)
+
+const laws = `rule demorgan: not (a and b) -> not a or not b
+rule dneg: not not a -> a
+rule idem: a and a -> a`
+
+func parseRules(t *testing.T, input string) []Rule {
+	file, err := parser.FileParser.ParseString("", input)
+	assert.NoError(t, err)
+	rules, err := FromFile(file)
+	assert.NoError(t, err)
+	return rules
+}
+
+func lowerString(t *testing.T, input string) ir.Node {
+	expr, err := boolean.ExprParser.ParseString("", input)
+	assert.NoError(t, err)
+	node, err := ir.Lower(expr)
+	assert.NoError(t, err)
+	return node
+}
+
+func normalizeString(t *testing.T, rules []Rule, input string) (string, []Step, error) {
+	result, steps, err := Normalize(lowerString(t, input), rules, DEFAULT_LIMIT)
+	if err != nil {
+		return "", steps, err
+	}
+	return Format(result), steps, nil
+}
+
+func TestNormalize(t *testing.T) {
+	rules := parseRules(t, laws)
+	tests := []struct {
+		input    string
+		expected string
+		steps    int
+	}{
+		{"not (p and q)", "not p or not q", 1},
+		{"~(p /\\ q)", "not p or not q", 1},
+		{"not not not (p and q)", "not p or not q", 2},
+		{"not (p and (q or r))", "not p or not (q or r)", 1},
+		{"not (p and q and r)", "not p or not q or not r", 2},
+		{"x or not (p and p)", "x or not p or not p", 1},
+		{"x or not not (p and p)", "x or p", 2},
+		{"(p and q) and (p and q)", "p and q", 1},
+		{"p and q", "p and q", 0},
+	}
+	for _, test := range tests {
+		result, steps, err := normalizeString(t, rules, test.input)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, result, test.input)
+		assert.Len(t, steps, test.steps, test.input)
+	}
+}
+
+func TestStepOnceIsOutermostFirst(t *testing.T) {
+	rules := parseRules(t, laws)
+	expr := lowerString(t, "not not (p and not not q)")
+	step, ok := StepOnce(expr, rules)
+	assert.True(t, ok)
+	assert.Equal(t, "dneg", step.Rule)
+	assert.Equal(t, "p and not not q", Format(step.Result))
+	step, ok = StepOnce(step.Result, rules)
+	assert.True(t, ok)
+	assert.Equal(t, "p and q", Format(step.Result))
+	_, ok = StepOnce(step.Result, rules)
+	assert.False(t, ok)
+}
+
+func TestOnceDoesNotRevisit(t *testing.T) {
+	rules := parseRules(t, laws)
+	expr := lowerString(t, "not not not not p or not not q")
+	result, applied := Once(expr, rules)
+	assert.Equal(t, "not not p or q", Format(result))
+	assert.Equal(t, []string{"dneg", "dneg"}, applied)
+}
+
+func TestLoopIsReported(t *testing.T) {
+	rules := parseRules(t, "rule comm: a and b -> b and a")
+	_, steps, err := normalizeString(t, rules, "p and q")
+	assert.Len(t, steps, 2)
+	assert.EqualError(
+		t,
+		err,
+		"rewriting did not terminate within 1000 steps: p and q -[comm]-> q and p -[comm]-> p and q",
+	)
+
+	rules = parseRules(t, "rule grow: a -> not a")
+	_, steps, err = normalizeString(t, rules, "p")
+	assert.Len(t, steps, DEFAULT_LIMIT)
+	assert.ErrorContains(t, err, "-[grow]->")
+}
+
+func TestUnboundRhsVarFail(t *testing.T) {
+	file, err := parser.FileParser.ParseString("", "rule bad: a -> a or b")
+	assert.NoError(t, err)
+	_, err = FromFile(file)
+	assert.EqualError(t, err, "1:16: rule 'bad': 'b' does not appear on the left-hand side")
+}
+
+func TestMatchIgnoresSpelling(t *testing.T) {
+	rules := parseRules(t, laws)
+	result, steps, err := normalizeString(t, rules, "~~(p /\\ p) \\/ q")
+	assert.NoError(t, err)
+	assert.Equal(t, "p \\/ q", result)
+	assert.Len(t, steps, 2)
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"((p)) /\\ (q)", "p /\\ q"},
+		{"~(p or q)", "~(p or q)"},
+		{"not ~p", "not ~p"},
+		{"p and (q and r)", "p and q and r"},
+		{"p and q or r", "p and (q or r)"},
+		{"(p or q) and r", "(p or q) and r"},
+		{"x + 1 > y iff 0.5", "x + 1 > y iff 0.5"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, Format(lowerString(t, test.input)), test.input)
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(p)", "p"},
+		{"~(p) /\\ ((q))", "not p and q"},
+		{"p and (q and r)", "p and q and r"},
+		{"p and q or r", "p and (q or r)"},
+		{"(p and q) and r", "(p and q) and r"},
+		{"not ((p or q))", "not (p or q)"},
+		{"p <=> q", "p iff q"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, Format(Canonical(lowerString(t, test.input))), test.input)
+	}
+}
+
+func TestRewritesListsEveryPosition(t *testing.T) {
+	rules := parseRules(t, laws)
+	expr := lowerString(t, "not not (p and p) or not not q")
+	results := []string{}
+	for _, step := range Rewrites(expr, rules) {
+		results = append(results, step.Rule+": "+Format(step.Result))
+	}
+	assert.Equal(t, []string{
+		"dneg: (p and p) or not not q",
+		"demorgan: not (not p or not p) or not not q",
+		"idem: not not p or not not q",
+		"dneg: not not (p and p) or q",
+	}, results)
+}
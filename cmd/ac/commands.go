@@ -0,0 +1,736 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+
+	"acornlang.dev/lang/analysis/bdd"
+	"acornlang.dev/lang/analysis/bounded"
+	"acornlang.dev/lang/analysis/decision"
+	"acornlang.dev/lang/analysis/fuzzy"
+	"acornlang.dev/lang/analysis/kmap"
+	"acornlang.dev/lang/analysis/ltl"
+	"acornlang.dev/lang/analysis/prove"
+	"acornlang.dev/lang/analysis/rewrite"
+	"acornlang.dev/lang/parser"
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/parser/bits"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/parser/data"
+	"acornlang.dev/lang/parser/sets"
+	parserStrings "acornlang.dev/lang/parser/strings"
+	"acornlang.dev/lang/repl"
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/check"
+	"acornlang.dev/lang/types/ir"
+)
+
+const (
+	KMAP_COMMAND  = ":kmap"
+	COUNT_COMMAND = ":count"
+	PROB_COMMAND  = ":prob"
+	LTL_COMMAND   = ":ltl"
+	LOGIC_COMMAND = ":logic"
+	TABLE_COMMAND = ":table"
+	TREE_COMMAND  = ":tree"
+
+	UNIVERSE_COMMAND = ":universe"
+
+	RULES_COMMAND   = ":rules"
+	REWRITE_COMMAND = ":rewrite"
+	ONCE_COMMAND    = ":once"
+	STEP_COMMAND    = ":step"
+	PROVE_COMMAND   = ":prove"
+	TYPE_COMMAND    = ":type"
+)
+
+var groupStyles = []tcell.Style{
+	tcell.StyleDefault.Background(tcell.ColorDarkRed).Foreground(tcell.ColorWhite),
+	tcell.StyleDefault.Background(tcell.ColorDarkGreen).Foreground(tcell.ColorWhite),
+	tcell.StyleDefault.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorWhite),
+	tcell.StyleDefault.Background(tcell.ColorOlive).Foreground(tcell.ColorWhite),
+	tcell.StyleDefault.Background(tcell.ColorPurple).Foreground(tcell.ColorWhite),
+	tcell.StyleDefault.Background(tcell.ColorTeal).Foreground(tcell.ColorWhite),
+}
+
+func isCommand(input string) bool {
+	return strings.HasPrefix(strings.TrimSpace(input), ":")
+}
+
+// LXCommand runs a `:command arg` line and returns the history entries it
+// produces. Commands do not consume an expression number.
+func LXCommand(input string, ctx *repl.ReplContext) ([]HistoryEntry, *repl.ReplContext) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(input), " ")
+	switch name {
+	case KMAP_COMMAND:
+		return kmapEntries(strings.TrimSpace(arg)), ctx
+	case COUNT_COMMAND:
+		return textEntries(countText(strings.TrimSpace(arg))), ctx
+	case PROB_COMMAND:
+		return textEntries(probText(strings.TrimSpace(arg))), ctx
+	case LTL_COMMAND:
+		exprInput, tracePath, _ := strings.Cut(arg, "|")
+		return textEntries(ltlText(strings.TrimSpace(exprInput), strings.TrimSpace(tracePath))), ctx
+	case TABLE_COMMAND:
+		return textEntries(tableText(strings.TrimSpace(arg))), ctx
+	case TREE_COMMAND:
+		return textEntries(treeText(strings.TrimSpace(arg))), ctx
+	case LOGIC_COMMAND:
+		return logicCommand(strings.TrimSpace(arg), ctx)
+	case UNIVERSE_COMMAND:
+		return universeCommand(strings.TrimSpace(arg), ctx)
+	case RULES_COMMAND:
+		return textEntries(rulesText(ctx)), ctx
+	case REWRITE_COMMAND:
+		return textEntries(rewriteText(strings.TrimSpace(arg), ctx)), ctx
+	case ONCE_COMMAND:
+		return textEntries(onceText(strings.TrimSpace(arg), ctx)), ctx
+	case STEP_COMMAND:
+		return stepCommand(strings.TrimSpace(arg), ctx)
+	case TYPE_COMMAND:
+		return textEntries(typeText(strings.TrimSpace(arg), ctx)), ctx
+	case PROVE_COMMAND:
+		return proveEntries(strings.TrimSpace(arg)), ctx
+	default:
+		return textEntries(fmt.Sprintf("|  Error:\n|  unknown command '%s'", name)), ctx
+	}
+}
+
+// logicCommand switches between boolean evaluation and a fuzzy t-norm
+// family; with no argument it reports the current logic.
+func logicCommand(arg string, ctx *repl.ReplContext) ([]HistoryEntry, *repl.ReplContext) {
+	if arg == "" {
+		return textEntries("logic: " + ctx.Logic()), ctx
+	}
+	if arg == repl.DEFAULT_LOGIC {
+		return textEntries("logic: " + arg), ctx.WithLogic(arg)
+	}
+	family, err := fuzzy.ParseFamily(arg)
+	if err != nil {
+		return textEntries(fmt.Sprintf("|  Error:\n|  %s", err.Error())), ctx
+	}
+	return textEntries("logic: fuzzy " + family.String()), ctx.WithLogic(family.String())
+}
+
+// universeCommand declares the set that `not` and the other complementing
+// connectives take their elements from; with no argument it reports it.
+func universeCommand(arg string, ctx *repl.ReplContext) ([]HistoryEntry, *repl.ReplContext) {
+	if arg == "" {
+		if ctx.Universe() == "" {
+			return textEntries("universe: none"), ctx
+		}
+		return textEntries("universe: " + ctx.Universe()), ctx
+	}
+	parsed, err := sets.ExprParser.ParseString("", arg)
+	if err != nil || parsed.Set == nil {
+		return textEntries(fmt.Sprintf("|  Error:\n|  illegal set\n|  %s\n|  ^", arg)), ctx
+	}
+	universe, err := sets.EvalSet(parsed.Set, setsEnv(ctx))
+	if err != nil {
+		return textEntries(fmt.Sprintf("|  Error:\n|  %s", err.Error())), ctx
+	}
+	return textEntries("universe: " + universe.String()), ctx.WithUniverse(universe.String())
+}
+
+// setsEnv gives set expressions the declared universe, if any.
+func setsEnv(ctx *repl.ReplContext) sets.Env {
+	env := sets.Env{Sets: map[string]types.Set{}}
+	for name, val := range ctx.Bindings() {
+		if set, ok := val.(types.Set); ok {
+			env.Sets[name] = set
+		}
+	}
+	if ctx.Universe() == "" {
+		return env
+	}
+	parsed, err := sets.ExprParser.ParseString("", ctx.Universe())
+	if err != nil || parsed.Set == nil {
+		return env
+	}
+	universe, err := sets.EvalSet(parsed.Set, sets.Env{})
+	if err != nil {
+		return env
+	}
+	env.Universe = &universe
+	return env
+}
+
+// Regd. Bindings
+
+// checkEnv types every `let` binding; the evaluators' environments below
+// take only the bindings of their own kind of value.
+func checkEnv(ctx *repl.ReplContext) check.Env {
+	env := check.Env{}
+	for name, val := range ctx.Bindings() {
+		env[name] = check.Binding(name, val)
+	}
+	return env
+}
+
+func booleanEnv(ctx *repl.ReplContext) boolean.Env {
+	env := boolean.Env{}
+	for name, val := range ctx.Bindings() {
+		if b, ok := val.(types.Bool); ok {
+			env[name] = bool(b)
+		}
+	}
+	return env
+}
+
+// fuzzyEnv reads True as the degree 1 and False as 0.
+func fuzzyEnv(ctx *repl.ReplContext) fuzzy.Env {
+	env := fuzzy.Env{}
+	for name, val := range ctx.Bindings() {
+		switch val := val.(type) {
+		case types.Degree:
+			env[name] = float64(val)
+		case types.Bool:
+			if val {
+				env[name] = 1
+			} else {
+				env[name] = 0
+			}
+		}
+	}
+	return env
+}
+
+func stringsEnv(ctx *repl.ReplContext) parserStrings.Env {
+	env := parserStrings.Env{Bools: booleanEnv(ctx), Strings: map[string]string{}}
+	for name, val := range ctx.Bindings() {
+		if s, ok := val.(types.String); ok {
+			env.Strings[name] = string(s)
+		}
+	}
+	return env
+}
+
+func arithEnv(ctx *repl.ReplContext) arith.Env {
+	env := arith.Env{}
+	for name, val := range ctx.Bindings() {
+		if i, ok := val.(types.Int); ok {
+			env[name] = int64(i)
+		}
+	}
+	return env
+}
+
+func bitsEnv(ctx *repl.ReplContext) bits.Env {
+	env := bits.Env{}
+	for name, val := range ctx.Bindings() {
+		if vector, ok := val.(types.Bits); ok {
+			env[name] = vector
+		}
+	}
+	return env
+}
+
+func textEntries(text string) []HistoryEntry {
+	entries := []HistoryEntry{}
+	for _, line := range strings.Split(text, "\n") {
+		entries = append(entries, HistoryEntry{Raw: line, Math: line, English: line})
+	}
+	return entries
+}
+
+// lower parses `expr | x : 0..7, y : 1..3` and bit-blasts the integer
+// comparisons in expr over the given ranges.
+func lower(input string) (bounded.Lowered, string) {
+	exprInput, rangesInput, _ := strings.Cut(input, "|")
+	exprInput = strings.TrimSpace(exprInput)
+	parsed, err := boolean.ExprParser.ParseString("", exprInput)
+	if err != nil {
+		return bounded.Lowered{}, fmt.Sprintf("|  Error:\n|  illegal expression\n|  %s\n|  ^", exprInput)
+	}
+	ranges, err := bounded.ParseRanges(rangesInput)
+	if err != nil {
+		return bounded.Lowered{}, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	lowered, err := bounded.Lower(parsed, ranges)
+	if err != nil {
+		return bounded.Lowered{}, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	return lowered, ""
+}
+
+func buildKMap(input string) (*kmap.KMap, string) {
+	lowered, errText := lower(input)
+	if errText != "" {
+		return nil, errText
+	}
+	m, err := kmap.Build(lowered.Expr)
+	if err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	return m, ""
+}
+
+func kmapEntries(input string) []HistoryEntry {
+	m, errText := buildKMap(input)
+	if m == nil {
+		return textEntries(errText)
+	}
+	entries := []HistoryEntry{}
+	for _, line := range m.Lines() {
+		entries = append(entries, HistoryEntry{
+			Raw:     line.Text,
+			Math:    line.Text,
+			English: line.Text,
+			Marks:   line.Marks,
+		})
+	}
+	return entries
+}
+
+func kmapCommand(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: ac kmap 'expr [| x : lo..hi, ...]'")
+		return 2
+	}
+	m, errText := buildKMap(strings.Join(args, " "))
+	if m == nil {
+		fmt.Fprintln(stderr, errText)
+		return 1
+	}
+	fmt.Fprint(stdout, m.String())
+	return 0
+}
+
+func buildBDD(lowered bounded.Lowered) (*bdd.Manager, bdd.Node, string) {
+	m := bdd.New(lowered.Vars)
+	root, err := m.FromExpr(lowered.Expr)
+	if err != nil {
+		return nil, bdd.False, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	return m, root, ""
+}
+
+// countText answers `:count expr | x : 0..7` with the number and share of
+// satisfying assignments over the expression's free variables, integers
+// ranging over their bounds.
+func countText(input string) string {
+	if _, suffix, _ := strings.Cut(input, "|"); strings.Contains(suffix, "~") {
+		return "|  Error:\n|  :count takes ranges 'name : lo..hi'; for weights 'name ~ probability' use :prob"
+	}
+	lowered, errText := lower(input)
+	if errText != "" {
+		return errText
+	}
+	m, root, errText := buildBDD(lowered)
+	if m == nil {
+		return errText
+	}
+	count := m.SatCount(root)
+	total := lowered.Total
+	share, _ := new(big.Rat).SetFrac(count, total).Float64()
+	return fmt.Sprintf("%s of %s assignments (%g%%)", count, total, share*100)
+}
+
+// probText answers `:prob expr | p ~ 0.3, q ~ 0.9`, giving unweighted
+// variables probability 0.5.
+func probText(input string) string {
+	exprInput, weightsInput, _ := strings.Cut(input, "|")
+	weights, err := bdd.ParseWeights(weightsInput)
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	lowered, errText := lower(exprInput)
+	if errText != "" {
+		return errText
+	}
+	m, root, errText := buildBDD(lowered)
+	if m == nil {
+		return errText
+	}
+	for name := range weights {
+		if _, err := m.Var(name); err != nil {
+			return fmt.Sprintf("|  Error:\n|  %s", err.Error())
+		}
+	}
+	return fmt.Sprintf("P = %g", m.Probability(root, weights))
+}
+
+// ltlText answers `:ltl expr | trace-file` with whether the property holds
+// on the trace, counting steps from 0. The letters X, G, F, U and R may
+// stand for the temporal operators.
+func ltlText(input string, tracePath string) string {
+	parsed, err := boolean.ExprParser.ParseString("", ltl.FromLetters(input))
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  illegal expression\n|  %s\n|  ^", input)
+	}
+	if tracePath == "" {
+		return "|  Error:\n|  missing trace file: :ltl expr | trace-file"
+	}
+	traceFile, err := os.Open(tracePath)
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	defer traceFile.Close()
+	trace, err := ltl.ParseTrace(traceFile)
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  %s: %s", tracePath, err.Error())
+	}
+	res, err := ltl.Check(parsed, trace)
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	if res.Holds {
+		return fmt.Sprintf("holds on all %d steps", len(trace))
+	}
+	return fmt.Sprintf("fails at step %d of %d", res.Step, len(trace))
+}
+
+func ltlCommand(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("ltl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	tracePath := flags.String("trace", "", "trace file: one assignment per line, or CSV")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 || *tracePath == "" {
+		fmt.Fprintln(stderr, "usage: ac ltl --trace=file expr")
+		return 2
+	}
+	out := ltlText(strings.Join(flags.Args(), " "), *tracePath)
+	if strings.HasPrefix(out, "|  Error:") {
+		fmt.Fprintln(stderr, out)
+		return 1
+	}
+	fmt.Fprintln(stdout, out)
+	return 0
+}
+
+// tableText answers `:table a | b || out ; T | - || T ; ...` with each
+// output as an expression over the conditions, then any rows that leave
+// the table incomplete, unreachable or conflicting.
+func tableText(input string) string {
+	table, err := decision.ParseTable(input)
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	exprs, err := table.Exprs()
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	lines := []string{}
+	for idx, expr := range exprs {
+		node, err := ir.Lower(expr)
+		if err != nil {
+			return fmt.Sprintf("|  Error:\n|  %s", err.Error())
+		}
+		lines = append(lines, fmt.Sprintf("%s = %s", table.Outputs[idx], rewrite.Format(node)))
+	}
+	for _, issue := range table.Check() {
+		lines = append(lines, issue.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func tableCommand(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, "usage: ac table file")
+		return 2
+	}
+	input, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+	out := tableText(string(input))
+	if strings.HasPrefix(out, "|  Error:") {
+		fmt.Fprintln(stderr, out)
+		return 1
+	}
+	fmt.Fprintln(stdout, out)
+	return 0
+}
+
+// treeText answers `:tree expr` with the decision tree for expr that
+// has the fewest tests.
+func treeText(input string) string {
+	parsed, err := boolean.ExprParser.ParseString("", input)
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  illegal expression\n|  %s\n|  ^", input)
+	}
+	tree, err := decision.Tree(parsed)
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	return tree.String()
+}
+
+func treeCommand(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: ac tree 'expr'")
+		return 2
+	}
+	out := treeText(strings.Join(args, " "))
+	if strings.HasPrefix(out, "|  Error:") {
+		fmt.Fprintln(stderr, out)
+		return 1
+	}
+	fmt.Fprintln(stdout, out)
+	return 0
+}
+
+// drawMarkedText draws one unwrapped line, one rune per column, styling
+// each marked rune with the style of its group.
+func drawMarkedText(s tcell.Screen, x, y int, text string, marks []int, width int) int {
+	for idx, ch := range []rune(text) {
+		if width <= x+idx {
+			break
+		}
+		style := tcell.StyleDefault
+		if idx < len(marks) && 0 <= marks[idx] {
+			style = groupStyles[marks[idx]%len(groupStyles)]
+		}
+		s.SetContent(x+idx, y, ch, nil, style)
+	}
+	return 1
+}
+
+// typeText shows the type an expression would evaluate to under the
+// current logic and the `let` bindings so far; a fuzzy logic evaluates
+// every truth value as a Degree.
+func typeText(input string, ctx *repl.ReplContext) string {
+	typ, errText := typeOf(input, checkEnv(ctx))
+	if errText != "" {
+		return errText
+	}
+	if ctx.Logic() != repl.DEFAULT_LOGIC && typ.Equal(types.BOOL) {
+		typ = types.DEGREE
+	}
+	return fmt.Sprintf("%s : %s", input, typ)
+}
+
+// typeOf checks input with the first parser that accepts it, in the order
+// evalValue tries them.
+func typeOf(input string, env check.Env) (types.Type, string) {
+	var typ types.Type
+	var err error
+	if str, parseErr := parseString(input, env); parseErr == nil {
+		typ, err = check.String(str, env)
+	} else if strings.HasPrefix(strings.TrimSpace(input), `"`) {
+		return nil, fmt.Sprintf("|  Error:\n|  illegal expression\n|  %s\n|  ^", input)
+	} else if parsed, parseErr := boolean.ExprParser.ParseString("", input); parseErr == nil {
+		typ, err = check.Expr(parsed, env)
+	} else if text, ok := comparisonError(parseErr); ok {
+		return nil, text
+	} else if sum, parseErr := arith.ExprParser.ParseString("", input); parseErr == nil {
+		typ, err = check.Sum(sum, env)
+	} else if vector, parseErr := bits.ExprParser.ParseString("", input); parseErr == nil {
+		typ, err = check.Bits(vector, env)
+	} else if set, parseErr := sets.ExprParser.ParseString("", input); parseErr == nil {
+		typ, err = check.Set(set, env)
+	} else if expr, parseErr := data.ExprParser.ParseString("", input); parseErr == nil {
+		typ, err = check.Data(expr, env)
+	} else {
+		return nil, fmt.Sprintf("|  Error:\n|  illegal expression\n|  %s\n|  ^", input)
+	}
+	if err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	return typ, ""
+}
+
+// Regd. Rewriting
+
+func isRule(input string) bool {
+	return strings.HasPrefix(strings.TrimSpace(input), "rule ")
+}
+
+// LXDeclareRule adds a rewrite rule to the session, replacing any earlier
+// rule of the same name.
+func LXDeclareRule(input string, ctx *repl.ReplContext) (string, *repl.ReplContext) {
+	parsed, err := parser.RuleParser.ParseString("", input)
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  %s", err.Error()), ctx
+	}
+	rule, err := rewrite.FromAST(parsed)
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  %s", err.Error()), ctx
+	}
+	rules, err := sessionRules(ctx)
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  %s", err.Error()), ctx
+	}
+	sources := []string{}
+	for idx, prev := range rules {
+		if prev.Name != rule.Name {
+			sources = append(sources, ctx.Rules()[idx])
+		}
+	}
+	return rule.String(), ctx.WithRules(append(sources, strings.TrimSpace(input)))
+}
+
+func sessionRules(ctx *repl.ReplContext) ([]rewrite.Rule, error) {
+	acc := []rewrite.Rule{}
+	for _, source := range ctx.Rules() {
+		parsed, err := parser.RuleParser.ParseString("", source)
+		if err != nil {
+			return nil, err
+		}
+		rule, err := rewrite.FromAST(parsed)
+		if err != nil {
+			return nil, err
+		}
+		acc = append(acc, rule)
+	}
+	return acc, nil
+}
+
+func rulesText(ctx *repl.ReplContext) string {
+	rules, err := sessionRules(ctx)
+	if err != nil {
+		return fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	if len(rules) == 0 {
+		return "no rules declared"
+	}
+	lines := []string{}
+	for _, rule := range rules {
+		lines = append(lines, rule.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func parseForRewrite(input string, ctx *repl.ReplContext) (ir.Node, []rewrite.Rule, string) {
+	parsed, errText := parseNode(input)
+	if errText != "" {
+		return nil, nil, errText
+	}
+	rules, err := sessionRules(ctx)
+	if err != nil {
+		return nil, nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	if len(rules) == 0 {
+		return nil, nil, "|  Error:\n|  no rules declared"
+	}
+	return parsed, rules, ""
+}
+
+// parseNode parses input as a boolean expression and lowers it for the
+// rewriting and proof commands.
+func parseNode(input string) (ir.Node, string) {
+	parsed, err := boolean.ExprParser.ParseString("", input)
+	if err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  illegal expression\n|  %s\n|  ^", input)
+	}
+	node, err := ir.Lower(parsed)
+	if err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	return node, ""
+}
+
+func stepLine(step rewrite.Step) string {
+	return fmt.Sprintf("  -[%s]-> %s", step.Rule, rewrite.Format(step.Result))
+}
+
+func rewriteText(input string, ctx *repl.ReplContext) string {
+	parsed, rules, errText := parseForRewrite(input, ctx)
+	if errText != "" {
+		return errText
+	}
+	_, steps, err := rewrite.Normalize(parsed, rules, rewrite.DEFAULT_LIMIT)
+	var loop *rewrite.LoopError
+	if errors.As(err, &loop) {
+		lines := []string{"|  Error:", "|  no normal form, the rules loop:"}
+		lines = append(lines, "|  "+rewrite.Format(loop.Start))
+		for _, step := range loop.Cycle {
+			lines = append(lines, "|  "+stepLine(step))
+		}
+		return strings.Join(lines, "\n")
+	}
+	lines := []string{rewrite.Format(parsed)}
+	for _, step := range steps {
+		lines = append(lines, stepLine(step))
+	}
+	if len(steps) == 0 {
+		lines = append(lines, "  (already in normal form)")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func onceText(input string, ctx *repl.ReplContext) string {
+	parsed, rules, errText := parseForRewrite(input, ctx)
+	if errText != "" {
+		return errText
+	}
+	result, applied := rewrite.Once(parsed, rules)
+	if len(applied) == 0 {
+		return rewrite.Format(parsed) + "\n  (no rule applies)"
+	}
+	return fmt.Sprintf("%s\n  -[%s]-> %s", rewrite.Format(parsed), strings.Join(applied, ", "), rewrite.Format(result))
+}
+
+// stepCommand applies one rule to its argument, or with no argument to the
+// result of the previous `:step`.
+func stepCommand(arg string, ctx *repl.ReplContext) ([]HistoryEntry, *repl.ReplContext) {
+	if arg == "" {
+		arg = ctx.Focus()
+	}
+	if arg == "" {
+		return textEntries("|  Error:\n|  nothing to step, use :step expr"), ctx
+	}
+	parsed, rules, errText := parseForRewrite(arg, ctx)
+	if errText != "" {
+		return textEntries(errText), ctx
+	}
+	step, ok := rewrite.StepOnce(parsed, rules)
+	if !ok {
+		return textEntries(rewrite.Format(parsed) + "\n  (normal form)"), ctx.WithFocus("")
+	}
+	result := rewrite.Format(step.Result)
+	return textEntries(rewrite.Format(parsed) + "\n" + stepLine(step)), ctx.WithFocus(result)
+}
+
+// Regd. Proofs
+
+// proveEntries prints each step of an equational proof with the law it
+// uses, as a chain of equalities in raw and math mode and as prose in
+// English mode.
+func proveEntries(input string) []HistoryEntry {
+	lhsInput, rhsInput, err := prove.SplitEquation(input)
+	if err != nil {
+		return textEntries(fmt.Sprintf("|  Error:\n|  %s", err.Error()))
+	}
+	lhs, errText := parseNode(lhsInput)
+	if errText != "" {
+		return textEntries(errText)
+	}
+	rhs, errText := parseNode(rhsInput)
+	if errText != "" {
+		return textEntries(errText)
+	}
+	proof, err := prove.Prove(lhs, rhs)
+	if err != nil {
+		return textEntries(fmt.Sprintf("|  Error:\n|  %s", err.Error()))
+	}
+	start := rewrite.Format(proof.Start)
+	entries := []HistoryEntry{{
+		Raw:     "   " + start,
+		Math:    "   " + replaceToMath(start),
+		English: "   " + replaceToEnglish(start),
+	}}
+	for _, step := range proof.Steps {
+		result := rewrite.Format(step.Result)
+		entries = append(entries, HistoryEntry{
+			Raw:     fmt.Sprintf("=  %s    [%s]", result, step.Law),
+			Math:    fmt.Sprintf("=  %s    [%s]", replaceToMath(result), step.Law),
+			English: fmt.Sprintf("is the same as %s, by %s", replaceToEnglish(result), step.Law),
+		})
+	}
+	done := "so both sides are equal"
+	if len(proof.Steps) == 0 {
+		done = "both sides are the same expression"
+	}
+	return append(entries, HistoryEntry{Raw: "∎", Math: "∎", English: done})
+}
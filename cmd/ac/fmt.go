@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"acornlang.dev/lang/codegen/lisp"
+	"acornlang.dev/lang/parser"
+	renderMath "acornlang.dev/lang/render/math"
+	"acornlang.dev/lang/types/ast"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+)
+
+func fmtCommand(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	notation := flags.String("notation", renderMath.ASCII.Name, "ascii, unicode, latex, common-lisp, scheme or clojure")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: ac fmt [--notation=ascii|unicode|latex|common-lisp|scheme|clojure] file.lx")
+		return 2
+	}
+	path := flags.Arg(0)
+	out, err := format(*notation, path)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", path, err)
+		return 1
+	}
+	fmt.Fprint(stdout, out)
+	return 0
+}
+
+// format prints each statement of the file on its own line with its
+// boolean expressions in the notation; string expressions keep their
+// source text.
+func format(notationName string, path string) (string, error) {
+	render, err := renderer(notationName)
+	if err != nil {
+		return "", err
+	}
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	file, err := parser.FileParser.ParseBytes("", source)
+	if err != nil {
+		return "", err
+	}
+	exprs := []*ast.Expr{file.Head}
+	ends := []int{}
+	for _, tail := range file.Tail {
+		exprs = append(exprs, tail.Expr)
+		ends = append(ends, tail.ExprTerminator.Pos.Offset)
+	}
+	if file.Terminator != nil {
+		ends = append(ends, file.Terminator.Pos.Offset)
+	} else {
+		ends = append(ends, len(source))
+	}
+	var sb strings.Builder
+	for idx, expr := range exprs {
+		text := strings.TrimSpace(string(source[expr.Pos.Offset:ends[idx]]))
+		sb.WriteString(formatExpr(expr, render, text) + "\n")
+	}
+	return sb.String(), nil
+}
+
+// renderer prints in a math notation, or as S-expressions in a Lisp
+// flavour.
+func renderer(name string) (func(*booleanAst.Expr) string, error) {
+	names := []string{}
+	for _, notation := range renderMath.NOTATIONS {
+		if notation.Name == name {
+			return notation.Render, nil
+		}
+		names = append(names, notation.Name)
+	}
+	for _, flavour := range lisp.FLAVOURS {
+		if flavour.Name == name {
+			return flavour.Format, nil
+		}
+		names = append(names, flavour.Name)
+	}
+	return nil, fmt.Errorf("unknown notation '%s', expected %s or %s", name, strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
+
+func formatExpr(expr *ast.Expr, render func(*booleanAst.Expr) string, source string) string {
+	switch {
+	case expr.Def != nil && expr.Def.Expr != nil:
+		return fmt.Sprintf("let %s := %s", expr.Def.Name, render(expr.Def.Expr))
+	case expr.Rule != nil:
+		return fmt.Sprintf("rule %s: %s -> %s", expr.Rule.Name, render(expr.Rule.Lhs), render(expr.Rule.Rhs))
+	case expr.Bool != nil:
+		return render(expr.Bool)
+	default:
+		return source
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cgen "acornlang.dev/lang/codegen/c"
+	"acornlang.dev/lang/codegen/circuit"
+	"acornlang.dev/lang/codegen/elixir"
+	"acornlang.dev/lang/codegen/golang"
+	"acornlang.dev/lang/codegen/program"
+	"acornlang.dev/lang/codegen/python"
+	"acornlang.dev/lang/codegen/raku"
+	"acornlang.dev/lang/codegen/ruby"
+	"acornlang.dev/lang/codegen/typescript"
+	"acornlang.dev/lang/codegen/verilog"
+	"acornlang.dev/lang/codegen/vhdl"
+	"acornlang.dev/lang/parser"
+	"acornlang.dev/lang/types/check"
+)
+
+func genCommand(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	target := flags.String("target", "", "output language: verilog, vhdl, c, python, ruby, raku, elixir, go or typescript")
+	module := flags.String("module", "", "module name (default: file name)")
+	pkg := flags.String("pkg", "", "Go package name (default: module name)")
+	outDir := flags.String("out", "", "directory to write the files to (default: standard output for one file other than Go, else the current directory)")
+	harness := flags.Bool("harness", false, "also write a test checking the output against the evaluator (c, python and go)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(stderr, "usage: ac gen --target=verilog|vhdl|c|python|ruby|raku|elixir|go|typescript [--module=name] [--pkg=name] [--out=dir] [--harness] file.lx")
+		return 2
+	}
+	path := flags.Arg(0)
+	files, err := gen(*target, *module, *pkg, *harness, path)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", path, err)
+		return 1
+	}
+	// Go output is always written, so that go:generate leaves a file behind.
+	if len(files) == 1 && *outDir == "" && *target != "go" {
+		fmt.Fprint(stdout, files[0].Text)
+		return 0
+	}
+	for _, file := range files {
+		name := filepath.Join(*outDir, file.Name)
+		if err := os.WriteFile(name, []byte(file.Text), 0o644); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		fmt.Fprintln(stdout, name)
+	}
+	return 0
+}
+
+// genFile is one file a target emits.
+type genFile struct {
+	Name string
+	Text string
+}
+
+func gen(target string, module string, pkg string, harness bool, path string) ([]genFile, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := parser.FileParser.ParseBytes("", source)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := check.File(file); err != nil {
+		return nil, err
+	}
+	if module == "" {
+		module = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if harness && target != "c" && target != "python" && target != "go" {
+		return nil, errors.New("--harness is only for --target=c, python or go")
+	}
+	if pkg != "" && target != "go" {
+		return nil, errors.New("--pkg is only for --target=go")
+	}
+	switch target {
+	case "verilog":
+		c, err := circuit.FromFile(module, file)
+		if err != nil {
+			return nil, err
+		}
+		return []genFile{{module + ".v", verilog.Generate(c)}}, nil
+	case "vhdl":
+		c, err := circuit.FromFile(module, file)
+		if err != nil {
+			return nil, err
+		}
+		return []genFile{{module + ".vhd", vhdl.Generate(c)}}, nil
+	case "c":
+		p, err := program.FromFile(module, file, "C")
+		if err != nil {
+			return nil, err
+		}
+		header, source := cgen.Generate(p)
+		files := []genFile{{module + ".h", header}, {module + ".c", source}}
+		if harness {
+			text, err := cgen.Harness(p)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, genFile{module + "_test.c", text})
+		}
+		return files, nil
+	case "python":
+		p, err := program.FromFile(module, file, "Python")
+		if err != nil {
+			return nil, err
+		}
+		name := python.Module(p)
+		files := []genFile{{name + ".py", python.Generate(p)}}
+		if harness {
+			text, err := python.Tests(p)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, genFile{"test_" + name + ".py", text})
+		}
+		return files, nil
+	case "ruby":
+		p, err := program.FromFile(module, file, "Ruby")
+		if err != nil {
+			return nil, err
+		}
+		return []genFile{{module + ".rb", ruby.Generate(p)}}, nil
+	case "raku":
+		p, err := program.FromFile(module, file, "Raku")
+		if err != nil {
+			return nil, err
+		}
+		return []genFile{{raku.Module(p) + ".rakumod", raku.Generate(p)}}, nil
+	case "elixir":
+		p, err := program.FromFile(module, file, "Elixir")
+		if err != nil {
+			return nil, err
+		}
+		return []genFile{{module + ".ex", elixir.Generate(p)}}, nil
+	case "go":
+		p, err := program.FromFile(module, file, "Go")
+		if err != nil {
+			return nil, err
+		}
+		p.ReadDocs(source)
+		if pkg == "" {
+			pkg = golang.Package(module)
+		}
+		files := []genFile{{module + "_gen.go", golang.Generate(p, pkg)}}
+		if harness {
+			text, err := golang.Tests(p, pkg)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, genFile{module + "_gen_test.go", text})
+		}
+		return files, nil
+	case "typescript":
+		p, err := program.FromFile(module, file, "TypeScript")
+		if err != nil {
+			return nil, err
+		}
+		p.ReadDocs(source)
+		return []genFile{{module + ".ts", typescript.Generate(p)}}, nil
+	case "":
+		return nil, errors.New("missing --target")
+	default:
+		return nil, fmt.Errorf("unknown target '%s'", target)
+	}
+}
@@ -1,18 +1,54 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/alecthomas/participle/v2"
 	"github.com/gdamore/tcell/v2"
 
+	"acornlang.dev/lang/analysis/fuzzy"
+	"acornlang.dev/lang/analysis/prove"
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/parser/bits"
 	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/parser/data"
+	"acornlang.dev/lang/parser/sets"
+	parserStrings "acornlang.dev/lang/parser/strings"
+	"acornlang.dev/lang/render/english"
+	renderMath "acornlang.dev/lang/render/math"
 	"acornlang.dev/lang/repl"
+	"acornlang.dev/lang/types"
+	arithAst "acornlang.dev/lang/types/ast/arith"
+	bitsAst "acornlang.dev/lang/types/ast/bits"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	dataAst "acornlang.dev/lang/types/ast/data"
+	setsAst "acornlang.dev/lang/types/ast/sets"
+	stringsAst "acornlang.dev/lang/types/ast/strings"
+	"acornlang.dev/lang/types/check"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "fmt":
+			os.Exit(fmtCommand(os.Args[2:], os.Stdout, os.Stderr))
+		case "gen":
+			os.Exit(genCommand(os.Args[2:], os.Stdout, os.Stderr))
+		case "kmap":
+			os.Exit(kmapCommand(os.Args[2:], os.Stdout, os.Stderr))
+		case "ltl":
+			os.Exit(ltlCommand(os.Args[2:], os.Stdout, os.Stderr))
+		case "table":
+			os.Exit(tableCommand(os.Args[2:], os.Stdout, os.Stderr))
+		case "tree":
+			os.Exit(treeCommand(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
 	interactiveRepl()
 }
 
@@ -20,6 +56,7 @@ type HistoryEntry struct {
 	Raw     string
 	Math    string
 	English string
+	Marks   []int
 }
 
 type DisplayMode int
@@ -100,6 +137,10 @@ func interactiveRepl() {
 			case ENG:
 				line = entry.English
 			}
+			if entry.Marks != nil {
+				yOffset += drawMarkedText(screen, 0, yOffset, line, entry.Marks, width)
+				continue
+			}
 			yOffset += drawText(screen, 0, yOffset, tcell.StyleDefault, line, width)
 		}
 
@@ -138,18 +179,24 @@ func interactiveRepl() {
 
 				fullExpression := strings.Join(wrapText(mathInput, width), " ")
 
-				evaluated, newCtx := LXEvalPrint(fullExpression, ctx)
-				ctx = newCtx
-
 				history = append(history, HistoryEntry{
 					Raw:     prompt + rawInput,
 					Math:    prompt + mathInput,
 					English: prompt + englishInput,
 				})
 
-				evaluatedLines := strings.Split(evaluated, "\n")
-				for _, line := range evaluatedLines {
-					history = append(history, HistoryEntry{Raw: line, Math: line, English: line})
+				if isRule(fullExpression) {
+					declared, newCtx := LXDeclareRule(fullExpression, ctx)
+					ctx = newCtx
+					history = append(history, textEntries(declared)...)
+				} else if isCommand(fullExpression) {
+					entries, newCtx := LXCommand(fullExpression, ctx)
+					ctx = newCtx
+					history = append(history, entries...)
+				} else {
+					evaluated, newCtx := LXEvalPrintEntries(fullExpression, ctx)
+					ctx = newCtx
+					history = append(history, evaluated...)
 				}
 
 				inputHistory = append(inputHistory, rawInput)
@@ -207,22 +254,6 @@ func interactiveRepl() {
 	}
 }
 
-func convertHistoryToMode(history []string, modeIndex int) []string {
-	newHistory := make([]string, len(history))
-
-	for i, line := range history {
-		if modeIndex == 1 {
-			newHistory[i] = replaceToMath(line)
-		} else if modeIndex == 2 {
-			newHistory[i] = replaceToEnglish(line)
-		} else {
-			newHistory[i] = line
-		}
-	}
-
-	return newHistory
-}
-
 func drawText(s tcell.Screen, x, y int, style tcell.Style, text string, width int) int {
 	lines := wrapText(text, width)
 	for i, line := range lines {
@@ -267,68 +298,357 @@ func wrapText(text string, width int) []string {
 	return lines
 }
 
-func replaceToMath(input string) string {
-	replacements := map[string]string{
-		"there exists": "∃",
-		"for all":      "∀",
-		"and":          "/\\",
-		"or":           "\\/",
-		"not":          "~",
-		"implies":      "=>",
-		"iff":          "<=>",
+// stringLiteral matches a string literal, or an unfinished one at the end
+// of the input.
+var stringLiteral = regexp.MustCompile(`"(\\.|[^"\\])*("|$)`)
+
+// outsideStrings applies replace to the input between string literals, so
+// the text of a string survives switching display modes.
+func outsideStrings(input string, replace func(string) string) string {
+	acc := ""
+	last := 0
+	for _, loc := range stringLiteral.FindAllStringIndex(input, -1) {
+		acc += replace(input[last:loc[0]]) + input[loc[0]:loc[1]]
+		last = loc[1]
 	}
+	return acc + replace(input[last:])
+}
 
-	acc := input
-	for oldStr, newStr := range replacements {
-		searchRegex := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(oldStr) + `\b`)
-		acc = searchRegex.ReplaceAllString(acc, newStr)
+// exprCommands take an expression, up to a `|` that starts arguments of
+// their own, such as weights, domains or a trace file.
+var exprCommands = map[string]bool{
+	KMAP_COMMAND:    true,
+	COUNT_COMMAND:   true,
+	PROB_COMMAND:    true,
+	LTL_COMMAND:     true,
+	TREE_COMMAND:    true,
+	REWRITE_COMMAND: true,
+	ONCE_COMMAND:    true,
+	STEP_COMMAND:    true,
+	TYPE_COMMAND:    true,
+}
+
+// replaceCommand passes the expressions of a `:command` line through
+// replace and keeps the command and its other arguments as typed.
+func replaceCommand(input string, replace func(string) string) string {
+	name, arg, found := strings.Cut(input, " ")
+	if !found {
+		return input
 	}
-	return acc
+	switch {
+	case name == PROVE_COMMAND:
+		lhs, rhs, err := prove.SplitEquation(arg)
+		if err != nil {
+			return input
+		}
+		return name + " " + replace(lhs) + " = " + replace(rhs)
+	case exprCommands[name]:
+		exprInput, rest, cut := strings.Cut(arg, "|")
+		if !cut {
+			return name + " " + keepSpace(arg, replace)
+		}
+		return name + " " + keepSpace(exprInput, replace) + "|" + rest
+	default:
+		return input
+	}
+}
+
+// keepSpace applies replace to input without the whitespace around it.
+func keepSpace(input string, replace func(string) string) string {
+	trimmed := strings.TrimSpace(input)
+	if trimmed == "" {
+		return input
+	}
+	start := strings.Index(input, trimmed)
+	return input[:start] + replace(trimmed) + input[start+len(trimmed):]
+}
+
+// replaceToMath prints a whole expression in the ASCII notation, which
+// the parser reads back, and spells the operators of anything else.
+func replaceToMath(input string) string {
+	if isCommand(input) {
+		return replaceCommand(input, replaceToMath)
+	}
+	if parsed, err := boolean.ExprParser.ParseString("", input); err == nil {
+		return renderMath.ASCII.Render(parsed)
+	}
+	return outsideStrings(input, renderMath.ASCII.Spell)
 }
 
+// replaceToEnglish reads a whole expression out in English, and spells
+// the operators of anything else, such as a line still being typed, as
+// words.
 func replaceToEnglish(input string) string {
-	replacements := map[string]string{
-		"∃":   " there exists ",
-		"∀":   " for all ",
-		"/\\": " and ",
-		"\\/": " or ",
-		"~":   " not ",
-		"<=>": " iff ",
-	}
-
-	acc := input
-	for oldStr, newStr := range replacements {
-		searchRegex := regexp.MustCompile(regexp.QuoteMeta(oldStr))
-		acc = searchRegex.ReplaceAllString(acc, newStr)
-	}
-	// This is for the `$n ==> result` line
-	acc = regexp.MustCompile(`\b=>\b`).ReplaceAllString(acc, " implies ")
-	acc = regexp.MustCompile(`\s+`).ReplaceAllString(acc, " ")
-	return acc
+	if isCommand(input) {
+		return replaceCommand(input, replaceToEnglish)
+	}
+	if parsed, err := boolean.ExprParser.ParseString("", input); err == nil {
+		return english.Render(parsed)
+	}
+	return outsideStrings(input, english.Words)
+}
+
+// LXEvalPrintEntries is LXEvalPrint with tuples and lists shown in the
+// notation of each display mode.
+func LXEvalPrintEntries(input string, ctx *repl.ReplContext) ([]HistoryEntry, *repl.ReplContext) {
+	if _, err := boolean.ExprParser.ParseString("", input); err != nil {
+		if expr, err := data.ExprParser.ParseString("", input); err == nil && expr.Bool == nil {
+			return lxEvalPrintData(expr, ctx)
+		}
+	}
+	evaluated, newCtx := LXEvalPrint(input, ctx)
+	return textEntries(evaluated), newCtx
 }
 
+// letPattern matches a definition typed at the prompt, `let name := expr`.
+var letPattern = regexp.MustCompile(`^\s*let\s+([A-Za-z_][A-Za-z0-9_]*)\s*:=(.*)$`)
+
+// LXEvalPrint evaluates a line under the `let` bindings made so far. A
+// `let` line binds its name to the value of the expression after `:=`,
+// whichever kind of value that is.
 func LXEvalPrint(input string, ctx *repl.ReplContext) (string, *repl.ReplContext) {
+	if match := letPattern.FindStringSubmatch(input); match != nil {
+		val, errText := evalValue(strings.TrimSpace(match[2]), ctx)
+		if errText != "" {
+			return errText, ctx
+		}
+		typ := check.Binding(match[1], val)
+		return fmt.Sprintf("%s : %s := %s", match[1], typ, val), ctx.WithBinding(match[1], val)
+	}
+	val, errText := evalValue(input, ctx)
+	if errText != "" {
+		return errText, ctx
+	}
+	return fmt.Sprintf("$%d : %s ==> %s", ctx.ExprNum(), val.Type(), val), ctx.BumpExprNum()
+}
+
+// evalValue evaluates input with the first parser that accepts it. On
+// failure it returns the error text to print instead.
+func evalValue(input string, ctx *repl.ReplContext) (types.Value, string) {
+	if str, err := parseString(input, checkEnv(ctx)); err == nil {
+		return evalString(str, ctx)
+	} else if strings.HasPrefix(strings.TrimSpace(input), `"`) {
+		return nil, fmt.Sprintf("|  Error:\n|  illegal expression\n|  %s\n|  ^", input)
+	}
 	parsed, err := boolean.ExprParser.ParseString("", input)
 	if err != nil {
-		return fmt.Sprintf("|  Error:\n|  illegal expression\n|  %s\n|  ^", input), ctx
+		if text, ok := comparisonError(err); ok {
+			return nil, text
+		}
+		if sum, err := arith.ExprParser.ParseString("", input); err == nil {
+			return evalInt(sum, ctx)
+		}
+		if vector, err := bits.ExprParser.ParseString("", input); err == nil {
+			return evalBits(vector, ctx)
+		}
+		if set, err := sets.ExprParser.ParseString("", input); err == nil {
+			return evalSet(set, ctx)
+		}
+		if expr, err := data.ExprParser.ParseString("", input); err == nil {
+			return evalData(expr, ctx)
+		}
+		return nil, fmt.Sprintf("|  Error:\n|  illegal expression\n|  %s\n|  ^", input)
 	}
+	return evalBool(parsed, ctx)
+}
 
-	parseResult := boolean.EvalExpr(parsed)
+func evalBool(parsed *booleanAst.Expr, ctx *repl.ReplContext) (types.Value, string) {
+	typ, err := check.Expr(parsed, checkEnv(ctx))
+	if err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	// Operators only give truth values, so any other type is a bare name.
+	if !typ.Equal(types.BOOL) && !typ.Equal(types.DEGREE) {
+		return ctx.Bindings()[boolean.FreeVars(parsed)[0]], ""
+	}
+
+	var parseResult boolean.EvalResult
+	if ctx.Logic() == repl.DEFAULT_LOGIC {
+		for _, name := range boolean.FreeVars(parsed) {
+			if degree, ok := ctx.Bindings()[name].(types.Degree); ok {
+				return nil, fmt.Sprintf("|  Error:\n|  '%s' is the truth degree %s, which needs a fuzzy logic", name, degree)
+			}
+		}
+		parseResult = boolean.EvalExpr(parsed, booleanEnv(ctx))
+	} else {
+		family, err := fuzzy.ParseFamily(ctx.Logic())
+		if err != nil {
+			return nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+		}
+		parseResult = fuzzy.EvalExpr(parsed, fuzzyEnv(ctx), family)
+	}
 	if parseResult.Err != nil {
-		return fmt.Sprintf("|  Error:\n|  %s", parseResult.Err.Error()), ctx
-	}
-	payload := parseResult.Payload
-	var printablePayload string
-	switch payload {
-	case true:
-		printablePayload = "True"
-	case false:
-		printablePayload = "False"
+		return nil, fmt.Sprintf("|  Error:\n|  %s", parseResult.Err.Error())
+	}
+	return parseResult.Payload, ""
+}
+
+// comparisonError reports a line that failed as a comparison with a truth
+// degree on one side, such as `x > 0.5`. Any other parse error leaves the
+// line to the parsers tried after the boolean one.
+func comparisonError(err error) (string, bool) {
+	var parseErr *participle.ParseError
+	if !errors.As(err, &parseErr) {
+		return "", false
+	}
+	return fmt.Sprintf("|  Error:\n|  %s", err.Error()), true
+}
+
+// namesPattern matches a comparison of two bare names, `s == t`.
+var namesPattern = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*)\s*(==|!=)\s*([A-Za-z_][A-Za-z0-9_]*)\s*$`)
+
+// parseString parses a string expression. The parser leaves a comparison
+// of two bare names to the integers, so one between names env holds as
+// strings is built here.
+func parseString(input string, env check.Env) (*stringsAst.Expr, error) {
+	at := namesPattern.FindStringSubmatchIndex(input)
+	if at == nil {
+		return parserStrings.ExprParser.ParseString("", input)
+	}
+	lhs, op, rhs := input[at[2]:at[3]], input[at[4]:at[5]], input[at[6]:at[7]]
+	if !types.STRING.Equal(env[lhs]) || !types.STRING.Equal(env[rhs]) {
+		return parserStrings.ExprParser.ParseString("", input)
+	}
+	pos := func(offset int) types.Position {
+		return types.Position{Offset: offset, Line: 1, Column: utf8.RuneCountInString(input[:offset]) + 1}
+	}
+	return &stringsAst.Expr{
+		Pos:  pos(at[2]),
+		Head: &stringsAst.Operand{Pos: pos(at[2]), Var: lhs},
+		Compare: &stringsAst.CompareRest{
+			Pos:   pos(at[4]),
+			Op:    op,
+			Right: &stringsAst.Concat{Pos: pos(at[6]), Head: &stringsAst.Operand{Pos: pos(at[6]), Var: rhs}},
+		},
+	}, nil
+}
+
+func evalString(parsed *stringsAst.Expr, ctx *repl.ReplContext) (types.Value, string) {
+	if _, err := check.String(parsed, checkEnv(ctx)); err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	res := parserStrings.EvalExpr(parsed, stringsEnv(ctx))
+	if res.Err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", res.Err.Error())
+	}
+	return res.Payload, ""
+}
+
+func evalInt(sum *arithAst.Sum, ctx *repl.ReplContext) (types.Value, string) {
+	if _, err := check.Sum(sum, checkEnv(ctx)); err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	val, err := arith.EvalSum(sum, arithEnv(ctx))
+	if err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	return val, ""
+}
+
+func evalBits(vector *bitsAst.Expr, ctx *repl.ReplContext) (types.Value, string) {
+	if _, err := check.Bits(vector, checkEnv(ctx)); err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	res := bits.EvalExpr(vector, bitsEnv(ctx))
+	if res.Err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", res.Err.Error())
+	}
+	return res.Payload, ""
+}
+
+func evalSet(set *setsAst.Expr, ctx *repl.ReplContext) (types.Value, string) {
+	if _, err := check.Set(set, checkEnv(ctx)); err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	res := sets.EvalExpr(set, setsEnv(ctx))
+	if res.Err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", res.Err.Error())
+	}
+	return res.Payload, ""
+}
+
+func evalData(expr *dataAst.Expr, ctx *repl.ReplContext) (types.Value, string) {
+	if _, err := check.Data(expr, checkEnv(ctx)); err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	val, err := data.Eval(expr, data.Env(ctx.Bindings()))
+	if err != nil {
+		return nil, fmt.Sprintf("|  Error:\n|  %s", err.Error())
+	}
+	return val, ""
+}
+
+func lxEvalPrintData(expr *dataAst.Expr, ctx *repl.ReplContext) ([]HistoryEntry, *repl.ReplContext) {
+	val, errText := evalData(expr, ctx)
+	if errText != "" {
+		return textEntries(errText), ctx
+	}
+	header := fmt.Sprintf("$%d : %s ==> ", ctx.ExprNum(), val.Type())
+	entry := HistoryEntry{
+		Raw:     header + val.String(),
+		Math:    header + mathValue(val),
+		English: header + englishValue(val),
+	}
+	return []HistoryEntry{entry}, ctx.BumpExprNum()
+}
+
+// mathValue writes truth values as ⊤ and ⊥ and tuples in angle brackets.
+func mathValue(val types.Value) string {
+	switch val := val.(type) {
+	case types.Bool:
+		if val {
+			return "⊤"
+		}
+		return "⊥"
+	case types.Tuple:
+		return "⟨" + strings.Join(mapValues(val.Elems, mathValue), ", ") + "⟩"
+	case types.List:
+		return "[" + strings.Join(mapValues(val.Elems, mathValue), ", ") + "]"
 	default:
-		printablePayload = fmt.Sprintf("%t", payload)
+		return val.String()
 	}
+}
 
-	return fmt.Sprintf("$%d ==> %s", ctx.ExprNum(), printablePayload), ctx.BumpExprNum()
+// englishValue reads a value out: `(True, [False])` is "the pair of true
+// and the list of just false".
+func englishValue(val types.Value) string {
+	switch val := val.(type) {
+	case types.Bool:
+		return strings.ToLower(val.String())
+	case types.Tuple:
+		names := map[int]string{2: "pair", 3: "triple"}
+		name, ok := names[len(val.Elems)]
+		if !ok {
+			name = fmt.Sprintf("%d-tuple", len(val.Elems))
+		}
+		return "the " + name + " of " + englishList(mapValues(val.Elems, englishValue))
+	case types.List:
+		switch len(val.Elems) {
+		case 0:
+			return "the empty list"
+		case 1:
+			return "the list of just " + englishValue(val.Elems[0])
+		default:
+			return "the list of " + englishList(mapValues(val.Elems, englishValue))
+		}
+	default:
+		return val.String()
+	}
+}
+
+func englishList(words []string) string {
+	if len(words) < 2 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
+
+func mapValues(vals []types.Value, f func(types.Value) string) []string {
+	acc := make([]string, len(vals))
+	for idx, val := range vals {
+		acc[idx] = f(val)
+	}
+	return acc
 }
 
 func min(a, b int) int {
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+
+	"acornlang.dev/lang/repl"
+)
+
+// session feeds lines to the REPL in order and returns what each printed.
+func session(lines ...string) []string {
+	ctx := repl.NewReplContext()
+	acc := []string{}
+	for _, line := range lines {
+		var out string
+		if isCommand(line) {
+			var entries []HistoryEntry
+			entries, ctx = LXCommand(line, ctx)
+			out = entries[0].Raw
+		} else {
+			out, ctx = LXEvalPrint(line, ctx)
+		}
+		acc = append(acc, out)
+	}
+	return acc
+}
+
+func TestLetBindsDegrees(t *testing.T) {
+	out := session(":logic product", "let a := 0.3", "let b := 0.5", "a and b", "not a")
+	assert.Equal(t, []string{
+		"logic: fuzzy product",
+		"a : Degree := 0.3",
+		"b : Degree := 0.5",
+		"$1 : Degree ==> 0.15",
+		"$2 : Degree ==> 0.7",
+	}, out)
+}
+
+func TestLetBindsEachKindOfValue(t *testing.T) {
+	out := session(
+		"let p := True", "let q := False", "p xor q",
+		"let label := \"adder\"", "\"carry {p}, \" ++ label",
+		"let n := 40", "n + 2",
+		"let v := 0b1010", "v and 0b0110", "v",
+		"let xs := {1, 2}", "xs union {3}",
+		"let pair := (p, q)", "pair",
+	)
+	assert.Equal(t, []string{
+		"p : Bool := True", "q : Bool := False", "$1 : Bool ==> True",
+		"label : String := \"adder\"", "$2 : String ==> \"carry True, adder\"",
+		"n : Int := 40", "$3 : Int ==> 42",
+		"v : Bits[4] := 0b1010", "$4 : Bits[4] ==> 0b0010", "$5 : Bits[4] ==> 0b1010",
+		"xs : xs := {1, 2}", "$6 : Set ==> {1, 2, 3}",
+		"pair : (Bool, Bool) := (True, False)", "$7 : (Bool, Bool) ==> (True, False)",
+	}, out)
+}
+
+func TestLetErrors(t *testing.T) {
+	out := session("let a := 0.3", ":logic godel", "let a := 0.3", ":logic boolean", "a or True", "let p := q")
+	assert.Equal(t, []string{
+		"|  Error:\n|  truth degree '0.3' needs a fuzzy logic",
+		"logic: fuzzy godel",
+		"a : Degree := 0.3",
+		"logic: boolean",
+		"|  Error:\n|  'a' is the truth degree 0.3, which needs a fuzzy logic",
+		"|  Error:\n|  unbound variable 'q'",
+	}, out)
+}
+
+func TestStringsStartingWithNames(t *testing.T) {
+	out := session(
+		"let s := \"ab\"", "s ++ \"c\"", "s == \"ab\"", "let t := s ++ \"!\"", "t != s", ":type t == s", ":type s ++ \"c\"",
+		"let p := True", "p ++ \"x\"",
+	)
+	assert.Equal(t, []string{
+		"s : String := \"ab\"", "$1 : String ==> \"abc\"", "$2 : Bool ==> True",
+		"t : String := \"ab!\"", "$3 : Bool ==> True", "t == s : Bool", "s ++ \"c\" : String",
+		"p : Bool := True", "|  Error:\n|  1:1: '++' needs String, not Bool",
+	}, out)
+}
+
+func TestTypeCommand(t *testing.T) {
+	out := session(
+		`:type "a"`, `:type "a" == "b"`,
+		"let Color := {red, green}", ":type Color", ":type red in Color", ":type Color union {blue}",
+		"let n := 3", ":type n + 1",
+	)
+	assert.Equal(t, []string{
+		`"a" : String`, `"a" == "b" : Bool`,
+		"Color : Color := {green, red}", "Color : Color", "red in Color : Bool", "Color union {blue} : Set",
+		"n : Int := 3", "n + 1 : Int",
+	}, out)
+}
+
+func TestCountPointsWeightsToProb(t *testing.T) {
+	assert.Equal(t, "|  Error:\n|  :count takes ranges 'name : lo..hi'; for weights 'name ~ probability' use :prob", countText("p and q | p ~ 0.3"))
+	assert.Equal(t, "P = 0.15", probText("p and q | p ~ 0.3"))
+	assert.Equal(t, "1 of 4 assignments (25%)", countText("p and q"))
+}
+
+func TestGenGoWritesFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	assert.NoError(t, os.WriteFile("rules.lx", []byte("let both := a and b\n"), 0o644))
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, genCommand([]string{"--target=go", "rules.lx"}, &stdout, &stderr), stderr.String())
+	assert.Equal(t, "rules_gen.go\n", stdout.String())
+	text, err := os.ReadFile(filepath.Join(dir, "rules_gen.go"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(text), "package rules\n")
+	assert.Contains(t, string(text), "func Both(")
+}
+
+func TestMarksFollowRunes(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	assert.NoError(t, screen.Init())
+	screen.SetSize(10, 1)
+	drawMarkedText(screen, 0, 0, "¬a∧b", []int{-1, 0, -1, 1}, 10)
+	for col, expected := range []struct {
+		ch    rune
+		style tcell.Style
+	}{{'¬', tcell.StyleDefault}, {'a', groupStyles[0]}, {'∧', tcell.StyleDefault}, {'b', groupStyles[1]}} {
+		ch, _, style, _ := screen.GetContent(col, 0)
+		assert.Equal(t, expected.ch, ch, "column %d", col)
+		assert.Equal(t, expected.style, style, "column %d", col)
+	}
+}
@@ -0,0 +1,71 @@
+//go:build js && wasm
+
+// Command acwasm is the evaluator built for the browser:
+//
+//	GOOS=js GOARCH=wasm go build -o acorn.wasm ./cmd/acwasm
+//
+// Run with the wasm_exec.js of the Go release, it sets globalThis.acorn to
+// an object with three functions, each giving back {ok, ...} or
+// {ok: false, error}:
+//
+//	acorn.parse("a and not b")                  // {ok, ast, vars: ["a", "b"]}
+//	acorn.eval("a xor b", {a: true, b: false})  // {ok, value: "True", type: "Bool"}
+//	acorn.render("p => q", "unicode")           // {ok, text: "p → q"}
+package main
+
+import (
+	"syscall/js"
+
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/wasm"
+)
+
+// env reads the boolean properties of a JS object.
+func env(obj js.Value) boolean.Env {
+	acc := boolean.Env{}
+	if obj.Type() != js.TypeObject {
+		return acc
+	}
+	keys := js.Global().Get("Object").Call("keys", obj)
+	for idx := 0; idx < keys.Length(); idx++ {
+		key := keys.Index(idx).String()
+		if val := obj.Get(key); val.Type() == js.TypeBoolean {
+			acc[key] = val.Bool()
+		}
+	}
+	return acc
+}
+
+func arg(args []js.Value, idx int) js.Value {
+	if idx < len(args) {
+		return args[idx]
+	}
+	return js.Undefined()
+}
+
+func text(val js.Value) string {
+	if val.Type() != js.TypeString {
+		return ""
+	}
+	return val.String()
+}
+
+func main() {
+	api := map[string]any{
+		"parse": js.FuncOf(func(this js.Value, args []js.Value) any {
+			return wasm.Parse(text(arg(args, 0)))
+		}),
+		"eval": js.FuncOf(func(this js.Value, args []js.Value) any {
+			return wasm.Eval(text(arg(args, 0)), env(arg(args, 1)))
+		}),
+		"render": js.FuncOf(func(this js.Value, args []js.Value) any {
+			notation := text(arg(args, 1))
+			if notation == "" {
+				notation = "ascii"
+			}
+			return wasm.Render(text(arg(args, 0)), notation)
+		}),
+	}
+	js.Global().Set("acorn", js.ValueOf(api))
+	select {}
+}
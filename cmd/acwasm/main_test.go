@@ -0,0 +1,36 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"syscall/js"
+	"testing"
+
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/wasm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvReadsBooleans(t *testing.T) {
+	obj := js.ValueOf(map[string]any{"a": true, "b": false, "n": 1, "s": "x"})
+	assert.Equal(t, boolean.Env{"a": true, "b": false}, env(obj))
+	assert.Equal(t, boolean.Env{}, env(js.Undefined()))
+}
+
+func TestResultsConvert(t *testing.T) {
+	eval := js.ValueOf(wasm.Eval("a xor b", env(js.ValueOf(map[string]any{"a": true, "b": false}))))
+	assert.Equal(t, true, eval.Get("ok").Bool())
+	assert.Equal(t, "True", eval.Get("value").String())
+	assert.Equal(t, "Bool", eval.Get("type").String())
+
+	parse := js.ValueOf(wasm.Parse("a and not b"))
+	assert.Equal(t, 2, parse.Get("vars").Length())
+	assert.Equal(t, "and", parse.Get("ast").Get("Rest").Get("Op").String())
+
+	render := js.ValueOf(wasm.Render("p => q", "unicode"))
+	assert.Equal(t, "p → q", render.Get("text").String())
+
+	failed := js.ValueOf(wasm.Eval("a or c", boolean.Env{"a": false}))
+	assert.Equal(t, false, failed.Get("ok").Bool())
+	assert.Equal(t, "unbound variable 'c'", failed.Get("error").String())
+}
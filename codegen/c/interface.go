@@ -0,0 +1,144 @@
+package c
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"acornlang.dev/lang/codegen/program"
+	"acornlang.dev/lang/codegen/syntax"
+)
+
+// MAX_HARNESS_PARAMS bounds the truth tables the harness spells out.
+const MAX_HARNESS_PARAMS = 16
+
+// C brackets every compound operand but a run of && or ||, so neither
+// C's precedence nor -Wparentheses comes into it. Names are kept clear
+// of C keywords, of the macros in <stdbool.h> and of the identifiers C
+// reserves, which start with an underscore.
+var C = syntax.Language{
+	Ops:    map[syntax.Op]string{syntax.Not: "!", syntax.And: "&&", syntax.Or: "||", syntax.Eq: "==", syntax.Ne: "!="},
+	Levels: map[syntax.Op]int{syntax.Not: 2, syntax.Eq: 1, syntax.Ne: 1, syntax.And: 1, syntax.Or: 1},
+	True:   "true",
+	False:  "false",
+	Reserved: map[string]bool{
+		"auto": true, "bool": true, "break": true, "case": true, "char": true,
+		"const": true, "continue": true, "default": true, "do": true,
+		"double": true, "else": true, "enum": true, "extern": true, "false": true,
+		"float": true, "for": true, "goto": true, "if": true, "inline": true,
+		"int": true, "long": true, "main": true, "register": true,
+		"restrict": true, "return": true, "short": true, "signed": true,
+		"sizeof": true, "static": true, "struct": true, "switch": true,
+		"true": true, "typedef": true, "union": true, "unsigned": true,
+		"void": true, "volatile": true, "while": true, "_Bool": true,
+		"_Complex": true, "_Imaginary": true,
+	},
+	Ident:  regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`),
+	Prefix: "v_",
+}
+
+// Regd. Generation
+
+// generator names the exported functions after the program, so two
+// generated files can be linked together.
+type generator struct {
+	pr     *syntax.Printer
+	prefix string
+	public map[string]string
+}
+
+func newGenerator(p *program.Program) *generator {
+	module := C
+	module.Prefix = "m_"
+	g := &generator{pr: syntax.NewPrinter(&C, p), prefix: syntax.NewNamer(&module).Name(p.Name), public: map[string]string{}}
+	for _, fn := range p.Functions {
+		g.public[fn.Name] = g.pr.Names.Name(g.prefix + "_" + fn.Name)
+	}
+	return g
+}
+
+func (g *generator) signature(name string, params []string) string {
+	acc := []string{}
+	for _, param := range params {
+		acc = append(acc, "bool "+g.pr.Var(param))
+	}
+	if len(acc) == 0 {
+		acc = append(acc, "void")
+	}
+	return fmt.Sprintf("bool %s(%s)", name, strings.Join(acc, ", "))
+}
+
+func (g *generator) call(name string, params []string) string {
+	args := []string{}
+	for _, param := range params {
+		args = append(args, g.pr.Var(param))
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+}
+
+// Generate emits a C99 header declaring one function per definition,
+// named after the program, and a source file defining them through a
+// `static inline` function each.
+func Generate(p *program.Program) (string, string) {
+	g := newGenerator(p)
+	guard := strings.ToUpper(g.prefix) + "_H"
+	var header strings.Builder
+	header.WriteString("/* Generated by ac gen. Do not edit. */\n")
+	fmt.Fprintf(&header, "#ifndef %s\n#define %s\n\n#include <stdbool.h>\n\n", guard, guard)
+	for _, fn := range p.Functions {
+		fmt.Fprintf(&header, "%s;\n", g.signature(g.public[fn.Name], fn.Params))
+	}
+	fmt.Fprintf(&header, "\n#endif /* %s */\n", guard)
+
+	var source strings.Builder
+	source.WriteString("/* Generated by ac gen. Do not edit. */\n")
+	fmt.Fprintf(&source, "#include \"%s.h\"\n", p.Name)
+	for _, fn := range p.Functions {
+		body, used := g.pr.Body(fn)
+		fmt.Fprintf(&source, "\nstatic inline %s {\n", g.signature(g.pr.Names.Name(fn.Name), fn.Params))
+		// `a left b` and `nullify a` do not read every parameter.
+		for _, param := range fn.Params {
+			if !used[param] {
+				fmt.Fprintf(&source, "    (void)%s;\n", g.pr.Var(param))
+			}
+		}
+		fmt.Fprintf(&source, "    return %s;\n}\n", body)
+	}
+	for _, fn := range p.Functions {
+		fmt.Fprintf(&source, "\n%s {\n    return %s;\n}\n", g.signature(g.public[fn.Name], fn.Params), g.call(g.pr.Names.Name(fn.Name), fn.Params))
+	}
+	return header.String(), source.String()
+}
+
+// Regd. Harness
+
+// Harness emits a C program that calls every function of p on every
+// assignment of its parameters and compares the result with the truth
+// table boolean.EvalExpr gives. It exits with 1 after reporting each
+// mismatch, and 0 when there is none.
+func Harness(p *program.Program) (string, error) {
+	g := newGenerator(p)
+	var sb strings.Builder
+	sb.WriteString("/* Generated by ac gen. Do not edit. */\n")
+	fmt.Fprintf(&sb, "#include <stdio.h>\n\n#include \"%s.h\"\n\nint main(void) {\n    int failures = 0;\n", p.Name)
+	for idx, fn := range p.Functions {
+		if len(fn.Params) > MAX_HARNESS_PARAMS {
+			return "", fmt.Errorf("'%s' has %d inputs, the harness takes at most %d", fn.Name, len(fn.Params), MAX_HARNESS_PARAMS)
+		}
+		table, err := p.TruthTable(idx)
+		if err != nil {
+			return "", err
+		}
+		args := []string{}
+		for bit := range fn.Params {
+			args = append(args, fmt.Sprintf("(m >> %d) & 1u", len(fn.Params)-1-bit))
+		}
+		fmt.Fprintf(&sb, "    {\n        static const char expected[] = \"%s\";\n", table)
+		fmt.Fprintf(&sb, "        for (unsigned long m = 0; m < %dul; m++) {\n", len(table))
+		fmt.Fprintf(&sb, "            if (%s(%s) != (expected[m] == '1')) {\n", g.public[fn.Name], strings.Join(args, ", "))
+		fmt.Fprintf(&sb, "                printf(\"%s: wrong at assignment %%lu\\n\", m);\n", g.public[fn.Name])
+		sb.WriteString("                failures++;\n            }\n        }\n    }\n")
+	}
+	sb.WriteString("    return failures == 0 ? 0 : 1;\n}\n")
+	return sb.String(), nil
+}
@@ -0,0 +1,108 @@
+package c
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"acornlang.dev/lang/codegen/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHalfAdder(t *testing.T) {
+	header, source := Generate(testutil.Program(t, "C", "half_adder", "let sum := a xor b\nlet carry := a and b"))
+	assert.Equal(t, `/* Generated by ac gen. Do not edit. */
+#ifndef HALF_ADDER_H
+#define HALF_ADDER_H
+
+#include <stdbool.h>
+
+bool half_adder_sum(bool a, bool b);
+bool half_adder_carry(bool a, bool b);
+
+#endif /* HALF_ADDER_H */
+`, header)
+	assert.Equal(t, `/* Generated by ac gen. Do not edit. */
+#include "half_adder.h"
+
+static inline bool sum(bool a, bool b) {
+    return a != b;
+}
+
+static inline bool carry(bool a, bool b) {
+    return a && b;
+}
+
+bool half_adder_sum(bool a, bool b) {
+    return sum(a, b);
+}
+
+bool half_adder_carry(bool a, bool b) {
+    return carry(a, b);
+}
+`, source)
+}
+
+func TestDefinitionsCallDefinitions(t *testing.T) {
+	_, source := Generate(testutil.Program(t, "C", "m", "let p := a and b\nlet q := not (p or c) => True\nlet k := False"))
+	assert.Contains(t, source, "static inline bool q(bool a, bool b, bool c) {\n    return p(a, b) || c || true;\n}")
+	assert.Contains(t, source, "static inline bool k(void) {\n    return false;\n}")
+}
+
+func TestMixedOperatorsAreBracketed(t *testing.T) {
+	_, source := Generate(testutil.Program(t, "C", "m", "let p := (a and b) or (c and d) or not (a iff b iff c)"))
+	assert.Contains(t, source, "    return (a && b) || (c && d) || !(a == (b == c));\n")
+}
+
+func TestNamesAreMangled(t *testing.T) {
+	header, source := Generate(testutil.Program(t, "C", "2-bit", "let int := _x and true_"))
+	assert.Contains(t, header, "#ifndef M_2_BIT_H")
+	assert.Contains(t, header, "bool m_2_bit_int(bool v__x, bool true_);")
+	assert.Contains(t, source, "static inline bool int_(bool v__x, bool true_) {")
+}
+
+// The harness compares every operator against the evaluator; it needs a
+// C compiler, and is skipped without one.
+func TestHarnessAgreesWithEvalExpr(t *testing.T) {
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("no C compiler")
+	}
+	input, err := os.ReadFile("../testdata/operators.lx")
+	assert.NoError(t, err)
+	p := testutil.Program(t, "C", "ops", string(input))
+	header, source := Generate(p)
+	harness, err := Harness(p)
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	for name, text := range map[string]string{"ops.h": header, "ops.c": source, "ops_test.c": harness} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644))
+	}
+	binary := filepath.Join(dir, "ops_test")
+	out, err := exec.Command(cc, "-std=c99", "-Wall", "-Wextra", "-pedantic", "-Werror", "-o", binary, filepath.Join(dir, "ops.c"), filepath.Join(dir, "ops_test.c")).CombinedOutput()
+	assert.NoError(t, err, string(out))
+	out, err = exec.Command(binary).CombinedOutput()
+	assert.NoError(t, err, string(out))
+}
+
+func TestHarnessCatchesWrongOutput(t *testing.T) {
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("no C compiler")
+	}
+	p := testutil.Program(t, "C", "m", "let p := a and b")
+	header, source := Generate(p)
+	harness, err := Harness(testutil.Program(t, "C", "m", "let p := a or b"))
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	for name, text := range map[string]string{"m.h": header, "m.c": source, "m_test.c": harness} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644))
+	}
+	binary := filepath.Join(dir, "m_test")
+	out, err := exec.Command(cc, "-std=c99", "-o", binary, filepath.Join(dir, "m.c"), filepath.Join(dir, "m_test.c")).CombinedOutput()
+	assert.NoError(t, err, string(out))
+	out, err = exec.Command(binary).CombinedOutput()
+	assert.Error(t, err)
+	assert.Equal(t, "m_p: wrong at assignment 1\nm_p: wrong at assignment 2\n", string(out))
+}
@@ -0,0 +1,303 @@
+package circuit
+
+import (
+	"fmt"
+	"strconv"
+
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ast"
+	"acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ast/walk"
+	"acornlang.dev/lang/types/ir"
+)
+
+type GateKind int
+
+const (
+	ConstFalse GateKind = iota
+	ConstTrue
+	Not
+	And
+	Nand
+	Or
+	Nor
+	Xor
+	Xnor
+)
+
+// Gate drives the signal Out from the signals in In.
+type Gate struct {
+	Out  string
+	Kind GateKind
+	In   []string
+}
+
+type Output struct {
+	Name   string
+	Signal string
+}
+
+// Circuit is a combinational netlist. Gates are in topological order, so
+// every signal is driven before it is read.
+type Circuit struct {
+	Name    string
+	Inputs  []string
+	Gates   []Gate
+	Outputs []Output
+}
+
+// Regd. Construction
+
+type builder struct {
+	circuit *Circuit
+	defs    map[string]string
+	inputs  map[string]bool
+	taken   map[string]bool
+	nextId  int
+}
+
+// FromFile builds a circuit with one output per top-level definition in
+// file. Free variables become inputs; a definition may use the definitions
+// above it. Bare expressions and string definitions are not synthesized.
+func FromFile(name string, file *ast.File) (*Circuit, error) {
+	exprs := fileExprs(file)
+	b := builder{
+		circuit: &Circuit{Name: name},
+		defs:    map[string]string{},
+		inputs:  map[string]bool{},
+		taken:   namesIn(exprs),
+	}
+	for _, expr := range exprs {
+		if expr.Def == nil || expr.Def.Str != nil {
+			continue
+		}
+		if err := b.define(expr.Def); err != nil {
+			return nil, err
+		}
+	}
+	if len(b.circuit.Outputs) == 0 {
+		return nil, fmt.Errorf("%s: no definitions to synthesize", name)
+	}
+	return b.circuit, nil
+}
+
+func fileExprs(file *ast.File) []*ast.Expr {
+	if file == nil || file.Head == nil {
+		return nil
+	}
+	exprs := []*ast.Expr{file.Head}
+	for _, tail := range file.Tail {
+		exprs = append(exprs, tail.Expr)
+	}
+	return exprs
+}
+
+func namesIn(exprs []*ast.Expr) map[string]bool {
+	names := map[string]bool{}
+	for _, expr := range exprs {
+		if expr.Def == nil {
+			continue
+		}
+		names[expr.Def.Name] = true
+		if expr.Def.Str != nil {
+			continue
+		}
+		collectNames(expr.Def.Expr, names)
+	}
+	return names
+}
+
+func collectNames(expr *boolean.Expr, names map[string]bool) {
+	if expr == nil {
+		return
+	}
+	walk.Inspect(expr, func(node walk.Node) bool {
+		if primary, ok := node.(*boolean.PrimaryExpr); ok && primary.Var != "" {
+			names[primary.Var] = true
+		}
+		return true
+	})
+}
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+func (b *builder) define(def *ast.Definition) error {
+	if _, ok := b.defs[def.Name]; ok {
+		return errorAt(def.Pos, "'%s' is already defined", def.Name)
+	}
+	if b.inputs[def.Name] {
+		return errorAt(def.Pos, "'%s' is used as an input above its definition", def.Name)
+	}
+	node, err := ir.Lower(def.Expr)
+	if err != nil {
+		return err
+	}
+	signal, err := b.node(node)
+	if err != nil {
+		return err
+	}
+	b.defs[def.Name] = signal
+	b.circuit.Outputs = append(b.circuit.Outputs, Output{Name: def.Name, Signal: signal})
+	return nil
+}
+
+func (b *builder) fresh() string {
+	for {
+		name := "w" + strconv.Itoa(b.nextId)
+		b.nextId++
+		if !b.taken[name] {
+			return name
+		}
+	}
+}
+
+func (b *builder) gate(kind GateKind, in ...string) string {
+	out := b.fresh()
+	b.circuit.Gates = append(b.circuit.Gates, Gate{Out: out, Kind: kind, In: in})
+	return out
+}
+
+func (b *builder) node(node ir.Node) (string, error) {
+	switch n := node.(type) {
+	case *ir.Var:
+		if signal, ok := b.defs[n.Name]; ok {
+			return signal, nil
+		}
+		if !b.inputs[n.Name] {
+			b.inputs[n.Name] = true
+			b.circuit.Inputs = append(b.circuit.Inputs, n.Name)
+		}
+		return n.Name, nil
+	case *ir.Const:
+		if n.Value {
+			return b.gate(ConstTrue), nil
+		}
+		return b.gate(ConstFalse), nil
+	case *ir.Cmp:
+		return "", errorAt(n.Pos, "integer comparison '%s' cannot be synthesized", arith.Format(n.Cmp))
+	case *ir.Degree:
+		return "", errorAt(n.Pos, "truth degree '%s' needs a fuzzy logic", n.Text)
+	case *ir.Unary:
+		return b.unary(n)
+	case *ir.Binary:
+		return b.binary(n)
+	default:
+		return "", fmt.Errorf("invalid boolean expression '%v'", node)
+	}
+}
+
+func (b *builder) binary(n *ir.Binary) (string, error) {
+	left, err := b.node(n.X)
+	if err != nil {
+		return "", err
+	}
+	right, err := b.node(n.Y)
+	if err != nil {
+		return "", err
+	}
+	switch n.Op {
+	case ir.AND:
+		return b.gate(And, left, right), nil
+	case ir.NAND:
+		return b.gate(Nand, left, right), nil
+	case ir.OR:
+		return b.gate(Or, left, right), nil
+	case ir.NOR:
+		return b.gate(Nor, left, right), nil
+	case ir.IMPLIES:
+		return b.gate(Or, b.gate(Not, left), right), nil
+	case ir.IMPLIED_BY:
+		return b.gate(Or, left, b.gate(Not, right)), nil
+	case ir.INHIBITS:
+		return b.gate(And, left, b.gate(Not, right)), nil
+	case ir.INHIBITED_BY:
+		return b.gate(And, b.gate(Not, left), right), nil
+	case ir.LEFT:
+		return left, nil
+	case ir.RIGHT:
+		return right, nil
+	case ir.NOT_LEFT:
+		return b.gate(Not, left), nil
+	case ir.NOT_RIGHT:
+		return b.gate(Not, right), nil
+	case ir.IFF:
+		return b.gate(Xnor, left, right), nil
+	case ir.XOR:
+		return b.gate(Xor, left, right), nil
+	default:
+		return "", errorAt(n.OpPos, "invalid binary operation '%s'", n.Text)
+	}
+}
+
+func (b *builder) unary(n *ir.Unary) (string, error) {
+	acc, err := b.node(n.X)
+	if err != nil {
+		return "", err
+	}
+	switch n.Op {
+	case ir.NOT:
+		return b.gate(Not, acc), nil
+	case ir.NULLIFY:
+		return b.gate(ConstFalse), nil
+	case ir.TRUIFY:
+		return b.gate(ConstTrue), nil
+	case ir.ID:
+		return acc, nil
+	default:
+		return "", errorAt(n.Pos, "invalid unary operator '%s'", n.Text)
+	}
+}
+
+// Regd. Simulation
+
+// Eval drives the circuit with inputs and returns the value of every output.
+func (c *Circuit) Eval(inputs map[string]bool) (map[string]bool, error) {
+	signals := map[string]bool{}
+	for _, name := range c.Inputs {
+		val, ok := inputs[name]
+		if !ok {
+			return nil, fmt.Errorf("missing value for input '%s'", name)
+		}
+		signals[name] = val
+	}
+	for _, gate := range c.Gates {
+		in := make([]bool, len(gate.In))
+		for idx, name := range gate.In {
+			in[idx] = signals[name]
+		}
+		signals[gate.Out] = gate.Kind.Apply(in...)
+	}
+	outputs := map[string]bool{}
+	for _, out := range c.Outputs {
+		outputs[out.Name] = signals[out.Signal]
+	}
+	return outputs, nil
+}
+
+func (kind GateKind) Apply(in ...bool) bool {
+	switch kind {
+	case ConstFalse:
+		return false
+	case ConstTrue:
+		return true
+	case Not:
+		return !in[0]
+	case And:
+		return in[0] && in[1]
+	case Nand:
+		return !(in[0] && in[1])
+	case Or:
+		return in[0] || in[1]
+	case Nor:
+		return !(in[0] || in[1])
+	case Xor:
+		return in[0] != in[1]
+	case Xnor:
+		return in[0] == in[1]
+	default:
+		panic("unknown gate kind " + strconv.Itoa(int(kind)))
+	}
+}
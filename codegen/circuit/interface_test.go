@@ -0,0 +1,105 @@
+package circuit
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/parser"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHalfAdder(t *testing.T) {
+	input := "let sum := a xor b\nlet carry := a and b"
+	file, err := parser.FileParser.ParseString("", input)
+	assert.NoError(t, err)
+	c, err := FromFile("half_adder", file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, c.Inputs)
+	assert.Equal(t, []Gate{
+		{Out: "w0", Kind: Xor, In: []string{"a", "b"}},
+		{Out: "w1", Kind: And, In: []string{"a", "b"}},
+	}, c.Gates)
+	assert.Equal(t, []Output{{"sum", "w0"}, {"carry", "w1"}}, c.Outputs)
+}
+
+func TestDefinitionsFeedLaterDefinitions(t *testing.T) {
+	input := "let p := a and b\nlet q := p or c\na or b"
+	file, err := parser.FileParser.ParseString("", input)
+	assert.NoError(t, err)
+	c, err := FromFile("m", file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, c.Inputs)
+	assert.Equal(t, []Output{{"p", "w0"}, {"q", "w1"}}, c.Outputs)
+}
+
+func TestFreshNamesAvoidUserNames(t *testing.T) {
+	input := "let w1 := w0 and not w2"
+	file, err := parser.FileParser.ParseString("", input)
+	assert.NoError(t, err)
+	c, err := FromFile("m", file)
+	assert.NoError(t, err)
+	assert.Equal(t, []Gate{
+		{Out: "w3", Kind: Not, In: []string{"w2"}},
+		{Out: "w4", Kind: And, In: []string{"w0", "w3"}},
+	}, c.Gates)
+}
+
+func TestFromFileFail(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a and b", "m: no definitions to synthesize"},
+		{"let p := a\nlet p := b", "2:1: 'p' is already defined"},
+		{"let p := q\nlet q := a", "2:1: 'q' is used as an input above its definition"},
+	}
+	for _, test := range tests {
+		file, err := parser.FileParser.ParseString("", test.input)
+		assert.NoError(t, err)
+		_, err = FromFile("m", file)
+		assert.EqualError(t, err, test.expected)
+	}
+}
+
+func TestEvalAgreesWithInterpreter(t *testing.T) {
+	tests := []string{
+		"a and b",
+		"a nand b",
+		"a or not b",
+		"a nor b",
+		"a xor b",
+		"a iff b",
+		"a implies b",
+		"a is implied by b",
+		"a inhibits b",
+		"a is inhibited by b",
+		"a left b",
+		"a right b",
+		"a not left b",
+		"a not right b",
+		"nullify a or truify b and id c",
+		"(a => b) /\\ (b <=/ c) <~> ~(c </ a)",
+		"True and a s> False",
+	}
+	for _, test := range tests {
+		expr, err := boolean.ExprParser.ParseString("", test)
+		assert.NoError(t, err)
+		file, err := parser.FileParser.ParseString("", "let out := "+test)
+		assert.NoError(t, err)
+		c, err := FromFile("m", file)
+		assert.NoError(t, err)
+		vars := []string{"a", "b", "c"}
+		for bits := 0; bits < 1<<len(vars); bits++ {
+			env := boolean.Env{}
+			for idx, name := range vars {
+				env[name] = bits&(1<<idx) != 0
+			}
+			expected := boolean.EvalExpr(expr, env)
+			assert.NoError(t, expected.Err)
+			actual, err := c.Eval(env)
+			assert.NoError(t, err)
+			assert.Equal(t, expected.Payload, types.Bool(actual["out"]), test)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package elixir
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"acornlang.dev/lang/codegen/program"
+	"acornlang.dev/lang/codegen/syntax"
+)
+
+// ELIXIR uses the strict boolean operators.
+var ELIXIR = syntax.Language{
+	Ops:    map[syntax.Op]string{syntax.Not: "not ", syntax.And: "and", syntax.Or: "or", syntax.Eq: "==", syntax.Ne: "!="},
+	Levels: map[syntax.Op]int{syntax.Not: 4, syntax.Eq: 3, syntax.Ne: 3, syntax.And: 2, syntax.Or: 1},
+	True:   "true",
+	False:  "false",
+	Reserved: map[string]bool{
+		"after": true, "and": true, "catch": true, "do": true, "else": true,
+		"end": true, "false": true, "fn": true, "in": true, "nil": true,
+		"not": true, "or": true, "rescue": true, "true": true, "when": true,
+	},
+	// A leading underscore marks a variable as unused.
+	Ident:  regexp.MustCompile(`^[a-z][a-zA-Z0-9_]*$`),
+	Prefix: "v_",
+}
+
+// Module is the name of the Elixir module for p.
+func Module(p *program.Program) string {
+	return syntax.Camel(p.Name)
+}
+
+// Generate emits an Elixir module with one specced function per
+// definition. Parameters a function does not read are underscored.
+func Generate(p *program.Program) string {
+	pr := syntax.NewPrinter(&ELIXIR, p)
+	var sb strings.Builder
+	sb.WriteString("# Generated by ac gen. Do not edit.\n")
+	fmt.Fprintf(&sb, "defmodule %s do", Module(p))
+	for _, fn := range p.Functions {
+		body, used := pr.Body(fn)
+		types, params := []string{}, []string{}
+		for _, param := range fn.Params {
+			types = append(types, "boolean()")
+			if used[param] {
+				params = append(params, pr.Var(param))
+			} else {
+				params = append(params, "_"+pr.Var(param))
+			}
+		}
+		name := pr.Names.Name(fn.Name)
+		fmt.Fprintf(&sb, "\n  @spec %s(%s) :: boolean()\n", name, strings.Join(types, ", "))
+		if len(params) == 0 {
+			fmt.Fprintf(&sb, "  def %s do\n    %s\n  end\n", name, body)
+		} else {
+			fmt.Fprintf(&sb, "  def %s(%s) do\n    %s\n  end\n", name, strings.Join(params, ", "), body)
+		}
+	}
+	sb.WriteString("end\n")
+	return sb.String()
+}
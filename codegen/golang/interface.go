@@ -0,0 +1,108 @@
+package golang
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"acornlang.dev/lang/codegen/program"
+	"acornlang.dev/lang/codegen/syntax"
+)
+
+// MAX_TEST_PARAMS bounds the truth tables the tests spell out.
+const MAX_TEST_PARAMS = 16
+
+// GO exports every definition under its name in MixedCaps.
+var GO = syntax.Language{
+	Ops:    map[syntax.Op]string{syntax.Not: "!", syntax.And: "&&", syntax.Or: "||", syntax.Eq: "==", syntax.Ne: "!="},
+	Levels: map[syntax.Op]int{syntax.Not: 4, syntax.Eq: 3, syntax.Ne: 3, syntax.And: 2, syntax.Or: 1},
+	True:   "true",
+	False:  "false",
+	Reserved: map[string]bool{
+		"break": true, "case": true, "chan": true, "const": true,
+		"continue": true, "default": true, "defer": true, "else": true,
+		"fallthrough": true, "for": true, "func": true, "go": true,
+		"goto": true, "if": true, "import": true, "interface": true,
+		"map": true, "package": true, "range": true, "return": true,
+		"select": true, "struct": true, "switch": true, "type": true,
+		"var": true,
+		// Predeclared names would be shadowed, and `_` cannot be read.
+		"_": true, "any": true, "bool": true, "error": true, "false": true,
+		"int": true, "len": true, "nil": true, "string": true, "true": true,
+	},
+	Ident:  regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`),
+	Prefix: "v_",
+	Export: syntax.Camel,
+}
+
+var invalidChars = regexp.MustCompile(`[^a-z0-9]`)
+
+// Package is a package name made of name: lower case letters and digits,
+// as `go vet` likes them.
+func Package(name string) string {
+	name = invalidChars.ReplaceAllString(strings.ToLower(name), "")
+	if name == "" || name[0] >= '0' && name[0] <= '9' || GO.Reserved[name] {
+		return "m" + name
+	}
+	return name
+}
+
+func header(sb *strings.Builder, pkg string) {
+	fmt.Fprintf(sb, "// Code generated by ac gen. DO NOT EDIT.\n\npackage %s\n", pkg)
+}
+
+// Generate emits a Go file of package pkg with one exported function per
+// definition, documented by the comment above the definition if any.
+func Generate(p *program.Program, pkg string) string {
+	pr := syntax.NewPrinter(&GO, p)
+	var sb strings.Builder
+	header(&sb, pkg)
+	for _, fn := range p.Functions {
+		body, _ := pr.Body(fn)
+		params := []string{}
+		for _, param := range fn.Params {
+			params = append(params, pr.Var(param))
+		}
+		signature := strings.Join(params, ", ")
+		if len(params) > 0 {
+			signature += " bool"
+		}
+		sb.WriteString("\n")
+		if fn.Doc != "" {
+			for _, line := range strings.Split(fn.Doc, "\n") {
+				sb.WriteString(strings.TrimRight("// "+line, " ") + "\n")
+			}
+		}
+		fmt.Fprintf(&sb, "func %s(%s) bool {\n\treturn %s\n}\n", pr.Names.Name(fn.Name), signature, body)
+	}
+	return sb.String()
+}
+
+// Tests emits a Go test file of package pkg that calls every function of
+// p on every assignment of its parameters and compares the result with
+// the truth table boolean.EvalExpr gives.
+func Tests(p *program.Program, pkg string) (string, error) {
+	pr := syntax.NewPrinter(&GO, p)
+	var sb strings.Builder
+	header(&sb, pkg)
+	sb.WriteString("\nimport \"testing\"\n")
+	for idx, fn := range p.Functions {
+		if len(fn.Params) > MAX_TEST_PARAMS {
+			return "", fmt.Errorf("'%s' has %d inputs, the tests take at most %d", fn.Name, len(fn.Params), MAX_TEST_PARAMS)
+		}
+		table, err := p.TruthTable(idx)
+		if err != nil {
+			return "", err
+		}
+		args := []string{}
+		for bit := range fn.Params {
+			args = append(args, fmt.Sprintf("row&%d != 0", 1<<(len(fn.Params)-1-bit)))
+		}
+		name := pr.Names.Name(fn.Name)
+		fmt.Fprintf(&sb, "\nfunc Test%s(t *testing.T) {\n\tconst expected = \"%s\"\n", name, table)
+		sb.WriteString("\tfor row := 0; row < len(expected); row++ {\n")
+		fmt.Fprintf(&sb, "\t\tif got := %s(%s); got != (expected[row] == '1') {\n", name, strings.Join(args, ", "))
+		fmt.Fprintf(&sb, "\t\t\tt.Errorf(\"%s is %%v at assignment %%d\", got, row)\n\t\t}\n\t}\n}\n", name)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,108 @@
+package golang
+
+import (
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"acornlang.dev/lang/codegen/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHalfAdder(t *testing.T) {
+	p := testutil.Program(t, "Go", "half_adder", "# Sum is the low bit.\nlet sum := a xor b\n# Carry is the high bit,\n#\n# and the next carry in.\nlet carry := a and b\nlet both := sum or carry")
+	source := Generate(p, "adder")
+	assert.Equal(t, `// Code generated by ac gen. DO NOT EDIT.
+
+package adder
+
+// Sum is the low bit.
+func Sum(a, b bool) bool {
+	return a != b
+}
+
+// Carry is the high bit,
+//
+// and the next carry in.
+func Carry(a, b bool) bool {
+	return a && b
+}
+
+func Both(a, b bool) bool {
+	return Sum(a, b) || Carry(a, b)
+}
+`, source)
+	tests, err := Tests(p, "adder")
+	assert.NoError(t, err)
+	assert.Contains(t, tests, `func TestSum(t *testing.T) {
+	const expected = "0110"
+	for row := 0; row < len(expected); row++ {
+		if got := Sum(row&2 != 0, row&1 != 0); got != (expected[row] == '1') {
+			t.Errorf("Sum is %v at assignment %d", got, row)
+		}
+	}
+}`)
+	for _, text := range []string{source, tests} {
+		formatted, err := format.Source([]byte(text))
+		assert.NoError(t, err)
+		assert.Equal(t, string(formatted), text)
+	}
+}
+
+func TestNames(t *testing.T) {
+	p := testutil.Program(t, "Go", "m", "let carry_in := _ and type\nlet carryIn := len\nlet k := False")
+	source := Generate(p, "m")
+	assert.Contains(t, source, "func CarryIn(__, type_ bool) bool {\n\treturn __ && type_\n}")
+	assert.Contains(t, source, "func CarryIn_1(len_ bool) bool {")
+	assert.Contains(t, source, "func K() bool {\n\treturn false\n}")
+	assert.Equal(t, "halfadder", Package("Half-Adder"))
+	assert.Equal(t, "m2bit", Package("2-bit"))
+	assert.Equal(t, "mtype", Package("type"))
+}
+
+// The generated tests compare every operator against the evaluator, built
+// as a module of their own with the local go tool.
+func TestGeneratedTestsPass(t *testing.T) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("no go tool")
+	}
+	input, err := os.ReadFile("../testdata/operators.lx")
+	assert.NoError(t, err)
+	p := testutil.Program(t, "Go", "ops", string(input))
+	tests, err := Tests(p, "ops")
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	files := map[string]string{"go.mod": "module ops\n\ngo 1.21\n", "ops.go": Generate(p, "ops"), "ops_test.go": tests}
+	for name, text := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644))
+	}
+	cmd := exec.Command(goTool, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(out))
+}
+
+func TestGeneratedTestsCatchWrongOutput(t *testing.T) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("no go tool")
+	}
+	tests, err := Tests(testutil.Program(t, "Go", "m", "let p := a or b"), "m")
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	files := map[string]string{"go.mod": "module m\n\ngo 1.21\n", "m.go": Generate(testutil.Program(t, "Go", "m", "let p := a and b"), "m"), "m_test.go": tests}
+	for name, text := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644))
+	}
+	cmd := exec.Command(goTool, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	assert.Error(t, err)
+	assert.Contains(t, string(out), "P is false at assignment 1")
+	assert.Contains(t, string(out), "P is false at assignment 2")
+}
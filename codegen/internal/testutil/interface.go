@@ -0,0 +1,23 @@
+package testutil
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/codegen/program"
+	"acornlang.dev/lang/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+// Program reads input as the file name and compiles it for language, with
+// the comment above each definition as its doc.
+func Program(t *testing.T, language string, name string, input string) *program.Program {
+	t.Helper()
+	file, err := parser.FileParser.ParseString("", input)
+	assert.NoError(t, err, input)
+	p, err := program.FromFile(name, file, language)
+	assert.NoError(t, err, input)
+	if p != nil {
+		p.ReadDocs([]byte(input))
+	}
+	return p
+}
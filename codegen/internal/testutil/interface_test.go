@@ -0,0 +1,74 @@
+package testutil
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"acornlang.dev/lang/codegen/c"
+	"acornlang.dev/lang/codegen/elixir"
+	"acornlang.dev/lang/codegen/golang"
+	"acornlang.dev/lang/codegen/program"
+	"acornlang.dev/lang/codegen/python"
+	"acornlang.dev/lang/codegen/raku"
+	"acornlang.dev/lang/codegen/ruby"
+	"acornlang.dev/lang/codegen/typescript"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in codegen/testdata")
+
+func header(p *program.Program) string {
+	header, _ := c.Generate(p)
+	return header
+}
+
+func source(p *program.Program) string {
+	_, source := c.Generate(p)
+	return source
+}
+
+func goFile(p *program.Program) string {
+	return golang.Generate(p, golang.Package(p.Name))
+}
+
+// Every codegen/testdata/name.lx is generated by each target into
+// codegen/testdata/target/name.ext.
+func TestGolden(t *testing.T) {
+	targets := []struct {
+		dir      string
+		ext      string
+		language string
+		generate func(*program.Program) string
+	}{
+		{"c", ".h", "C", header},
+		{"c", ".c", "C", source},
+		{"python", ".py", "Python", python.Generate},
+		{"golang", ".go", "Go", goFile},
+		{"ruby", ".rb", "Ruby", ruby.Generate},
+		{"raku", ".rakumod", "Raku", raku.Generate},
+		{"elixir", ".ex", "Elixir", elixir.Generate},
+		{"typescript", ".ts", "TypeScript", typescript.Generate},
+	}
+	inputs, err := filepath.Glob("../../testdata/*.lx")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, inputs)
+	for _, target := range targets {
+		for _, input := range inputs {
+			text, err := os.ReadFile(input)
+			assert.NoError(t, err, input)
+			name := strings.TrimSuffix(filepath.Base(input), ".lx")
+			generated := target.generate(Program(t, target.language, name, string(text)))
+			golden := filepath.Join(filepath.Dir(input), target.dir, name+target.ext)
+			if *update {
+				assert.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755))
+				assert.NoError(t, os.WriteFile(golden, []byte(generated), 0o644))
+			}
+			expected, err := os.ReadFile(golden)
+			assert.NoError(t, err, golden)
+			assert.Equal(t, string(expected), generated, golden)
+		}
+	}
+}
@@ -0,0 +1,628 @@
+package lisp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/types"
+	arithAst "acornlang.dev/lang/types/ast/arith"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ir"
+)
+
+// Flavour is how one Lisp reads the forms: the forms are data, not code,
+// as most connectives are not Lisp functions, so the flavours differ only
+// in their booleans and the symbols their readers reject.
+type Flavour struct {
+	Name  string
+	True  string
+	False string
+	// NotEquals is the head of `!=`, or empty where it is written as the
+	// negation of `=`.
+	NotEquals string
+	// Taken are the symbols that read as something else, and are written
+	// between bars.
+	Taken map[string]bool
+}
+
+var COMMON_LISP = Flavour{
+	Name:      "common-lisp",
+	True:      "t",
+	False:     "nil",
+	NotEquals: "/=",
+	Taken:     map[string]bool{"t": true, "nil": true},
+}
+
+var SCHEME = Flavour{
+	Name:  "scheme",
+	True:  "#t",
+	False: "#f",
+	Taken: map[string]bool{},
+}
+
+var CLOJURE = Flavour{
+	Name:      "clojure",
+	True:      "true",
+	False:     "false",
+	NotEquals: "not=",
+	Taken:     map[string]bool{"true": true, "false": true, "nil": true},
+}
+
+var FLAVOURS = []Flavour{COMMON_LISP, SCHEME, CLOJURE}
+
+func ParseFlavour(name string) (Flavour, error) {
+	names := []string{}
+	for _, flavour := range FLAVOURS {
+		if flavour.Name == name {
+			return flavour, nil
+		}
+		names = append(names, flavour.Name)
+	}
+	return Flavour{}, fmt.Errorf("unknown flavour '%s', expected %s or %s", name, strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
+
+// Regd. Operators
+
+// heads names every operator by one symbol; `xnor` and `<=>` lower to
+// IFF, and the spaced operators are hyphenated.
+var heads = map[ir.Op]string{
+	ir.NOT:          "not",
+	ir.NULLIFY:      "nullify",
+	ir.TRUIFY:       "truify",
+	ir.ID:           "id",
+	ir.NEXT:         "next",
+	ir.GLOBALLY:     "globally",
+	ir.FINALLY:      "finally",
+	ir.AND:          "and",
+	ir.NAND:         "nand",
+	ir.OR:           "or",
+	ir.NOR:          "nor",
+	ir.XOR:          "xor",
+	ir.IFF:          "iff",
+	ir.IMPLIES:      "implies",
+	ir.IMPLIED_BY:   "implied-by",
+	ir.INHIBITS:     "inhibits",
+	ir.INHIBITED_BY: "inhibited-by",
+	ir.LEFT:         "left",
+	ir.RIGHT:        "right",
+	ir.NOT_LEFT:     "not-left",
+	ir.NOT_RIGHT:    "not-right",
+	ir.UNTIL:        "until",
+	ir.RELEASE:      "release",
+}
+
+// ops reads the heads back.
+var ops = opsByHead()
+
+func opsByHead() map[string]ir.Op {
+	acc := map[string]ir.Op{}
+	for op, head := range heads {
+		acc[head] = op
+	}
+	return acc
+}
+
+// associative operators take any number of arguments.
+var associative = map[ir.Op]bool{ir.AND: true, ir.OR: true, ir.XOR: true, ir.IFF: true}
+
+var relations = map[string]bool{
+	lexer.LESS_SYMB: true, lexer.LESS_EQUAL_SYMB: true, lexer.GREATER_SYMB: true,
+	lexer.GREATER_EQUAL_SYMB: true, lexer.EQUALS_SYMB: true, lexer.NOT_EQUALS_SYMB: true,
+}
+
+// Regd. Printing
+
+// Format writes expr as one form. Brackets in the source leave no trace,
+// and chains of an associative operator become one form. A tree the
+// parser did not build may not lower, and prints as nothing.
+func (f Flavour) Format(expr *booleanAst.Expr) string {
+	lowered, err := ir.Lower(expr)
+	if err != nil {
+		return ""
+	}
+	return f.FormatNode(lowered)
+}
+
+func (f Flavour) FormatNode(expr ir.Node) string {
+	switch e := expr.(type) {
+	case *ir.Binary:
+		args := []string{}
+		if associative[e.Op] {
+			args = append(f.chain(e.Op, e.X), f.chain(e.Op, e.Y)...)
+		} else {
+			args = append(args, f.FormatNode(e.X), f.FormatNode(e.Y))
+		}
+		return "(" + heads[e.Op] + " " + strings.Join(args, " ") + ")"
+	case *ir.Unary:
+		return "(" + heads[e.Op] + " " + f.FormatNode(e.X) + ")"
+	case *ir.Cmp:
+		op := e.Cmp.Op
+		switch op {
+		case lexer.LESS_EQUAL_SYMB:
+			op = "<="
+		case lexer.EQUALS_SYMB:
+			op = "="
+		case lexer.NOT_EQUALS_SYMB:
+			op = f.NotEquals
+			if op == "" {
+				return "(" + heads[ir.NOT] + " (= " + f.sum(e.Cmp.Left) + " " + f.sum(e.Cmp.Right) + "))"
+			}
+		}
+		return "(" + op + " " + f.sum(e.Cmp.Left) + " " + f.sum(e.Cmp.Right) + ")"
+	case *ir.Var:
+		return f.symbol(e.Name)
+	case *ir.Const:
+		if e.Value {
+			return f.True
+		}
+		return f.False
+	case *ir.Degree:
+		return e.Text
+	default:
+		return ""
+	}
+}
+
+// chain spells the arguments of op found in expr: expr itself, or its
+// operands if it applies op too.
+func (f Flavour) chain(op ir.Op, expr ir.Node) []string {
+	binary, ok := expr.(*ir.Binary)
+	if !ok || binary.Op != op {
+		return []string{f.FormatNode(expr)}
+	}
+	return append(f.chain(op, binary.X), f.chain(op, binary.Y)...)
+}
+
+func (f Flavour) symbol(name string) string {
+	if f.Taken[name] {
+		return "|" + name + "|"
+	}
+	return name
+}
+
+// sum groups to the left, as the parser does: `a - b + c` is
+// `(+ (- a b) c)`, and runs of + or * become one form.
+func (f Flavour) sum(expr *arithAst.Sum) string {
+	acc, op, args := f.term(expr.Head), "", []string{}
+	for _, rest := range expr.Rest {
+		acc, op, args = f.extend(acc, op, args, rest.Op, f.term(rest.Term))
+	}
+	return f.close(acc, op, args)
+}
+
+func (f Flavour) term(expr *arithAst.Term) string {
+	acc, op, args := f.factor(expr.Head), "", []string{}
+	for _, rest := range expr.Rest {
+		acc, op, args = f.extend(acc, op, args, rest.Op, f.factor(rest.Factor))
+	}
+	return f.close(acc, op, args)
+}
+
+// extend adds arg to the open form (op args...), or closes it and opens a
+// new one around it.
+func (f Flavour) extend(acc string, op string, args []string, next string, arg string) (string, string, []string) {
+	if op == next && (op == lexer.PLUS_SYMB || op == lexer.TIMES_SYMB) {
+		return acc, op, append(args, arg)
+	}
+	return f.close(acc, op, args), next, []string{arg}
+}
+
+func (f Flavour) close(acc string, op string, args []string) string {
+	if op == "" {
+		return acc
+	}
+	return "(" + op + " " + acc + " " + strings.Join(args, " ") + ")"
+}
+
+func (f Flavour) factor(expr *arithAst.Factor) string {
+	var acc string
+	switch {
+	case expr.Paren != nil:
+		acc = f.sum(expr.Paren.Expr)
+	case expr.Var != "":
+		acc = f.symbol(expr.Var)
+	default:
+		acc = expr.Int
+	}
+	if expr.Neg {
+		return "(- " + acc + ")"
+	}
+	return acc
+}
+
+// Regd. Reading
+
+// node is a read form: an atom, or a list of forms.
+type node struct {
+	pos    types.Position
+	atom   string
+	bared  bool
+	list   []*node
+	isList bool
+}
+
+var (
+	intAtom    = regexp.MustCompile(`^[0-9]+$`)
+	degreeAtom = regexp.MustCompile(`^[0-9]*\.[0-9]+$`)
+	symbolAtom = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	delimiters = "()| \t\r\n"
+	ident      = lexer.BooleanLexer.Symbols()["Ident"]
+)
+
+// isName reports whether acorn reads atom back as a name, and not as a
+// keyword such as `next` or `in`.
+func isName(atom string) bool {
+	lex, err := lexer.BooleanLexer.LexString("", atom)
+	if err != nil {
+		return false
+	}
+	tok, err := lex.Next()
+	if err != nil || tok.Type != ident || tok.Value != atom {
+		return false
+	}
+	end, err := lex.Next()
+	return err == nil && end.EOF()
+}
+
+func errNotName(n *node) error {
+	return errorAt(n.pos, "'%s' cannot name a variable, acorn reads it as a keyword", n.atom)
+}
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+type reader struct {
+	input  string
+	offset int
+	line   int
+	column int
+}
+
+func (r *reader) pos() types.Position {
+	return types.Position{Offset: r.offset, Line: r.line, Column: r.column}
+}
+
+func (r *reader) advance() {
+	if r.input[r.offset] == '\n' {
+		r.line++
+		r.column = 1
+	} else {
+		r.column++
+	}
+	r.offset++
+}
+
+// skip passes over whitespace and `;` comments.
+func (r *reader) skip() {
+	for r.offset < len(r.input) {
+		switch c := r.input[r.offset]; {
+		case c == ';':
+			for r.offset < len(r.input) && r.input[r.offset] != '\n' {
+				r.advance()
+			}
+		case strings.IndexByte(" \t\r\n", c) >= 0:
+			r.advance()
+		default:
+			return
+		}
+	}
+}
+
+func (r *reader) read() (*node, error) {
+	r.skip()
+	pos := r.pos()
+	if r.offset == len(r.input) {
+		return nil, errorAt(pos, "unexpected end of input")
+	}
+	switch r.input[r.offset] {
+	case '(':
+		r.advance()
+		n := &node{pos: pos, isList: true}
+		for {
+			r.skip()
+			if r.offset == len(r.input) {
+				return nil, errorAt(pos, "unclosed '('")
+			}
+			if r.input[r.offset] == ')' {
+				r.advance()
+				return n, nil
+			}
+			kid, err := r.read()
+			if err != nil {
+				return nil, err
+			}
+			n.list = append(n.list, kid)
+		}
+	case ')':
+		return nil, errorAt(pos, "unexpected ')'")
+	case '|':
+		r.advance()
+		start := r.offset
+		for r.offset < len(r.input) && r.input[r.offset] != '|' {
+			r.advance()
+		}
+		if r.offset == len(r.input) {
+			return nil, errorAt(pos, "unclosed '|'")
+		}
+		atom := r.input[start:r.offset]
+		r.advance()
+		return &node{pos: pos, atom: atom, bared: true}, nil
+	default:
+		start := r.offset
+		for r.offset < len(r.input) && strings.IndexByte(delimiters, r.input[r.offset]) < 0 {
+			r.advance()
+		}
+		return &node{pos: pos, atom: r.input[start:r.offset]}, nil
+	}
+}
+
+// Parse reads one form written in the flavour back into an expression,
+// with the positions of the forms. Associative forms group to the right,
+// as the parser does.
+func (f Flavour) Parse(input string) (*booleanAst.Expr, error) {
+	r := &reader{input: input, line: 1, column: 1}
+	n, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	r.skip()
+	if r.offset != len(input) {
+		return nil, errorAt(r.pos(), "unexpected text after the form")
+	}
+	return f.expr(n)
+}
+
+func (f Flavour) expr(n *node) (*booleanAst.Expr, error) {
+	if !n.isList {
+		primary, err := f.primaryOf(n)
+		if err != nil {
+			return nil, err
+		}
+		return &booleanAst.Expr{Pos: n.pos, Unary: &booleanAst.UnaryExpr{Pos: n.pos, Expr: primary}}, nil
+	}
+	if len(n.list) == 0 {
+		return nil, errorAt(n.pos, "empty form")
+	}
+	head := n.list[0]
+	args := n.list[1:]
+	if head.isList || head.bared {
+		return nil, errorAt(head.pos, "expected an operator")
+	}
+	op, ok := ops[head.atom]
+	if ok && op.IsUnary() {
+		unary, err := f.unaryOf(n)
+		if err != nil {
+			return nil, err
+		}
+		return &booleanAst.Expr{Pos: n.pos, Unary: unary}, nil
+	}
+	if f.relation(head.atom) != "" {
+		primary, err := f.primaryOf(n)
+		if err != nil {
+			return nil, err
+		}
+		return &booleanAst.Expr{Pos: n.pos, Unary: &booleanAst.UnaryExpr{Pos: n.pos, Expr: primary}}, nil
+	}
+	if !ok {
+		return nil, errorAt(head.pos, "unknown operator '%s'", head.atom)
+	}
+	if len(args) < 2 || len(args) > 2 && !associative[op] {
+		if associative[op] {
+			return nil, errorAt(n.pos, "'%s' takes at least 2 arguments, got %d", head.atom, len(args))
+		}
+		return nil, errorAt(n.pos, "'%s' takes 2 arguments, got %d", head.atom, len(args))
+	}
+	return f.fold(n.pos, head.pos, op.String(), args)
+}
+
+// fold makes `a op (b op c ...)` of args.
+func (f Flavour) fold(pos types.Position, opPos types.Position, op string, args []*node) (*booleanAst.Expr, error) {
+	left, err := f.operand(args[0])
+	if err != nil {
+		return nil, err
+	}
+	var right *booleanAst.Expr
+	if len(args) == 2 {
+		right, err = f.expr(args[1])
+	} else {
+		right, err = f.fold(args[1].pos, opPos, op, args[1:])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &booleanAst.Expr{Pos: pos, Unary: left, Rest: &booleanAst.ExprRest{Pos: opPos, Op: op, Expr: right}}, nil
+}
+
+// operand is the left side of a binary operator, which is bracketed
+// unless unary.
+func (f Flavour) operand(n *node) (*booleanAst.UnaryExpr, error) {
+	expr, err := f.expr(n)
+	if err != nil {
+		return nil, err
+	}
+	if expr.Rest == nil {
+		return expr.Unary, nil
+	}
+	return parenthesize(expr), nil
+}
+
+func parenthesize(expr *booleanAst.Expr) *booleanAst.UnaryExpr {
+	return &booleanAst.UnaryExpr{Pos: expr.Pos, Expr: &booleanAst.PrimaryExpr{Pos: expr.Pos, Paren: &booleanAst.ParenExpr{Pos: expr.Pos, Expr: expr}}}
+}
+
+// unaryOf collects the unary operators n applies in a row.
+func (f Flavour) unaryOf(n *node) (*booleanAst.UnaryExpr, error) {
+	head := n.list[0]
+	if len(n.list) != 2 {
+		return nil, errorAt(n.pos, "'%s' takes 1 argument, got %d", head.atom, len(n.list)-1)
+	}
+	kid, err := f.operand(n.list[1])
+	if err != nil {
+		return nil, err
+	}
+	ops := append([]booleanAst.UnaryOp{{Pos: head.pos, Op: ops[head.atom].String()}}, kid.Ops...)
+	return &booleanAst.UnaryExpr{Pos: n.pos, Ops: ops, Expr: kid.Expr}, nil
+}
+
+// relation is the acorn relation the head spells, if any.
+func (f Flavour) relation(head string) string {
+	switch {
+	case head == "<=":
+		return lexer.LESS_EQUAL_SYMB
+	case head == "=":
+		return lexer.EQUALS_SYMB
+	case f.NotEquals != "" && head == f.NotEquals:
+		return lexer.NOT_EQUALS_SYMB
+	case head != lexer.LESS_EQUAL_SYMB && head != lexer.EQUALS_SYMB && head != lexer.NOT_EQUALS_SYMB && relations[head]:
+		return head
+	}
+	return ""
+}
+
+func (f Flavour) primaryOf(n *node) (*booleanAst.PrimaryExpr, error) {
+	if n.isList {
+		head := n.list[0]
+		if len(n.list) != 3 {
+			return nil, errorAt(n.pos, "'%s' takes 2 arguments, got %d", head.atom, len(n.list)-1)
+		}
+		left, err := f.sumOf(n.list[1])
+		if err != nil {
+			return nil, err
+		}
+		right, err := f.sumOf(n.list[2])
+		if err != nil {
+			return nil, err
+		}
+		return &booleanAst.PrimaryExpr{Pos: n.pos, Cmp: &arithAst.Comparison{Pos: n.pos, Left: left, Op: f.relation(head.atom), Right: right}}, nil
+	}
+	switch {
+	case n.bared && !isName(n.atom):
+		return nil, errNotName(n)
+	case n.bared:
+		return &booleanAst.PrimaryExpr{Pos: n.pos, Var: n.atom}, nil
+	case n.atom == f.True:
+		return &booleanAst.PrimaryExpr{Pos: n.pos, Lit: lexer.TRUE}, nil
+	case n.atom == f.False:
+		return &booleanAst.PrimaryExpr{Pos: n.pos, Lit: lexer.FALSE}, nil
+	case degreeAtom.MatchString(n.atom):
+		return &booleanAst.PrimaryExpr{Pos: n.pos, Degree: n.atom}, nil
+	case symbolAtom.MatchString(n.atom) && !f.Taken[n.atom] && !isName(n.atom):
+		return nil, errNotName(n)
+	case symbolAtom.MatchString(n.atom) && !f.Taken[n.atom]:
+		return &booleanAst.PrimaryExpr{Pos: n.pos, Var: n.atom}, nil
+	}
+	return nil, errorAt(n.pos, "unexpected '%s'", n.atom)
+}
+
+// Regd. Reading sums
+
+var arithOps = map[string]bool{
+	lexer.PLUS_SYMB: true, lexer.MINUS_SYMB: true, lexer.TIMES_SYMB: true,
+	lexer.DIV_SYMB: true, lexer.MOD_TEXT: true,
+}
+
+func (f Flavour) sumOf(n *node) (*arithAst.Sum, error) {
+	if n.isList && len(n.list) > 2 && (n.list[0].atom == lexer.PLUS_SYMB || n.list[0].atom == lexer.MINUS_SYMB) {
+		if err := f.arity(n); err != nil {
+			return nil, err
+		}
+		sum, err := f.sumOf(n.list[1])
+		if err != nil {
+			return nil, err
+		}
+		sum = &arithAst.Sum{Pos: n.pos, Head: sum.Head, Rest: append([]arithAst.SumRest{}, sum.Rest...)}
+		for _, arg := range n.list[2:] {
+			term, err := f.termOf(arg)
+			if err != nil {
+				return nil, err
+			}
+			sum.Rest = append(sum.Rest, arithAst.SumRest{Pos: arg.pos, Op: n.list[0].atom, Term: term})
+		}
+		return sum, nil
+	}
+	term, err := f.termOf(n)
+	if err != nil {
+		return nil, err
+	}
+	return &arithAst.Sum{Pos: n.pos, Head: term}, nil
+}
+
+func (f Flavour) termOf(n *node) (*arithAst.Term, error) {
+	if n.isList && len(n.list) > 2 && (n.list[0].atom == lexer.TIMES_SYMB || n.list[0].atom == lexer.DIV_SYMB || n.list[0].atom == lexer.MOD_TEXT) {
+		if err := f.arity(n); err != nil {
+			return nil, err
+		}
+		term, err := f.termOf(n.list[1])
+		if err != nil {
+			return nil, err
+		}
+		term = &arithAst.Term{Pos: n.pos, Head: term.Head, Rest: append([]arithAst.TermRest{}, term.Rest...)}
+		for _, arg := range n.list[2:] {
+			factor, err := f.factorOf(arg)
+			if err != nil {
+				return nil, err
+			}
+			term.Rest = append(term.Rest, arithAst.TermRest{Pos: arg.pos, Op: n.list[0].atom, Factor: factor})
+		}
+		return term, nil
+	}
+	factor, err := f.factorOf(n)
+	if err != nil {
+		return nil, err
+	}
+	return &arithAst.Term{Pos: n.pos, Head: factor}, nil
+}
+
+// arity keeps - / and mod to 2 arguments, so the grouping is never in
+// doubt.
+func (f Flavour) arity(n *node) error {
+	head := n.list[0].atom
+	if len(n.list) > 3 && head != lexer.PLUS_SYMB && head != lexer.TIMES_SYMB {
+		return errorAt(n.pos, "'%s' takes 2 arguments, got %d", head, len(n.list)-1)
+	}
+	return nil
+}
+
+// factorOf brackets any sum or product.
+func (f Flavour) factorOf(n *node) (*arithAst.Factor, error) {
+	if !n.isList {
+		switch {
+		case intAtom.MatchString(n.atom) && !n.bared:
+			return &arithAst.Factor{Pos: n.pos, Int: n.atom}, nil
+		case (n.bared || symbolAtom.MatchString(n.atom) && !f.Taken[n.atom]) && !isName(n.atom):
+			return nil, errNotName(n)
+		case n.bared || symbolAtom.MatchString(n.atom) && !f.Taken[n.atom]:
+			return &arithAst.Factor{Pos: n.pos, Var: n.atom}, nil
+		}
+		return nil, errorAt(n.pos, "unexpected '%s'", n.atom)
+	}
+	if len(n.list) == 0 {
+		return nil, errorAt(n.pos, "empty form")
+	}
+	head := n.list[0]
+	if head.atom == lexer.MINUS_SYMB && len(n.list) == 2 {
+		factor, err := f.factorOf(n.list[1])
+		if err != nil {
+			return nil, err
+		}
+		if factor.Neg {
+			sum := &arithAst.Sum{Pos: factor.Pos, Head: &arithAst.Term{Pos: factor.Pos, Head: factor}}
+			factor = &arithAst.Factor{Pos: factor.Pos, Paren: &arithAst.ParenExpr{Pos: factor.Pos, Expr: sum}}
+		}
+		return &arithAst.Factor{Pos: n.pos, Neg: true, Int: factor.Int, Var: factor.Var, Paren: factor.Paren}, nil
+	}
+	if !arithOps[head.atom] || head.bared {
+		return nil, errorAt(head.pos, "unknown operator '%s'", head.atom)
+	}
+	if len(n.list) < 3 {
+		return nil, errorAt(n.pos, "'%s' takes 2 arguments, got %d", head.atom, len(n.list)-1)
+	}
+	sum, err := f.sumOf(n)
+	if err != nil {
+		return nil, err
+	}
+	return &arithAst.Factor{Pos: n.pos, Paren: &arithAst.ParenExpr{Pos: n.pos, Expr: sum}}, nil
+}
@@ -0,0 +1,143 @@
+package lisp
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"p", "p"},
+		{"p and ~q", "(and p (not q))"},
+		{"(p /\\ q) and (r and p)", "(and p q r p)"},
+		{"p and (q or r)", "(and p (or q r))"},
+		{"p => q => r", "(implies p (implies q r))"},
+		{"p xnor q <=> r iff p", "(iff p q r p)"},
+		{"p is inhibited by q", "(inhibited-by p q)"},
+		{"p </ q", "(not-left p q)"},
+		{"not not ((p))", "(not (not p))"},
+		{"~(p nand q)", "(not (nand p q))"},
+		{"□(p => ◇q)", "(globally (implies p (finally q)))"},
+		{"p until q", "(until p q)"},
+		{"0.7 and nullify p", "(and 0.7 (nullify p))"},
+		{"x + 1 > y", "(> (+ x 1) y)"},
+		{"a - b + c * d * 2 =< e mod 3", "(<= (+ (- a b) (* c d 2)) (mod e 3))"},
+		{"-(x - y) == -3", "(= (- (- x y)) (- 3))"},
+	}
+	for _, test := range tests {
+		expr, err := boolean.ExprParser.ParseString("", test.input)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, SCHEME.Format(expr), test.input)
+	}
+}
+
+func TestFlavours(t *testing.T) {
+	expr, err := boolean.ExprParser.ParseString("", "True or nil and False => x != 2")
+	assert.NoError(t, err)
+	assert.Equal(t, "(or t (and |nil| (implies nil (/= x 2))))", COMMON_LISP.Format(expr))
+	assert.Equal(t, "(or #t (and nil (implies #f (not (= x 2)))))", SCHEME.Format(expr))
+	assert.Equal(t, "(or true (and |nil| (implies false (not= x 2))))", CLOJURE.Format(expr))
+	for _, flavour := range FLAVOURS {
+		parsed, err := flavour.Parse(flavour.Format(expr))
+		assert.NoError(t, err, flavour.Name)
+		assert.Equal(t, flavour.Format(expr), flavour.Format(parsed), flavour.Name)
+	}
+}
+
+// Read back, every form means what it was formatted from.
+func TestRoundTrip(t *testing.T) {
+	inputs := []string{
+		"(p or q) and not (r => p)",
+		"p and (q or r) xor (p nand (q nor r))",
+		"((p <=/ q) /=> r) <s (p s> q)",
+		"not not (p </ q) /> r",
+		"truify p <=> id q xnor nullify r",
+		"(p and q) and (q or (r or p))",
+	}
+	for _, input := range inputs {
+		expr, err := boolean.ExprParser.ParseString("", input)
+		assert.NoError(t, err, input)
+		form := COMMON_LISP.Format(expr)
+		parsed, err := COMMON_LISP.Parse(form)
+		assert.NoError(t, err, form)
+		assert.Equal(t, form, COMMON_LISP.Format(parsed), input)
+		for minterm := 0; minterm < 8; minterm++ {
+			env := boolean.Env{"p": minterm&1 != 0, "q": minterm&2 != 0, "r": minterm&4 != 0}
+			assert.Equal(t, boolean.EvalExpr(expr, env).Payload, boolean.EvalExpr(parsed, env).Payload, input)
+			assert.IsType(t, types.Bool(true), boolean.EvalExpr(parsed, env).Payload, input)
+		}
+	}
+}
+
+// Sums come back with the brackets they need and no others.
+func TestArithRoundTrip(t *testing.T) {
+	inputs := []string{
+		"x * (y + 1) - 2 > y - (x - 1)",
+		"a - (b - c) - d == -(-x) mod (2 * y)",
+		"a / b / c * (d / e) != 0",
+	}
+	for _, input := range inputs {
+		expr, err := boolean.ExprParser.ParseString("", input)
+		assert.NoError(t, err, input)
+		parsed, err := CLOJURE.Parse(CLOJURE.Format(expr))
+		assert.NoError(t, err, input)
+		assert.Equal(t, input, arith.Format(parsed.Unary.Expr.Cmp), input)
+	}
+}
+
+func TestParse(t *testing.T) {
+	expr, err := SCHEME.Parse("; a comment\n(and p\n  (not #t))")
+	assert.NoError(t, err)
+	assert.Equal(t, "p", expr.Unary.Expr.Var)
+	assert.Equal(t, "and", expr.Rest.Op)
+	assert.Equal(t, 2, expr.Rest.Pos.Line)
+	assert.Equal(t, 2, expr.Rest.Pos.Column)
+	assert.Equal(t, "not", expr.Rest.Expr.Unary.Ops[0].Op)
+	assert.Equal(t, 3, expr.Rest.Expr.Unary.Ops[0].Pos.Line)
+	assert.Equal(t, "True", expr.Rest.Expr.Unary.Expr.Lit)
+}
+
+func TestParseFail(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "1:1: unexpected end of input"},
+		{"(and p", "1:1: unclosed '('"},
+		{"(and p q))", "1:10: unexpected text after the form"},
+		{")", "1:1: unexpected ')'"},
+		{"()", "1:1: empty form"},
+		{"(maybe p q)", "1:2: unknown operator 'maybe'"},
+		{"(implies p q r)", "1:1: 'implies' takes 2 arguments, got 3"},
+		{"(and p)", "1:1: 'and' takes at least 2 arguments, got 1"},
+		{"(not p q)", "1:1: 'not' takes 1 argument, got 2"},
+		{"(and p 12)", "1:8: unexpected '12'"},
+		{"(> (- x y z) 0)", "1:4: '-' takes 2 arguments, got 3"},
+		{"(> (^ x 2) 0)", "1:5: unknown operator '^'"},
+		{"(and p |q", "1:8: unclosed '|'"},
+		{"((and) p)", "1:2: expected an operator"},
+		{"(and next p)", "1:6: 'next' cannot name a variable, acorn reads it as a keyword"},
+		{"(or p |nand|)", "1:7: 'nand' cannot name a variable, acorn reads it as a keyword"},
+		{"(> in 0)", "1:4: 'in' cannot name a variable, acorn reads it as a keyword"},
+		{"(/= x 2)", "1:2: unknown operator '/='"},
+	}
+	for _, test := range tests {
+		_, err := SCHEME.Parse(test.input)
+		assert.EqualError(t, err, test.expected, test.input)
+	}
+}
+
+func TestParseFlavour(t *testing.T) {
+	flavour, err := ParseFlavour("clojure")
+	assert.NoError(t, err)
+	assert.Equal(t, "true", flavour.True)
+	_, err = ParseFlavour("elisp")
+	assert.EqualError(t, err, "unknown flavour 'elisp', expected common-lisp, scheme or clojure")
+}
@@ -0,0 +1,189 @@
+package program
+
+import (
+	"fmt"
+	"strings"
+
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ast"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ir"
+)
+
+// Function is one top-level definition as a function of the inputs it
+// reads, directly or through the definitions above it, in the order they
+// first appear in the file.
+type Function struct {
+	Pos    types.Position
+	Name   string
+	Params []string
+	Expr   *booleanAst.Expr
+	// Node is Expr lowered, which backends print.
+	Node ir.Node
+	// Doc is set by ReadDocs.
+	Doc string
+}
+
+type Program struct {
+	Name      string
+	Functions []Function
+}
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+// FromFile gathers the boolean definitions of file for a backend in
+// language, which names it in errors. Bare expressions and string
+// definitions are not compiled.
+func FromFile(name string, file *ast.File, language string) (*Program, error) {
+	exprs := []*ast.Expr{file.Head}
+	for _, tail := range file.Tail {
+		exprs = append(exprs, tail.Expr)
+	}
+	p := &Program{Name: name}
+	inputs := []string{}
+	params := map[string][]string{}
+	for _, expr := range exprs {
+		if expr.Def == nil || expr.Def.Str != nil {
+			continue
+		}
+		def := expr.Def
+		if _, ok := params[def.Name]; ok {
+			return nil, errorAt(def.Pos, "'%s' is already defined", def.Name)
+		}
+		if contains(inputs, def.Name) {
+			return nil, errorAt(def.Pos, "'%s' is used as an input above its definition", def.Name)
+		}
+		node, err := ir.Lower(def.Expr)
+		if err != nil {
+			return nil, err
+		}
+		if err := compilable(node, language); err != nil {
+			return nil, err
+		}
+		reads := map[string]bool{}
+		for _, v := range boolean.FreeVars(def.Expr) {
+			if defParams, ok := params[v]; ok {
+				for _, param := range defParams {
+					reads[param] = true
+				}
+				continue
+			}
+			if !contains(inputs, v) {
+				inputs = append(inputs, v)
+			}
+			reads[v] = true
+		}
+		acc := []string{}
+		for _, input := range inputs {
+			if reads[input] {
+				acc = append(acc, input)
+			}
+		}
+		params[def.Name] = acc
+		p.Functions = append(p.Functions, Function{Pos: def.Pos, Name: def.Name, Params: acc, Expr: def.Expr, Node: node})
+	}
+	if len(p.Functions) == 0 {
+		return nil, fmt.Errorf("%s: no definitions to compile", name)
+	}
+	return p, nil
+}
+
+// ReadDocs documents each function with the comment lines right above
+// its definition in source, the file p was read from, less their `#`.
+func (p *Program) ReadDocs(source []byte) {
+	lines := strings.Split(string(source), "\n")
+	for idx := range p.Functions {
+		fn := &p.Functions[idx]
+		acc := []string{}
+		for line := fn.Pos.Line - 2; line >= 0 && line < len(lines); line-- {
+			text := strings.TrimSpace(lines[line])
+			if !strings.HasPrefix(text, "#") {
+				break
+			}
+			acc = append([]string{strings.TrimPrefix(strings.TrimPrefix(text, "#"), " ")}, acc...)
+		}
+		fn.Doc = strings.Join(acc, "\n")
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, other := range names {
+		if other == name {
+			return true
+		}
+	}
+	return false
+}
+
+// compilable rejects what has no meaning outside the evaluator: temporal
+// operators, truth degrees and integer comparisons, whose variables have
+// no range.
+func compilable(node ir.Node, language string) error {
+	switch n := node.(type) {
+	case *ir.Unary:
+		if n.Op.IsTemporal() {
+			return errorAt(n.Pos, "temporal operator '%s' cannot be compiled to %s", n.Text, language)
+		}
+		return compilable(n.X, language)
+	case *ir.Binary:
+		if err := compilable(n.X, language); err != nil {
+			return err
+		}
+		if n.Op.IsTemporal() {
+			return errorAt(n.OpPos, "temporal operator '%s' cannot be compiled to %s", n.Text, language)
+		}
+		return compilable(n.Y, language)
+	case *ir.Cmp:
+		return errorAt(n.Pos, "integer comparison '%s' cannot be compiled to %s", arith.Format(n.Cmp), language)
+	case *ir.Degree:
+		return errorAt(n.Pos, "truth degree '%s' needs a fuzzy logic", n.Text)
+	}
+	return nil
+}
+
+// Regd. Truth tables
+
+// TruthTable evaluates p.Functions[idx] with boolean.Eval on every
+// assignment of its parameters, as a string of '0' and '1' whose first
+// parameter is the most significant bit. The functions above it are
+// evaluated first so it can read them.
+func (p *Program) TruthTable(idx int) (string, error) {
+	fns, params := p.Functions[:idx+1], p.Functions[idx].Params
+	var sb strings.Builder
+	for m := 0; m < 1<<len(params); m++ {
+		env := boolean.Env{}
+		for bit, param := range params {
+			env[param] = m&(1<<(len(params)-1-bit)) != 0
+		}
+		var res boolean.EvalResult
+		for _, fn := range fns {
+			if !covers(env, fn.Params) {
+				continue
+			}
+			res = boolean.Eval(fn.Node, env)
+			if res.Err != nil {
+				return "", res.Err
+			}
+			env[fn.Name] = bool(res.Payload.(types.Bool))
+		}
+		if res.Payload == types.Bool(true) {
+			sb.WriteString("1")
+		} else {
+			sb.WriteString("0")
+		}
+	}
+	return sb.String(), nil
+}
+
+func covers(env boolean.Env, params []string) bool {
+	for _, param := range params {
+		if _, ok := env[param]; !ok {
+			return false
+		}
+	}
+	return true
+}
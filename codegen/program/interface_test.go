@@ -0,0 +1,62 @@
+package program_test
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/codegen/internal/testutil"
+	"acornlang.dev/lang/codegen/program"
+	"acornlang.dev/lang/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParams(t *testing.T) {
+	p := testutil.Program(t, "C", "m", "let p := a and b\nlet q := not (p or c) => True\nlet k := False\nx or y\nlet s := \"text\"")
+	assert.Equal(t, []string{"p", "q", "k"}, []string{p.Functions[0].Name, p.Functions[1].Name, p.Functions[2].Name})
+	assert.Equal(t, []string{"a", "b"}, p.Functions[0].Params)
+	assert.Equal(t, []string{"a", "b", "c"}, p.Functions[1].Params)
+	assert.Empty(t, p.Functions[2].Params)
+}
+
+func TestFromFileFail(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a and b", "m: no definitions to compile"},
+		{"let p := a\nlet p := b", "2:1: 'p' is already defined"},
+		{"let p := q\nlet q := a", "2:1: 'q' is used as an input above its definition"},
+		{"let p := globally a", "1:10: temporal operator 'globally' cannot be compiled to C"},
+		{"let p := a and (b 𝒰 c)", "1:19: temporal operator '𝒰' cannot be compiled to C"},
+		{"let p := x + 1 > 2", "1:10: integer comparison 'x + 1 > 2' cannot be compiled to C"},
+		{"let p := 0.5 and a", "1:10: truth degree '0.5' needs a fuzzy logic"},
+	}
+	for _, test := range tests {
+		file, err := parser.FileParser.ParseString("", test.input)
+		assert.NoError(t, err, test.input)
+		_, err = program.FromFile("m", file, "C")
+		assert.EqualError(t, err, test.expected, test.input)
+	}
+}
+
+func TestTruthTable(t *testing.T) {
+	p := testutil.Program(t, "C", "m", "let p := a and not b\nlet q := p or c\nlet k := True")
+	tests := []string{"0010", "01011101", "1"}
+	for idx, expected := range tests {
+		table, err := p.TruthTable(idx)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, table, p.Functions[idx].Name)
+	}
+}
+
+func TestReadDocs(t *testing.T) {
+	source := "# Sum bit.\n#\n#  Indented.\nlet sum := a xor b # not a doc\nlet carry := a and b\n\n# Detached.\n\nlet k := True\n"
+	file, err := parser.FileParser.ParseString("", source)
+	assert.NoError(t, err)
+	p, err := program.FromFile("m", file, "C")
+	assert.NoError(t, err)
+	assert.Equal(t, "", p.Functions[0].Doc)
+	p.ReadDocs([]byte(source))
+	assert.Equal(t, "Sum bit.\n\n Indented.", p.Functions[0].Doc)
+	assert.Equal(t, "", p.Functions[1].Doc)
+	assert.Equal(t, "", p.Functions[2].Doc)
+}
@@ -0,0 +1,85 @@
+package python
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"acornlang.dev/lang/codegen/program"
+	"acornlang.dev/lang/codegen/syntax"
+)
+
+// MAX_TEST_PARAMS bounds the truth tables the tests spell out.
+const MAX_TEST_PARAMS = 16
+
+// PYTHON binds `not` looser than == and !=, which do not chain here as
+// they would in Python, so both are bracketed when nested.
+var PYTHON = syntax.Language{
+	Ops:    map[syntax.Op]string{syntax.Not: "not ", syntax.And: "and", syntax.Or: "or", syntax.Eq: "==", syntax.Ne: "!="},
+	Levels: map[syntax.Op]int{syntax.Eq: 4, syntax.Ne: 4, syntax.Not: 3, syntax.And: 2, syntax.Or: 1},
+	True:   "True",
+	False:  "False",
+	Reserved: map[string]bool{
+		"False": true, "None": true, "True": true, "and": true, "as": true,
+		"assert": true, "async": true, "await": true, "break": true,
+		"class": true, "continue": true, "def": true, "del": true, "elif": true,
+		"else": true, "except": true, "finally": true, "for": true, "from": true,
+		"global": true, "if": true, "import": true, "in": true, "is": true,
+		"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true,
+		"raise": true, "return": true, "try": true, "while": true, "with": true,
+		"yield": true,
+	},
+	Ident:  regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`),
+	Prefix: "v_",
+}
+
+// Module is the name to give the generated module so the tests can
+// import it; they refer to it as `generated`.
+func Module(p *program.Program) string {
+	module := PYTHON
+	module.Prefix = "m_"
+	return syntax.NewNamer(&module).Name(p.Name)
+}
+
+// Generate emits a Python module with one type-hinted function per
+// definition.
+func Generate(p *program.Program) string {
+	pr := syntax.NewPrinter(&PYTHON, p)
+	var sb strings.Builder
+	sb.WriteString("# Generated by ac gen. Do not edit.\n")
+	for _, fn := range p.Functions {
+		body, _ := pr.Body(fn)
+		params := []string{}
+		for _, param := range fn.Params {
+			params = append(params, pr.Var(param)+": bool")
+		}
+		fmt.Fprintf(&sb, "\n\ndef %s(%s) -> bool:\n    return %s\n", pr.Names.Name(fn.Name), strings.Join(params, ", "), body)
+	}
+	return sb.String()
+}
+
+// Regd. Tests
+
+// Tests emits a pytest module with one test per function of p, comparing
+// it on every assignment of its parameters with the truth table
+// boolean.EvalExpr gives.
+func Tests(p *program.Program) (string, error) {
+	pr := syntax.NewPrinter(&PYTHON, p)
+	var sb strings.Builder
+	sb.WriteString("# Generated by ac gen. Do not edit.\n")
+	fmt.Fprintf(&sb, "import itertools\n\nimport %s as generated\n", Module(p))
+	for idx, fn := range p.Functions {
+		if len(fn.Params) > MAX_TEST_PARAMS {
+			return "", fmt.Errorf("'%s' has %d inputs, the tests take at most %d", fn.Name, len(fn.Params), MAX_TEST_PARAMS)
+		}
+		table, err := p.TruthTable(idx)
+		if err != nil {
+			return "", err
+		}
+		name := pr.Names.Name(fn.Name)
+		fmt.Fprintf(&sb, "\n\ndef test_%s():\n    expected = \"%s\"\n", name, table)
+		fmt.Fprintf(&sb, "    for row, args in enumerate(itertools.product((False, True), repeat=%d)):\n", len(fn.Params))
+		fmt.Fprintf(&sb, "        assert generated.%s(*args) == (expected[row] == \"1\"), args\n", name)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,124 @@
+package python
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"acornlang.dev/lang/codegen/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHalfAdder(t *testing.T) {
+	p := testutil.Program(t, "Python", "half_adder", "let sum := a xor b\nlet carry := a and b")
+	assert.Equal(t, `# Generated by ac gen. Do not edit.
+
+
+def sum(a: bool, b: bool) -> bool:
+    return a != b
+
+
+def carry(a: bool, b: bool) -> bool:
+    return a and b
+`, Generate(p))
+	tests, err := Tests(p)
+	assert.NoError(t, err)
+	assert.Equal(t, `# Generated by ac gen. Do not edit.
+import itertools
+
+import half_adder as generated
+
+
+def test_sum():
+    expected = "0110"
+    for row, args in enumerate(itertools.product((False, True), repeat=2)):
+        assert generated.sum(*args) == (expected[row] == "1"), args
+
+
+def test_carry():
+    expected = "0001"
+    for row, args in enumerate(itertools.product((False, True), repeat=2)):
+        assert generated.carry(*args) == (expected[row] == "1"), args
+`, tests)
+}
+
+func TestExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let p := not (a or b) => True", "a or b or True"},
+		{"let p := not (a iff b) and c", "not a == b and c"},
+		{"let p := ~a <=> ~b", "(not a) == (not b)"},
+		{"let p := a xor (b <~> c)", "a != (b != c)"},
+		{"let p := a inhibits b", "a and not b"},
+		{"let p := a is inhibited by b", "not a and b"},
+		{"let p := a not right (b or c)", "not (b or c)"},
+		{"let p := nullify a or truify b", "False or True"},
+		{"let p := a nand b\nlet q := p nor c", "not (p(a, b) or c)"},
+	}
+	for _, test := range tests {
+		p := testutil.Program(t, "Python", "m", test.input)
+		assert.Contains(t, Generate(p), "    return "+test.expected+"\n", test.input)
+	}
+}
+
+func TestNamesAreMangled(t *testing.T) {
+	p := testutil.Program(t, "Python", "2-bit", "let lambda := _x and yield\nlet k := False")
+	assert.Equal(t, "m_2_bit", Module(p))
+	source := Generate(p)
+	assert.Contains(t, source, "def lambda_(_x: bool, yield_: bool) -> bool:\n")
+	assert.Contains(t, source, "def k() -> bool:\n    return False\n")
+}
+
+func TestTestsTakeAtMostSixteenInputs(t *testing.T) {
+	inputs := []string{}
+	for idx := 0; idx <= MAX_TEST_PARAMS; idx++ {
+		inputs = append(inputs, fmt.Sprintf("x%d", idx))
+	}
+	_, err := Tests(testutil.Program(t, "Python", "m", "let p := "+strings.Join(inputs, " and ")))
+	assert.EqualError(t, err, "'p' has 17 inputs, the tests take at most 16")
+}
+
+// The tests compare every operator against the evaluator; they need
+// python3, and are skipped without it. The test functions are called
+// directly, so pytest need not be installed.
+func TestGeneratedTestsPass(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("no python3")
+	}
+	input, err := os.ReadFile("../testdata/operators.lx")
+	assert.NoError(t, err)
+	p := testutil.Program(t, "Python", "ops", string(input))
+	tests, err := Tests(p)
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "ops.py"), []byte(Generate(p)), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "test_ops.py"), []byte(tests), 0o644))
+	run := "import test_ops\nfor name in dir(test_ops):\n    if name.startswith('test_'):\n        getattr(test_ops, name)()\n"
+	cmd := exec.Command(python, "-c", run)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(out))
+}
+
+func TestGeneratedTestsCatchWrongOutput(t *testing.T) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("no python3")
+	}
+	tests, err := Tests(testutil.Program(t, "Python", "m", "let p := a or b"))
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "m.py"), []byte(Generate(testutil.Program(t, "Python", "m", "let p := a and b"))), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "test_m.py"), []byte(tests), 0o644))
+	cmd := exec.Command(python, "-c", "import test_m\ntest_m.test_p()")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	assert.Error(t, err)
+	assert.Contains(t, string(out), "AssertionError: (False, True)")
+}
@@ -0,0 +1,54 @@
+package raku
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"acornlang.dev/lang/codegen/program"
+	"acornlang.dev/lang/codegen/syntax"
+)
+
+// RAKU keeps to the symbolic operators, which bind tighter than the
+// comparisons; `not`, `and` and `or` are looser than a list.
+var RAKU = syntax.Language{
+	Ops:    map[syntax.Op]string{syntax.Not: "!", syntax.And: "&&", syntax.Or: "||", syntax.Eq: "==", syntax.Ne: "!="},
+	Levels: map[syntax.Op]int{syntax.Not: 4, syntax.Eq: 3, syntax.Ne: 3, syntax.And: 2, syntax.Or: 1},
+	True:   "True",
+	False:  "False",
+	Sigil:  "$",
+	Reserved: map[string]bool{
+		"False": true, "True": true, "and": true, "class": true, "default": true,
+		"do": true, "else": true, "elsif": true, "for": true, "given": true,
+		"has": true, "if": true, "last": true, "loop": true, "module": true,
+		"my": true, "next": true, "not": true, "or": true, "our": true,
+		"repeat": true, "return": true, "so": true, "sub": true, "unit": true,
+		"unless": true, "until": true, "when": true, "while": true,
+		"with": true, "xor": true,
+	},
+	Ident:  regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`),
+	Prefix: "v_",
+}
+
+// Module is the name of the Raku module for p, and of its .rakumod file.
+func Module(p *program.Program) string {
+	return syntax.Camel(p.Name)
+}
+
+// Generate emits a Raku module exporting one typed sub per definition.
+func Generate(p *program.Program) string {
+	pr := syntax.NewPrinter(&RAKU, p)
+	var sb strings.Builder
+	sb.WriteString("# Generated by ac gen. Do not edit.\n")
+	fmt.Fprintf(&sb, "unit module %s;\n", Module(p))
+	for _, fn := range p.Functions {
+		body, _ := pr.Body(fn)
+		params := []string{}
+		for _, param := range fn.Params {
+			params = append(params, "Bool "+pr.Var(param))
+		}
+		signature := strings.TrimPrefix(strings.Join(params, ", ")+" --> Bool", " ")
+		fmt.Fprintf(&sb, "\nsub %s(%s) is export {\n    %s\n}\n", pr.Names.Name(fn.Name), signature, body)
+	}
+	return sb.String()
+}
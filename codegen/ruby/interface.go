@@ -0,0 +1,58 @@
+package ruby
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"acornlang.dev/lang/codegen/program"
+	"acornlang.dev/lang/codegen/syntax"
+)
+
+var RUBY = syntax.Language{
+	Ops:    map[syntax.Op]string{syntax.Not: "!", syntax.And: "&&", syntax.Or: "||", syntax.Eq: "==", syntax.Ne: "!="},
+	Levels: map[syntax.Op]int{syntax.Not: 4, syntax.Eq: 3, syntax.Ne: 3, syntax.And: 2, syntax.Or: 1},
+	True:   "true",
+	False:  "false",
+	Reserved: map[string]bool{
+		"BEGIN": true, "END": true, "alias": true, "and": true, "begin": true,
+		"break": true, "case": true, "class": true, "def": true,
+		"defined": true, "do": true, "else": true, "elsif": true, "end": true,
+		"ensure": true, "false": true, "for": true, "if": true, "in": true,
+		"module": true, "next": true, "nil": true, "not": true, "or": true,
+		"redo": true, "rescue": true, "retry": true, "return": true,
+		"self": true, "super": true, "then": true, "true": true,
+		"undef": true, "unless": true, "until": true, "when": true,
+		"while": true, "yield": true,
+	},
+	// A capital would make a constant.
+	Ident:  regexp.MustCompile(`^[a-z_][a-zA-Z0-9_]*$`),
+	Prefix: "v_",
+}
+
+// Module is the name of the Ruby module for p.
+func Module(p *program.Program) string {
+	return syntax.Camel(p.Name)
+}
+
+// Generate emits a Ruby module with one module function per definition.
+func Generate(p *program.Program) string {
+	pr := syntax.NewPrinter(&RUBY, p)
+	var sb strings.Builder
+	sb.WriteString("# Generated by ac gen. Do not edit.\n")
+	fmt.Fprintf(&sb, "module %s\n  module_function\n", Module(p))
+	for _, fn := range p.Functions {
+		body, _ := pr.Body(fn)
+		params := []string{}
+		for _, param := range fn.Params {
+			params = append(params, pr.Var(param))
+		}
+		signature := pr.Names.Name(fn.Name)
+		if len(params) > 0 {
+			signature += "(" + strings.Join(params, ", ") + ")"
+		}
+		fmt.Fprintf(&sb, "\n  def %s\n    %s\n  end\n", signature, body)
+	}
+	sb.WriteString("end\n")
+	return sb.String()
+}
@@ -0,0 +1,266 @@
+package syntax
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"acornlang.dev/lang/codegen/program"
+	"acornlang.dev/lang/types/ir"
+)
+
+// Op is one of the few connectives every target has; the others are
+// lowered onto them.
+type Op int
+
+const (
+	Leaf Op = iota
+	Not
+	And
+	Or
+	Eq
+	Ne
+)
+
+// Node is an expression over the connectives, or a Leaf whose Text is
+// already spelled in the target language.
+type Node struct {
+	Op   Op
+	Kids []*Node
+	Text string
+}
+
+// Language describes how a target spells expressions and names.
+type Language struct {
+	// Ops spells each connective; Not is a prefix and takes its own
+	// trailing space, if any.
+	Ops map[Op]string
+	// Levels are the binding strengths of the connectives, the higher the
+	// tighter. And and Or group either way; Eq and Ne are bracketed when
+	// nested, as some targets chain or reject them.
+	Levels map[Op]int
+	True   string
+	False  string
+	// Sigil goes before every variable.
+	Sigil    string
+	Reserved map[string]bool
+	// Ident matches the names the target accepts; others get Prefix.
+	Ident  *regexp.Regexp
+	Prefix string
+	// Export, if set, turns a definition's name into its function's.
+	Export func(string) string
+}
+
+var invalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// Regd. Names
+
+// Namer gives every identifier of a program a distinct name the target
+// accepts: invalid characters become underscores, names Ident rejects get
+// Prefix and reserved words get a trailing underscore.
+type Namer struct {
+	lang  *Language
+	names map[string]string
+	taken map[string]bool
+}
+
+func NewNamer(lang *Language) *Namer {
+	return &Namer{lang: lang, names: map[string]string{}, taken: map[string]bool{}}
+}
+
+func (n *Namer) Name(ident string) string {
+	return n.NameAs(ident, ident)
+}
+
+// NameAs names ident after base, unless it is named already.
+func (n *Namer) NameAs(ident string, base string) string {
+	if name, ok := n.names[ident]; ok {
+		return name
+	}
+	base = invalidChars.ReplaceAllString(base, "_")
+	if !n.lang.Ident.MatchString(base) {
+		base = n.lang.Prefix + base
+	}
+	if n.lang.Reserved[base] {
+		base += "_"
+	}
+	name := base
+	for idx := 1; n.taken[name]; idx++ {
+		name = fmt.Sprintf("%s_%d", base, idx)
+	}
+	n.names[ident] = name
+	n.taken[name] = true
+	return name
+}
+
+// Camel turns a file name into a module name: "half_adder" becomes
+// "HalfAdder" and "2-bit" becomes "M2Bit".
+func Camel(name string) string {
+	var sb strings.Builder
+	for _, word := range invalidChars.Split(strings.ReplaceAll(name, "_", " "), -1) {
+		for _, part := range strings.Fields(word) {
+			runes := []rune(part)
+			sb.WriteRune(unicode.ToUpper(runes[0]))
+			sb.WriteString(string(runes[1:]))
+		}
+	}
+	if sb.Len() == 0 || unicode.IsDigit(rune(sb.String()[0])) {
+		return "M" + sb.String()
+	}
+	return sb.String()
+}
+
+// Regd. Lowering
+
+// Printer spells the definitions of one program in one target; a
+// definition read by a later one becomes a call.
+type Printer struct {
+	lang   *Language
+	Names  *Namer
+	params map[string][]string
+	used   map[string]bool
+}
+
+func NewPrinter(lang *Language, p *program.Program) *Printer {
+	pr := &Printer{lang: lang, Names: NewNamer(lang), params: map[string][]string{}}
+	for _, fn := range p.Functions {
+		if lang.Export != nil {
+			pr.Names.NameAs(fn.Name, lang.Export(fn.Name))
+		} else {
+			pr.Names.Name(fn.Name)
+		}
+		pr.params[fn.Name] = fn.Params
+	}
+	return pr
+}
+
+// Var is the name of an input, sigil included.
+func (pr *Printer) Var(ident string) string {
+	return pr.lang.Sigil + pr.Names.Name(ident)
+}
+
+// Body spells the expression of fn, and reports the parameters it reads;
+// the ignored side of a projection and the operand of nullify or truify
+// are not read.
+func (pr *Printer) Body(fn program.Function) (string, map[string]bool) {
+	pr.used = map[string]bool{}
+	return pr.lang.Print(pr.node(fn.Node)), pr.used
+}
+
+func not(n *Node) *Node {
+	if n.Op == Not {
+		return n.Kids[0]
+	}
+	return &Node{Op: Not, Kids: []*Node{n}}
+}
+
+func binary(op Op, left *Node, right *Node) *Node {
+	return &Node{Op: op, Kids: []*Node{left, right}}
+}
+
+// node lowers onto the connectives: a projection and nullify or truify
+// keep nothing of what they ignore.
+func (pr *Printer) node(node ir.Node) *Node {
+	switch n := node.(type) {
+	case *ir.Var:
+		params, ok := pr.params[n.Name]
+		if !ok {
+			pr.used[n.Name] = true
+			return &Node{Text: pr.Var(n.Name)}
+		}
+		args := []string{}
+		for _, param := range params {
+			args = append(args, pr.Var(param))
+			pr.used[param] = true
+		}
+		return &Node{Text: fmt.Sprintf("%s(%s)", pr.Names.Name(n.Name), strings.Join(args, ", "))}
+	case *ir.Const:
+		if n.Value {
+			return &Node{Text: pr.lang.True}
+		}
+		return &Node{Text: pr.lang.False}
+	case *ir.Unary:
+		switch n.Op {
+		case ir.NULLIFY:
+			return &Node{Text: pr.lang.False}
+		case ir.TRUIFY:
+			return &Node{Text: pr.lang.True}
+		case ir.NOT:
+			return not(pr.node(n.X))
+		default:
+			return pr.node(n.X)
+		}
+	case *ir.Binary:
+		return pr.binary(n)
+	default:
+		return &Node{Text: pr.lang.False}
+	}
+}
+
+func (pr *Printer) binary(n *ir.Binary) *Node {
+	switch n.Op {
+	case ir.LEFT:
+		return pr.node(n.X)
+	case ir.RIGHT:
+		return pr.node(n.Y)
+	case ir.NOT_LEFT:
+		return not(pr.node(n.X))
+	case ir.NOT_RIGHT:
+		return not(pr.node(n.Y))
+	}
+	left, right := pr.node(n.X), pr.node(n.Y)
+	switch n.Op {
+	case ir.AND:
+		return binary(And, left, right)
+	case ir.NAND:
+		return not(binary(And, left, right))
+	case ir.OR:
+		return binary(Or, left, right)
+	case ir.NOR:
+		return not(binary(Or, left, right))
+	case ir.IMPLIES:
+		return binary(Or, not(left), right)
+	case ir.IMPLIED_BY:
+		return binary(Or, left, not(right))
+	case ir.INHIBITS:
+		return binary(And, left, not(right))
+	case ir.INHIBITED_BY:
+		return binary(And, not(left), right)
+	case ir.IFF:
+		return binary(Eq, left, right)
+	default:
+		return binary(Ne, left, right)
+	}
+}
+
+// Regd. Printing
+
+func (l *Language) level(n *Node) int {
+	if n.Op == Leaf {
+		return int(^uint(0) >> 1)
+	}
+	return l.Levels[n.Op]
+}
+
+// operand brackets n under parent only when the levels call for it.
+func (l *Language) operand(n *Node, parent Op) string {
+	text := l.Print(n)
+	level, outer := l.level(n), l.Levels[parent]
+	regroups := n.Op == parent && (parent == Not || parent == And || parent == Or)
+	if level < outer || level == outer && !regroups {
+		return "(" + text + ")"
+	}
+	return text
+}
+
+func (l *Language) Print(n *Node) string {
+	switch n.Op {
+	case Leaf:
+		return n.Text
+	case Not:
+		return l.Ops[Not] + l.operand(n.Kids[0], Not)
+	default:
+		return l.operand(n.Kids[0], n.Op) + " " + l.Ops[n.Op] + " " + l.operand(n.Kids[1], n.Op)
+	}
+}
@@ -0,0 +1,120 @@
+package syntax
+
+import (
+	"regexp"
+	"testing"
+
+	"acornlang.dev/lang/codegen/internal/testutil"
+	"acornlang.dev/lang/parser/boolean"
+	"github.com/stretchr/testify/assert"
+)
+
+// acorn spells the connectives the way the parser reads them. Having no
+// precedence, every level is the same, so only Not goes unbracketed.
+var acorn = Language{
+	Ops:    map[Op]string{Not: "~", And: "/\\", Or: "\\/", Eq: "<=>", Ne: "<~>"},
+	Levels: map[Op]int{Not: 2, And: 1, Or: 1, Eq: 1, Ne: 1},
+	True:   "True",
+	False:  "False",
+	Ident:  regexp.MustCompile(`^[a-z][a-z0-9_]*$`),
+	Prefix: "v_",
+}
+
+// python binds `not` looser than the comparisons.
+var python = Language{
+	Ops:    map[Op]string{Not: "not ", And: "and", Or: "or", Eq: "==", Ne: "!="},
+	Levels: map[Op]int{Eq: 4, Ne: 4, Not: 3, And: 2, Or: 1},
+	True:   "True",
+	False:  "False",
+	Ident:  regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`),
+	Prefix: "v_",
+}
+
+func TestPrint(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a and b and c", "a and b and c"},
+		{"(a and b) or c", "a and b or c"},
+		{"a and (b or c)", "a and (b or c)"},
+		{"a nand b", "not (a and b)"},
+		{"not not a implies b", "not a or b"},
+		{"not a iff b", "(not a) == b"},
+		{"not (a iff b)", "not a == b"},
+		{"a xor b xor c", "a != (b != c)"},
+		{"(a xor b) iff c", "(a != b) == c"},
+		{"a not right b inhibits c", "not (b and not c)"},
+		{"truify a or nullify b", "True or False"},
+	}
+	for _, test := range tests {
+		p := testutil.Program(t, "test", "m", "let p := "+test.input)
+		body, _ := NewPrinter(&python, p).Body(p.Functions[0])
+		assert.Equal(t, test.expected, body, test.input)
+	}
+}
+
+func TestBodyReports(t *testing.T) {
+	p := testutil.Program(t, "test", "m", "let p := a left b\nlet q := p or nullify c\nlet r := q and d")
+	pr := NewPrinter(&python, p)
+	body, used := pr.Body(p.Functions[1])
+	assert.Equal(t, "p(a, b) or False", body)
+	assert.Equal(t, map[string]bool{"a": true, "b": true}, used)
+	_, used = pr.Body(p.Functions[0])
+	assert.Equal(t, map[string]bool{"a": true}, used)
+}
+
+// Spelled in acorn again, every lowering parses back to the same function.
+func TestLoweringAgreesWithEvalExpr(t *testing.T) {
+	inputs := []string{
+		"a nand b ~/\\ c",
+		"a nor b ~\\/ c",
+		"a xor b <~> c",
+		"a iff b xnor c <=> a",
+		"a implies b => c",
+		"a is implied by b <= c",
+		"a inhibits b /=> c",
+		"a is inhibited by b <=/ c",
+		"a left b <s c",
+		"a right b s> c",
+		"a not left b </ c",
+		"a not right b /> c",
+		"not ~a and nullify b or truify c and id a",
+		"~a <~> ~b xnor ~(c nand a)",
+		"((a or b) and c) => not (a xor (b => c))",
+	}
+	vars := []string{"a", "b", "c"}
+	for _, input := range inputs {
+		expr, err := boolean.ExprParser.ParseString("", input)
+		assert.NoError(t, err, input)
+		p := testutil.Program(t, "test", "m", "let p := "+input)
+		body, _ := NewPrinter(&acorn, p).Body(p.Functions[0])
+		printed, err := boolean.ExprParser.ParseString("", body)
+		assert.NoError(t, err, body)
+		for minterm := 0; minterm < 1<<len(vars); minterm++ {
+			env := boolean.Env{}
+			for idx, name := range vars {
+				env[name] = minterm&(1<<idx) != 0
+			}
+			assert.Equal(t, boolean.EvalExpr(expr, env).Payload, boolean.EvalExpr(printed, env).Payload, input+" printed as "+body)
+		}
+	}
+}
+
+func TestNamer(t *testing.T) {
+	lang := acorn
+	lang.Reserved = map[string]bool{"end": true}
+	n := NewNamer(&lang)
+	assert.Equal(t, "end_", n.Name("end"))
+	assert.Equal(t, "v_Big", n.Name("Big"))
+	assert.Equal(t, "v__x", n.Name("_x"))
+	assert.Equal(t, "end_", n.Name("end"))
+	assert.Equal(t, "end__1", n.Name("end_"))
+}
+
+func TestCamel(t *testing.T) {
+	assert.Equal(t, "HalfAdder", Camel("half_adder"))
+	assert.Equal(t, "M2Bit", Camel("2-bit"))
+	assert.Equal(t, "Rules", Camel("rules"))
+	assert.Equal(t, "M", Camel("__"))
+}
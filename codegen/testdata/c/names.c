@@ -0,0 +1,18 @@
+/* Generated by ac gen. Do not edit. */
+#include "names.h"
+
+static inline bool end(bool Big, bool v__x) {
+    return Big && v__x;
+}
+
+static inline bool when(bool Big, bool v__x) {
+    return end(Big, v__x) || !Big;
+}
+
+bool names_end(bool Big, bool v__x) {
+    return end(Big, v__x);
+}
+
+bool names_when(bool Big, bool v__x) {
+    return when(Big, v__x);
+}
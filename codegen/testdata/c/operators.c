@@ -0,0 +1,156 @@
+/* Generated by ac gen. Do not edit. */
+#include "operators.h"
+
+static inline bool and_(bool a, bool b, bool c) {
+    return a && b && c;
+}
+
+static inline bool nand_(bool a, bool b, bool c) {
+    return !(a && !(b && c));
+}
+
+static inline bool or_(bool a, bool b, bool c) {
+    return a || b || c;
+}
+
+static inline bool nor_(bool a, bool b, bool c) {
+    return !(a || !(b || c));
+}
+
+static inline bool xor_(bool a, bool b, bool c) {
+    return a != (b != c);
+}
+
+static inline bool equiv(bool a, bool b, bool c) {
+    return a == (b == (c == a));
+}
+
+static inline bool imp(bool a, bool b, bool c) {
+    return !a || !b || c;
+}
+
+static inline bool imp_by(bool a, bool b, bool c) {
+    return a || !(b || !c);
+}
+
+static inline bool inh(bool a, bool b, bool c) {
+    return a && !(b && !c);
+}
+
+static inline bool inh_by(bool a, bool b, bool c) {
+    return !a && !b && c;
+}
+
+static inline bool left_(bool a, bool b, bool c) {
+    (void)b;
+    (void)c;
+    return a;
+}
+
+static inline bool right_(bool a, bool b, bool c) {
+    (void)a;
+    (void)b;
+    return c;
+}
+
+static inline bool not_left(bool a, bool b, bool c) {
+    (void)b;
+    (void)c;
+    return !a;
+}
+
+static inline bool not_right(bool a, bool b, bool c) {
+    (void)a;
+    (void)b;
+    return c;
+}
+
+static inline bool unary(bool a, bool b, bool c) {
+    (void)b;
+    (void)c;
+    return a && (false || (true && a));
+}
+
+static inline bool negated(bool a, bool b, bool c) {
+    return !a != (!b == !c);
+}
+
+static inline bool nested(bool a, bool b, bool c) {
+    return (and_(a, b, c) || nand_(a, b, c)) && !(imp(a, b, c) != left_(a, b, c));
+}
+
+static inline bool constant(void) {
+    return false;
+}
+
+bool operators_and_(bool a, bool b, bool c) {
+    return and_(a, b, c);
+}
+
+bool operators_nand_(bool a, bool b, bool c) {
+    return nand_(a, b, c);
+}
+
+bool operators_or_(bool a, bool b, bool c) {
+    return or_(a, b, c);
+}
+
+bool operators_nor_(bool a, bool b, bool c) {
+    return nor_(a, b, c);
+}
+
+bool operators_xor_(bool a, bool b, bool c) {
+    return xor_(a, b, c);
+}
+
+bool operators_equiv(bool a, bool b, bool c) {
+    return equiv(a, b, c);
+}
+
+bool operators_imp(bool a, bool b, bool c) {
+    return imp(a, b, c);
+}
+
+bool operators_imp_by(bool a, bool b, bool c) {
+    return imp_by(a, b, c);
+}
+
+bool operators_inh(bool a, bool b, bool c) {
+    return inh(a, b, c);
+}
+
+bool operators_inh_by(bool a, bool b, bool c) {
+    return inh_by(a, b, c);
+}
+
+bool operators_left_(bool a, bool b, bool c) {
+    return left_(a, b, c);
+}
+
+bool operators_right_(bool a, bool b, bool c) {
+    return right_(a, b, c);
+}
+
+bool operators_not_left(bool a, bool b, bool c) {
+    return not_left(a, b, c);
+}
+
+bool operators_not_right(bool a, bool b, bool c) {
+    return not_right(a, b, c);
+}
+
+bool operators_unary(bool a, bool b, bool c) {
+    return unary(a, b, c);
+}
+
+bool operators_negated(bool a, bool b, bool c) {
+    return negated(a, b, c);
+}
+
+bool operators_nested(bool a, bool b, bool c) {
+    return nested(a, b, c);
+}
+
+bool operators_constant(void) {
+    return constant();
+}
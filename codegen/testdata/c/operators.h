@@ -0,0 +1,26 @@
+/* Generated by ac gen. Do not edit. */
+#ifndef OPERATORS_H
+#define OPERATORS_H
+
+#include <stdbool.h>
+
+bool operators_and_(bool a, bool b, bool c);
+bool operators_nand_(bool a, bool b, bool c);
+bool operators_or_(bool a, bool b, bool c);
+bool operators_nor_(bool a, bool b, bool c);
+bool operators_xor_(bool a, bool b, bool c);
+bool operators_equiv(bool a, bool b, bool c);
+bool operators_imp(bool a, bool b, bool c);
+bool operators_imp_by(bool a, bool b, bool c);
+bool operators_inh(bool a, bool b, bool c);
+bool operators_inh_by(bool a, bool b, bool c);
+bool operators_left_(bool a, bool b, bool c);
+bool operators_right_(bool a, bool b, bool c);
+bool operators_not_left(bool a, bool b, bool c);
+bool operators_not_right(bool a, bool b, bool c);
+bool operators_unary(bool a, bool b, bool c);
+bool operators_negated(bool a, bool b, bool c);
+bool operators_nested(bool a, bool b, bool c);
+bool operators_constant(void);
+
+#endif /* OPERATORS_H */
@@ -0,0 +1,12 @@
+# Generated by ac gen. Do not edit.
+defmodule Names do
+  @spec end_(boolean(), boolean()) :: boolean()
+  def end_(v_Big, v__x) do
+    v_Big and v__x
+  end
+
+  @spec when_(boolean(), boolean()) :: boolean()
+  def when_(v_Big, v__x) do
+    end_(v_Big, v__x) or not v_Big
+  end
+end
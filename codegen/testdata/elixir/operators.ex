@@ -0,0 +1,92 @@
+# Generated by ac gen. Do not edit.
+defmodule Operators do
+  @spec and_(boolean(), boolean(), boolean()) :: boolean()
+  def and_(a, b, c) do
+    a and b and c
+  end
+
+  @spec nand_(boolean(), boolean(), boolean()) :: boolean()
+  def nand_(a, b, c) do
+    not (a and not (b and c))
+  end
+
+  @spec or_(boolean(), boolean(), boolean()) :: boolean()
+  def or_(a, b, c) do
+    a or b or c
+  end
+
+  @spec nor_(boolean(), boolean(), boolean()) :: boolean()
+  def nor_(a, b, c) do
+    not (a or not (b or c))
+  end
+
+  @spec xor_(boolean(), boolean(), boolean()) :: boolean()
+  def xor_(a, b, c) do
+    a != (b != c)
+  end
+
+  @spec equiv(boolean(), boolean(), boolean()) :: boolean()
+  def equiv(a, b, c) do
+    a == (b == (c == a))
+  end
+
+  @spec imp(boolean(), boolean(), boolean()) :: boolean()
+  def imp(a, b, c) do
+    not a or not b or c
+  end
+
+  @spec imp_by(boolean(), boolean(), boolean()) :: boolean()
+  def imp_by(a, b, c) do
+    a or not (b or not c)
+  end
+
+  @spec inh(boolean(), boolean(), boolean()) :: boolean()
+  def inh(a, b, c) do
+    a and not (b and not c)
+  end
+
+  @spec inh_by(boolean(), boolean(), boolean()) :: boolean()
+  def inh_by(a, b, c) do
+    not a and not b and c
+  end
+
+  @spec left_(boolean(), boolean(), boolean()) :: boolean()
+  def left_(a, _b, _c) do
+    a
+  end
+
+  @spec right_(boolean(), boolean(), boolean()) :: boolean()
+  def right_(_a, _b, c) do
+    c
+  end
+
+  @spec not_left(boolean(), boolean(), boolean()) :: boolean()
+  def not_left(a, _b, _c) do
+    not a
+  end
+
+  @spec not_right(boolean(), boolean(), boolean()) :: boolean()
+  def not_right(_a, _b, c) do
+    c
+  end
+
+  @spec unary(boolean(), boolean(), boolean()) :: boolean()
+  def unary(a, _b, _c) do
+    a and (false or true and a)
+  end
+
+  @spec negated(boolean(), boolean(), boolean()) :: boolean()
+  def negated(a, b, c) do
+    not a != (not b == not c)
+  end
+
+  @spec nested(boolean(), boolean(), boolean()) :: boolean()
+  def nested(a, b, c) do
+    (and_(a, b, c) or nand_(a, b, c)) and not (imp(a, b, c) != left_(a, b, c))
+  end
+
+  @spec constant() :: boolean()
+  def constant do
+    false
+  end
+end
@@ -0,0 +1,11 @@
+// Code generated by ac gen. DO NOT EDIT.
+
+package names
+
+func End(Big, _x bool) bool {
+	return Big && _x
+}
+
+func When(Big, _x bool) bool {
+	return End(Big, _x) || !Big
+}
@@ -0,0 +1,75 @@
+// Code generated by ac gen. DO NOT EDIT.
+
+package operators
+
+func And(a, b, c bool) bool {
+	return a && b && c
+}
+
+func Nand(a, b, c bool) bool {
+	return !(a && !(b && c))
+}
+
+func Or(a, b, c bool) bool {
+	return a || b || c
+}
+
+func Nor(a, b, c bool) bool {
+	return !(a || !(b || c))
+}
+
+func Xor(a, b, c bool) bool {
+	return a != (b != c)
+}
+
+func Equiv(a, b, c bool) bool {
+	return a == (b == (c == a))
+}
+
+func Imp(a, b, c bool) bool {
+	return !a || !b || c
+}
+
+func ImpBy(a, b, c bool) bool {
+	return a || !(b || !c)
+}
+
+func Inh(a, b, c bool) bool {
+	return a && !(b && !c)
+}
+
+func InhBy(a, b, c bool) bool {
+	return !a && !b && c
+}
+
+func Left(a, b, c bool) bool {
+	return a
+}
+
+func Right(a, b, c bool) bool {
+	return c
+}
+
+func NotLeft(a, b, c bool) bool {
+	return !a
+}
+
+func NotRight(a, b, c bool) bool {
+	return c
+}
+
+func Unary(a, b, c bool) bool {
+	return a && (false || true && a)
+}
+
+func Negated(a, b, c bool) bool {
+	return !a != (!b == !c)
+}
+
+func Nested(a, b, c bool) bool {
+	return (And(a, b, c) || Nand(a, b, c)) && !(Imp(a, b, c) != Left(a, b, c))
+}
+
+func Constant() bool {
+	return false
+}
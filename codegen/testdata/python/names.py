@@ -0,0 +1,9 @@
+# Generated by ac gen. Do not edit.
+
+
+def end(Big: bool, _x: bool) -> bool:
+    return Big and _x
+
+
+def when(Big: bool, _x: bool) -> bool:
+    return end(Big, _x) or not Big
@@ -0,0 +1,73 @@
+# Generated by ac gen. Do not edit.
+
+
+def and_(a: bool, b: bool, c: bool) -> bool:
+    return a and b and c
+
+
+def nand_(a: bool, b: bool, c: bool) -> bool:
+    return not (a and not (b and c))
+
+
+def or_(a: bool, b: bool, c: bool) -> bool:
+    return a or b or c
+
+
+def nor_(a: bool, b: bool, c: bool) -> bool:
+    return not (a or not (b or c))
+
+
+def xor_(a: bool, b: bool, c: bool) -> bool:
+    return a != (b != c)
+
+
+def equiv(a: bool, b: bool, c: bool) -> bool:
+    return a == (b == (c == a))
+
+
+def imp(a: bool, b: bool, c: bool) -> bool:
+    return not a or not b or c
+
+
+def imp_by(a: bool, b: bool, c: bool) -> bool:
+    return a or not (b or not c)
+
+
+def inh(a: bool, b: bool, c: bool) -> bool:
+    return a and not (b and not c)
+
+
+def inh_by(a: bool, b: bool, c: bool) -> bool:
+    return not a and not b and c
+
+
+def left_(a: bool, b: bool, c: bool) -> bool:
+    return a
+
+
+def right_(a: bool, b: bool, c: bool) -> bool:
+    return c
+
+
+def not_left(a: bool, b: bool, c: bool) -> bool:
+    return not a
+
+
+def not_right(a: bool, b: bool, c: bool) -> bool:
+    return c
+
+
+def unary(a: bool, b: bool, c: bool) -> bool:
+    return a and (False or True and a)
+
+
+def negated(a: bool, b: bool, c: bool) -> bool:
+    return (not a) != ((not b) == (not c))
+
+
+def nested(a: bool, b: bool, c: bool) -> bool:
+    return (and_(a, b, c) or nand_(a, b, c)) and not imp(a, b, c) != left_(a, b, c)
+
+
+def constant() -> bool:
+    return False
@@ -0,0 +1,9 @@
+// Generated by ac gen. Do not edit.
+
+export function end(Big: boolean, _x: boolean): boolean {
+  return Big && _x;
+}
+
+export function when(Big: boolean, _x: boolean): boolean {
+  return end(Big, _x) || !Big;
+}
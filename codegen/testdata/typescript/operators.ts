@@ -0,0 +1,73 @@
+// Generated by ac gen. Do not edit.
+
+export function and_(a: boolean, b: boolean, c: boolean): boolean {
+  return a && b && c;
+}
+
+export function nand_(a: boolean, b: boolean, c: boolean): boolean {
+  return !(a && !(b && c));
+}
+
+export function or_(a: boolean, b: boolean, c: boolean): boolean {
+  return a || b || c;
+}
+
+export function nor_(a: boolean, b: boolean, c: boolean): boolean {
+  return !(a || !(b || c));
+}
+
+export function xor_(a: boolean, b: boolean, c: boolean): boolean {
+  return a !== (b !== c);
+}
+
+export function equiv(a: boolean, b: boolean, c: boolean): boolean {
+  return a === (b === (c === a));
+}
+
+export function imp(a: boolean, b: boolean, c: boolean): boolean {
+  return !a || !b || c;
+}
+
+export function imp_by(a: boolean, b: boolean, c: boolean): boolean {
+  return a || !(b || !c);
+}
+
+export function inh(a: boolean, b: boolean, c: boolean): boolean {
+  return a && !(b && !c);
+}
+
+export function inh_by(a: boolean, b: boolean, c: boolean): boolean {
+  return !a && !b && c;
+}
+
+export function left_(a: boolean, b: boolean, c: boolean): boolean {
+  return a;
+}
+
+export function right_(a: boolean, b: boolean, c: boolean): boolean {
+  return c;
+}
+
+export function not_left(a: boolean, b: boolean, c: boolean): boolean {
+  return !a;
+}
+
+export function not_right(a: boolean, b: boolean, c: boolean): boolean {
+  return c;
+}
+
+export function unary(a: boolean, b: boolean, c: boolean): boolean {
+  return a && (false || true && a);
+}
+
+export function negated(a: boolean, b: boolean, c: boolean): boolean {
+  return !a !== (!b === !c);
+}
+
+export function nested(a: boolean, b: boolean, c: boolean): boolean {
+  return (and_(a, b, c) || nand_(a, b, c)) && !(imp(a, b, c) !== left_(a, b, c));
+}
+
+export function constant(): boolean {
+  return false;
+}
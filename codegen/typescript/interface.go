@@ -0,0 +1,59 @@
+package typescript
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"acornlang.dev/lang/codegen/program"
+	"acornlang.dev/lang/codegen/syntax"
+)
+
+// TYPESCRIPT compares with === and !==, which never coerce.
+var TYPESCRIPT = syntax.Language{
+	Ops:    map[syntax.Op]string{syntax.Not: "!", syntax.And: "&&", syntax.Or: "||", syntax.Eq: "===", syntax.Ne: "!=="},
+	Levels: map[syntax.Op]int{syntax.Not: 4, syntax.Eq: 3, syntax.Ne: 3, syntax.And: 2, syntax.Or: 1},
+	True:   "true",
+	False:  "false",
+	Reserved: map[string]bool{
+		"arguments": true, "await": true, "boolean": true, "break": true,
+		"case": true, "catch": true, "class": true, "const": true,
+		"continue": true, "debugger": true, "default": true, "delete": true,
+		"do": true, "else": true, "enum": true, "eval": true, "export": true,
+		"extends": true, "false": true, "finally": true, "for": true,
+		"function": true, "if": true, "implements": true, "import": true,
+		"in": true, "instanceof": true, "interface": true, "let": true,
+		"new": true, "null": true, "package": true, "private": true,
+		"protected": true, "public": true, "return": true, "static": true,
+		"super": true, "switch": true, "this": true, "throw": true,
+		"true": true, "try": true, "typeof": true, "undefined": true,
+		"var": true, "void": true, "while": true, "with": true, "yield": true,
+	},
+	Ident:  regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`),
+	Prefix: "v_",
+}
+
+// Generate emits a TypeScript module exporting one typed function per
+// definition, documented by the comment above the definition if any.
+func Generate(p *program.Program) string {
+	pr := syntax.NewPrinter(&TYPESCRIPT, p)
+	var sb strings.Builder
+	sb.WriteString("// Generated by ac gen. Do not edit.\n")
+	for _, fn := range p.Functions {
+		body, _ := pr.Body(fn)
+		params := []string{}
+		for _, param := range fn.Params {
+			params = append(params, pr.Var(param)+": boolean")
+		}
+		sb.WriteString("\n")
+		if fn.Doc != "" {
+			sb.WriteString("/**\n")
+			for _, line := range strings.Split(fn.Doc, "\n") {
+				sb.WriteString(strings.TrimRight(" * "+line, " ") + "\n")
+			}
+			sb.WriteString(" */\n")
+		}
+		fmt.Fprintf(&sb, "export function %s(%s): boolean {\n  return %s;\n}\n", pr.Names.Name(fn.Name), strings.Join(params, ", "), body)
+	}
+	return sb.String()
+}
@@ -0,0 +1,14 @@
+package typescript
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/codegen/internal/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNames(t *testing.T) {
+	source := Generate(testutil.Program(t, "TypeScript", "m", "let typeof := await and undefined\nlet k := not not False"))
+	assert.Contains(t, source, "export function typeof_(await_: boolean, undefined_: boolean): boolean {\n  return await_ && undefined_;\n}")
+	assert.Contains(t, source, "export function k(): boolean {\n  return false;\n}")
+}
@@ -0,0 +1,110 @@
+package verilog
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"acornlang.dev/lang/codegen/circuit"
+)
+
+var reserved = map[string]bool{
+	"always": true, "assign": true, "begin": true, "buf": true, "case": true,
+	"default": true, "else": true, "end": true, "endcase": true,
+	"endfunction": true, "endmodule": true, "for": true, "function": true,
+	"if": true, "initial": true, "inout": true, "input": true, "integer": true,
+	"logic": true, "module": true, "negedge": true, "output": true,
+	"parameter": true, "posedge": true, "reg": true, "signed": true,
+	"supply0": true, "supply1": true, "tri": true, "wire": true,
+}
+
+var invalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+type namer struct {
+	names map[string]string
+	taken map[string]bool
+}
+
+func newNamer() *namer {
+	return &namer{names: map[string]string{}, taken: map[string]bool{}}
+}
+
+func (n *namer) name(ident string) string {
+	if name, ok := n.names[ident]; ok {
+		return name
+	}
+	base := invalidChars.ReplaceAllString(ident, "_")
+	if base == "" || base[0] >= '0' && base[0] <= '9' {
+		base = "m_" + base
+	}
+	if reserved[base] {
+		base += "_"
+	}
+	name := base
+	for idx := 1; n.taken[name]; idx++ {
+		name = fmt.Sprintf("%s_%d", base, idx)
+	}
+	n.names[ident] = name
+	n.taken[name] = true
+	return name
+}
+
+func gateExpr(gate circuit.Gate, in []string) string {
+	switch gate.Kind {
+	case circuit.ConstFalse:
+		return "1'b0"
+	case circuit.ConstTrue:
+		return "1'b1"
+	case circuit.Not:
+		return "~" + in[0]
+	case circuit.And:
+		return in[0] + " & " + in[1]
+	case circuit.Nand:
+		return "~(" + in[0] + " & " + in[1] + ")"
+	case circuit.Or:
+		return in[0] + " | " + in[1]
+	case circuit.Nor:
+		return "~(" + in[0] + " | " + in[1] + ")"
+	case circuit.Xor:
+		return in[0] + " ^ " + in[1]
+	case circuit.Xnor:
+		return "~(" + in[0] + " ^ " + in[1] + ")"
+	default:
+		panic(fmt.Sprintf("unknown gate kind %d", gate.Kind))
+	}
+}
+
+// Generate emits c as a synthesizable Verilog-2001 module.
+func Generate(c *circuit.Circuit) string {
+	n := newNamer()
+	moduleName := n.name(c.Name)
+	ports := []string{}
+	for _, input := range c.Inputs {
+		ports = append(ports, "    input  wire "+n.name(input))
+	}
+	for _, output := range c.Outputs {
+		ports = append(ports, "    output wire "+n.name(output.Name))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("// Generated by ac gen. Do not edit.\n")
+	fmt.Fprintf(&sb, "module %s (\n%s\n);\n", moduleName, strings.Join(ports, ",\n"))
+	for _, gate := range c.Gates {
+		fmt.Fprintf(&sb, "    wire %s;\n", n.name(gate.Out))
+	}
+	if len(c.Gates) > 0 {
+		sb.WriteString("\n")
+	}
+	for _, gate := range c.Gates {
+		in := make([]string, len(gate.In))
+		for idx, signal := range gate.In {
+			in[idx] = n.name(signal)
+		}
+		fmt.Fprintf(&sb, "    assign %s = %s;\n", n.name(gate.Out), gateExpr(gate, in))
+	}
+	for _, output := range c.Outputs {
+		fmt.Fprintf(&sb, "    assign %s = %s;\n", n.name(output.Name), n.name(output.Signal))
+	}
+	sb.WriteString("endmodule\n")
+	return sb.String()
+}
@@ -0,0 +1,93 @@
+package verilog
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/codegen/circuit"
+	"acornlang.dev/lang/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateFromString(t *testing.T, name string, input string) string {
+	file, err := parser.FileParser.ParseString("", input)
+	assert.NoError(t, err)
+	c, err := circuit.FromFile(name, file)
+	assert.NoError(t, err)
+	return Generate(c)
+}
+
+func TestHalfAdder(t *testing.T) {
+	actual := generateFromString(t, "half_adder", "let sum := a xor b\nlet carry := a and b")
+	expected := `// Generated by ac gen. Do not edit.
+module half_adder (
+    input  wire a,
+    input  wire b,
+    output wire sum,
+    output wire carry
+);
+    wire w0;
+    wire w1;
+
+    assign w0 = a ^ b;
+    assign w1 = a & b;
+    assign sum = w0;
+    assign carry = w1;
+endmodule
+`
+	assert.Equal(t, expected, actual)
+}
+
+func TestEveryGateKind(t *testing.T) {
+	actual := generateFromString(
+		t,
+		"gates",
+		"let out := (a nand b) nor (a xnor b) or nullify a and truify b => c",
+	)
+	expected := `// Generated by ac gen. Do not edit.
+module gates (
+    input  wire a,
+    input  wire b,
+    input  wire c,
+    output wire out
+);
+    wire w0;
+    wire w1;
+    wire w2;
+    wire w3;
+    wire w4;
+    wire w5;
+    wire w6;
+    wire w7;
+    wire w8;
+
+    assign w0 = ~(a & b);
+    assign w1 = ~(a ^ b);
+    assign w2 = 1'b0;
+    assign w3 = 1'b1;
+    assign w4 = ~w3;
+    assign w5 = w4 | c;
+    assign w6 = w2 & w5;
+    assign w7 = w1 | w6;
+    assign w8 = ~(w0 | w7);
+    assign out = w8;
+endmodule
+`
+	assert.Equal(t, expected, actual)
+}
+
+func TestNamesAreMangled(t *testing.T) {
+	actual := generateFromString(t, "2-bit", "let wire := input and wire_")
+	expected := `// Generated by ac gen. Do not edit.
+module m_2_bit (
+    input  wire input_,
+    input  wire wire_,
+    output wire wire__1
+);
+    wire w0;
+
+    assign w0 = input_ & wire_;
+    assign wire__1 = w0;
+endmodule
+`
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,127 @@
+package vhdl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"acornlang.dev/lang/codegen/circuit"
+)
+
+var reserved = map[string]bool{
+	"abs": true, "all": true, "and": true, "architecture": true, "array": true,
+	"begin": true, "block": true, "body": true, "buffer": true, "bus": true,
+	"case": true, "component": true, "constant": true, "else": true,
+	"elsif": true, "end": true, "entity": true, "exit": true, "file": true,
+	"for": true, "function": true, "generate": true, "generic": true,
+	"if": true, "in": true, "inout": true, "is": true, "library": true,
+	"loop": true, "map": true, "mod": true, "nand": true, "new": true,
+	"next": true, "nor": true, "not": true, "null": true, "of": true,
+	"on": true, "open": true, "or": true, "others": true, "out": true,
+	"package": true, "port": true, "process": true, "range": true,
+	"record": true, "register": true, "rem": true, "report": true,
+	"return": true, "select": true, "signal": true, "subtype": true,
+	"then": true, "to": true, "type": true, "until": true, "use": true,
+	"variable": true, "wait": true, "when": true, "while": true, "with": true,
+	"xnor": true, "xor": true,
+}
+
+var (
+	invalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	underscores  = regexp.MustCompile(`__+`)
+)
+
+// namer maps identifiers onto VHDL's stricter rules: no leading, trailing
+// or doubled underscores, and no two names that differ only in case.
+type namer struct {
+	names map[string]string
+	taken map[string]bool
+}
+
+func newNamer() *namer {
+	return &namer{names: map[string]string{}, taken: map[string]bool{}}
+}
+
+func (n *namer) name(ident string) string {
+	if name, ok := n.names[ident]; ok {
+		return name
+	}
+	base := invalidChars.ReplaceAllString(ident, "_")
+	base = underscores.ReplaceAllString(base, "_")
+	base = strings.Trim(base, "_")
+	if base == "" || base[0] >= '0' && base[0] <= '9' {
+		base = "s_" + base
+	}
+	if reserved[strings.ToLower(base)] {
+		base += "_s"
+	}
+	name := base
+	for idx := 1; n.taken[strings.ToLower(name)]; idx++ {
+		name = fmt.Sprintf("%s_%d", base, idx)
+	}
+	n.names[ident] = name
+	n.taken[strings.ToLower(name)] = true
+	return name
+}
+
+func gateExpr(gate circuit.Gate, in []string) string {
+	switch gate.Kind {
+	case circuit.ConstFalse:
+		return "'0'"
+	case circuit.ConstTrue:
+		return "'1'"
+	case circuit.Not:
+		return "not " + in[0]
+	case circuit.And:
+		return in[0] + " and " + in[1]
+	case circuit.Nand:
+		return in[0] + " nand " + in[1]
+	case circuit.Or:
+		return in[0] + " or " + in[1]
+	case circuit.Nor:
+		return in[0] + " nor " + in[1]
+	case circuit.Xor:
+		return in[0] + " xor " + in[1]
+	case circuit.Xnor:
+		return in[0] + " xnor " + in[1]
+	default:
+		panic(fmt.Sprintf("unknown gate kind %d", gate.Kind))
+	}
+}
+
+// Generate emits c as a synthesizable VHDL-93 entity and architecture.
+func Generate(c *circuit.Circuit) string {
+	n := newNamer()
+	entityName := n.name(c.Name)
+	ports := []string{}
+	for _, input := range c.Inputs {
+		ports = append(ports, "        "+n.name(input)+" : in  std_logic")
+	}
+	for _, output := range c.Outputs {
+		ports = append(ports, "        "+n.name(output.Name)+" : out std_logic")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("-- Generated by ac gen. Do not edit.\n")
+	sb.WriteString("library ieee;\nuse ieee.std_logic_1164.all;\n\n")
+	fmt.Fprintf(&sb, "entity %s is\n", entityName)
+	fmt.Fprintf(&sb, "    port (\n%s\n    );\n", strings.Join(ports, ";\n"))
+	fmt.Fprintf(&sb, "end entity %s;\n\n", entityName)
+	fmt.Fprintf(&sb, "architecture rtl of %s is\n", entityName)
+	for _, gate := range c.Gates {
+		fmt.Fprintf(&sb, "    signal %s : std_logic;\n", n.name(gate.Out))
+	}
+	sb.WriteString("begin\n")
+	for _, gate := range c.Gates {
+		in := make([]string, len(gate.In))
+		for idx, signal := range gate.In {
+			in[idx] = n.name(signal)
+		}
+		fmt.Fprintf(&sb, "    %s <= %s;\n", n.name(gate.Out), gateExpr(gate, in))
+	}
+	for _, output := range c.Outputs {
+		fmt.Fprintf(&sb, "    %s <= %s;\n", n.name(output.Name), n.name(output.Signal))
+	}
+	fmt.Fprintf(&sb, "end architecture rtl;\n")
+	return sb.String()
+}
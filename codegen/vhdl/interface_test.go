@@ -0,0 +1,114 @@
+package vhdl
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/codegen/circuit"
+	"acornlang.dev/lang/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateFromString(t *testing.T, name string, input string) string {
+	file, err := parser.FileParser.ParseString("", input)
+	assert.NoError(t, err)
+	c, err := circuit.FromFile(name, file)
+	assert.NoError(t, err)
+	return Generate(c)
+}
+
+func TestHalfAdder(t *testing.T) {
+	actual := generateFromString(t, "half_adder", "let sum := a xor b\nlet carry := a and b")
+	expected := `-- Generated by ac gen. Do not edit.
+library ieee;
+use ieee.std_logic_1164.all;
+
+entity half_adder is
+    port (
+        a : in  std_logic;
+        b : in  std_logic;
+        sum : out std_logic;
+        carry : out std_logic
+    );
+end entity half_adder;
+
+architecture rtl of half_adder is
+    signal w0 : std_logic;
+    signal w1 : std_logic;
+begin
+    w0 <= a xor b;
+    w1 <= a and b;
+    sum <= w0;
+    carry <= w1;
+end architecture rtl;
+`
+	assert.Equal(t, expected, actual)
+}
+
+func TestEveryGateKind(t *testing.T) {
+	actual := generateFromString(
+		t,
+		"gates",
+		"let y := (a nand b) nor (a xnor b) or nullify a and not truify b",
+	)
+	expected := `-- Generated by ac gen. Do not edit.
+library ieee;
+use ieee.std_logic_1164.all;
+
+entity gates is
+    port (
+        a : in  std_logic;
+        b : in  std_logic;
+        y : out std_logic
+    );
+end entity gates;
+
+architecture rtl of gates is
+    signal w0 : std_logic;
+    signal w1 : std_logic;
+    signal w2 : std_logic;
+    signal w3 : std_logic;
+    signal w4 : std_logic;
+    signal w5 : std_logic;
+    signal w6 : std_logic;
+    signal w7 : std_logic;
+begin
+    w0 <= a nand b;
+    w1 <= a xnor b;
+    w2 <= '0';
+    w3 <= '1';
+    w4 <= not w3;
+    w5 <= w2 and w4;
+    w6 <= w1 or w5;
+    w7 <= w0 nor w6;
+    y <= w7;
+end architecture rtl;
+`
+	assert.Equal(t, expected, actual)
+}
+
+func TestNamesAreMangled(t *testing.T) {
+	actual := generateFromString(t, "_x", "let signal := A and a__b_\nlet a := _b")
+	expected := `-- Generated by ac gen. Do not edit.
+library ieee;
+use ieee.std_logic_1164.all;
+
+entity x is
+    port (
+        A : in  std_logic;
+        a_b : in  std_logic;
+        b : in  std_logic;
+        signal_s : out std_logic;
+        a_1 : out std_logic
+    );
+end entity x;
+
+architecture rtl of x is
+    signal w0 : std_logic;
+begin
+    w0 <= A and a_b;
+    signal_s <= w0;
+    a_1 <= b;
+end architecture rtl;
+`
+	assert.Equal(t, expected, actual)
+}
@@ -2,27 +2,54 @@ module acornlang.dev/lang
 
 go 1.24.2
 
+replace acornlang.dev/lang/analysis => ./analysis
+
+replace acornlang.dev/lang/codegen => ./codegen
+
 replace acornlang.dev/lang/lexer => ./lexer
 
 replace acornlang.dev/lang/parser => ./parser
 
+replace acornlang.dev/lang/parser/arith => ./parser/arith
+
+replace acornlang.dev/lang/parser/bits => ./parser/bits
+
 replace acornlang.dev/lang/parser/boolean => ./parser/boolean
 
+replace acornlang.dev/lang/parser/data => ./parser/data
+
+replace acornlang.dev/lang/parser/sets => ./parser/sets
+
+replace acornlang.dev/lang/parser/strings => ./parser/strings
+
+replace acornlang.dev/lang/render => ./render
+
 replace acornlang.dev/lang/repl => ./repl
 
 replace acornlang.dev/lang/types => ./types
 
+replace acornlang.dev/lang/wasm => ./wasm
+
 require (
+	acornlang.dev/lang/analysis v0.0.0-00010101000000-000000000000
+	acornlang.dev/lang/codegen v0.0.0-00010101000000-000000000000
+	acornlang.dev/lang/parser v0.0.0-00010101000000-000000000000
+	acornlang.dev/lang/parser/arith v0.0.0-00010101000000-000000000000
+	acornlang.dev/lang/parser/bits v0.0.0-00010101000000-000000000000
 	acornlang.dev/lang/parser/boolean v0.0.0-00010101000000-000000000000
+	acornlang.dev/lang/parser/data v0.0.0-00010101000000-000000000000
+	acornlang.dev/lang/parser/sets v0.0.0-00010101000000-000000000000
+	acornlang.dev/lang/parser/strings v0.0.0-00010101000000-000000000000
+	acornlang.dev/lang/render v0.0.0-00010101000000-000000000000
 	acornlang.dev/lang/repl v0.0.0-00010101000000-000000000000
+	acornlang.dev/lang/wasm v0.0.0-00010101000000-000000000000
+	github.com/alecthomas/participle/v2 v2.1.4
 	github.com/gdamore/tcell/v2 v2.8.1
 )
 
 require (
 	acornlang.dev/lang/lexer v0.0.0-00010101000000-000000000000 // indirect
-	acornlang.dev/lang/parser v0.0.0-00010101000000-000000000000 // indirect
 	acornlang.dev/lang/types v0.0.0-00010101000000-000000000000 // indirect
-	github.com/alecthomas/participle/v2 v2.1.4 // indirect
 	github.com/gdamore/encoding v1.0.1 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-runewidth v0.0.16 // indirect
@@ -1,6 +1,7 @@
 package lexer
 
 import (
+	"io"
 	"regexp"
 	"strings"
 
@@ -14,6 +15,14 @@ const (
 	LeftBoundary
 	RightBoundary
 	BothBoundaries
+	// LetterLast is for a symbol that ends in a letter, such as <s. It
+	// must end a word, so x <sy lexes as x < sy. A rule cannot see the
+	// text behind it, so BooleanLexer splits one that touches a word on
+	// its left: x<s lexes as x < s.
+	LetterLast
+	// LetterFirst is for a symbol that starts with a letter, such as s>.
+	// It must not touch a word on its right, so s>3 lexes as s > 3.
+	LetterFirst
 )
 
 type EscapedAndWBString struct {
@@ -37,6 +46,10 @@ func (wbs EscapedAndWBString) String() string {
 		return value + `\b`
 	case BothBoundaries:
 		return `\b` + value + `\b`
+	case LetterLast:
+		return value + `\b`
+	case LetterFirst:
+		return `\b` + value + `\B`
 	default:
 		return value
 	}
@@ -52,6 +65,31 @@ var (
 	FALSE_WB EscapedAndWBString = NewEscapedAndWBString(FALSE, BothBoundaries)
 )
 
+// A lowercase prefix of a literal, or a literal with two neighbouring
+// letters swapped, reads as a typo rather than a name. So does a run of
+// unary operators written without spaces, such as notnot. Both lex as
+// Misspelling, which no grammar accepts, so they fail where they stand
+// instead of parsing as variables.
+var MISSPELLING string = `\b(` + strings.Join(append(
+	misspellingsOf(strings.ToLower(TRUE), strings.ToLower(FALSE)),
+	"("+strings.Join([]string{NOT_TEXT, NULLIFY_TEXT, TRUIFY_TEXT, ID_TEXT}, "|")+"){2,}",
+), "|") + `)\b`
+
+func misspellingsOf(words ...string) []string {
+	acc := []string{}
+	for _, word := range words {
+		for i := 1; i <= len(word); i++ {
+			acc = append(acc, regexp.QuoteMeta(word[:i]))
+		}
+		for i := 0; i+1 < len(word); i++ {
+			swapped := []byte(word)
+			swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+			acc = append(acc, regexp.QuoteMeta(string(swapped)))
+		}
+	}
+	return acc
+}
+
 var (
 	NOT_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(
 		NOT_TEXT,
@@ -114,6 +152,55 @@ const (
 	NOT_RIGHT_SYMB string = "/>"
 )
 
+// Temporal operators, only meaningful when evaluated over a trace. The
+// symbols are those of the literature but for the letters X, G, F, U and
+// R, which would take those names from variables.
+const (
+	NEXT_TEXT     string = "next"
+	NEXT_SYMB     string = "○"
+	GLOBALLY_TEXT string = "globally"
+	GLOBALLY_SYMB string = "□"
+	FINALLY_TEXT  string = "finally"
+	FINALLY_SYMB  string = "◇"
+
+	UNTIL_TEXT   string = "until"
+	UNTIL_SYMB   string = "𝒰"
+	RELEASE_TEXT string = "release"
+	RELEASE_SYMB string = "ℛ"
+)
+
+var (
+	NEXT_TEXT_WB     EscapedAndWBString = NewEscapedAndWBString(NEXT_TEXT, BothBoundaries)
+	NEXT_SYMB_WB     EscapedAndWBString = NewEscapedAndWBString(NEXT_SYMB, NoBoundary)
+	GLOBALLY_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(
+		GLOBALLY_TEXT,
+		BothBoundaries,
+	)
+	GLOBALLY_SYMB_WB EscapedAndWBString = NewEscapedAndWBString(
+		GLOBALLY_SYMB,
+		NoBoundary,
+	)
+	FINALLY_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(
+		FINALLY_TEXT,
+		BothBoundaries,
+	)
+	FINALLY_SYMB_WB EscapedAndWBString = NewEscapedAndWBString(
+		FINALLY_SYMB,
+		NoBoundary,
+	)
+
+	UNTIL_TEXT_WB   EscapedAndWBString = NewEscapedAndWBString(UNTIL_TEXT, BothBoundaries)
+	UNTIL_SYMB_WB   EscapedAndWBString = NewEscapedAndWBString(UNTIL_SYMB, NoBoundary)
+	RELEASE_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(
+		RELEASE_TEXT,
+		BothBoundaries,
+	)
+	RELEASE_SYMB_WB EscapedAndWBString = NewEscapedAndWBString(
+		RELEASE_SYMB,
+		NoBoundary,
+	)
+)
+
 var (
 	AND_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(
 		AND_TEXT,
@@ -192,7 +279,7 @@ var (
 	)
 	LEFT_SYMB_WB EscapedAndWBString = NewEscapedAndWBString(
 		LEFT_SYMB,
-		NoBoundary,
+		LetterLast,
 	)
 	RIGHT_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(
 		RIGHT_TEXT,
@@ -200,7 +287,7 @@ var (
 	)
 	RIGHT_SYMB_WB EscapedAndWBString = NewEscapedAndWBString(
 		RIGHT_SYMB,
-		NoBoundary,
+		LetterFirst,
 	)
 
 	NOT_LEFT_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(
@@ -221,6 +308,87 @@ var (
 	)
 )
 
+// String operators
+const (
+	CONCAT_SYMB     string = "++"
+	EQUALS_SYMB     string = "=="
+	NOT_EQUALS_SYMB string = "!="
+)
+
+// Integer operators. `<=` is lexed as `is implied by` and `==`, `!=` as
+// string comparisons; the grammar accepts them between integers too.
+const (
+	PLUS_SYMB  string = "+"
+	MINUS_SYMB string = "-"
+	TIMES_SYMB string = "*"
+	DIV_SYMB   string = "/"
+	MOD_TEXT   string = "mod"
+
+	LESS_SYMB          string = "<"
+	LESS_EQUAL_SYMB    string = "=<"
+	GREATER_SYMB       string = ">"
+	GREATER_EQUAL_SYMB string = ">="
+
+	RANGE_SYMB string = ".."
+)
+
+var MOD_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(MOD_TEXT, BothBoundaries)
+
+// Bit-vector operators. The connectives apply bit by bit, so only shifts,
+// rotations and popcount are new.
+const (
+	BINARY_PREFIX string = "0b"
+	HEX_PREFIX    string = "0x"
+
+	SHIFT_LEFT_SYMB   string = "<<"
+	SHIFT_RIGHT_SYMB  string = ">>"
+	ROTATE_LEFT_TEXT  string = "rotl"
+	ROTATE_RIGHT_TEXT string = "rotr"
+	POPCOUNT_TEXT     string = "popcount"
+)
+
+var (
+	ROTATE_LEFT_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(
+		ROTATE_LEFT_TEXT,
+		BothBoundaries,
+	)
+	ROTATE_RIGHT_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(
+		ROTATE_RIGHT_TEXT,
+		BothBoundaries,
+	)
+	POPCOUNT_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(
+		POPCOUNT_TEXT,
+		BothBoundaries,
+	)
+)
+
+// Set operators. `union` and `intersect` read as `or` and `and`, and every
+// connective applies element by element.
+const (
+	IN_TEXT        string = "in"
+	UNION_TEXT     string = "union"
+	INTERSECT_TEXT string = "intersect"
+	SUBSET_TEXT    string = "subset"
+	SUCH_THAT_SYMB string = "|"
+)
+
+var (
+	IN_TEXT_WB        EscapedAndWBString = NewEscapedAndWBString(IN_TEXT, BothBoundaries)
+	UNION_TEXT_WB     EscapedAndWBString = NewEscapedAndWBString(UNION_TEXT, BothBoundaries)
+	INTERSECT_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(
+		INTERSECT_TEXT,
+		BothBoundaries,
+	)
+	SUBSET_TEXT_WB EscapedAndWBString = NewEscapedAndWBString(SUBSET_TEXT, BothBoundaries)
+)
+
+// Pattern matching: `match (p, q) with (True, _) -> p | _ -> q`.
+const (
+	MATCH_TEXT    string = "match"
+	WITH_TEXT     string = "with"
+	WILDCARD_SYMB string = "_"
+)
+
 const (
 	TERMINATOR_DBL_SEMICOLON    = ";;"
 	TERMINATOR_NEWLINE          = "\n"
@@ -266,10 +434,32 @@ var tokenDefinitions = []TokenDef{
 		Name:  "Whitespace",
 		Regex: `[ \t]+`,
 	},
+	// A comment runs from # to the end of the line; the line above a
+	// definition documents it.
+	{
+		Name:  "Comment",
+		Regex: `#[^\r\n]*`,
+	},
+	{
+		Name:  "StringLit",
+		Regex: `"(\\.|[^"\\\n])*"`,
+	},
 	{
 		Name:   "DoubleSemicolon",
 		String: ";;",
 	},
+	{
+		Name:   "Assign",
+		String: ":=",
+	},
+	{
+		Name:   "Colon",
+		String: ":",
+	},
+	{
+		Name:   "Arrow",
+		String: "->",
+	},
 	{
 		Name:   "SingleSemicolon",
 		String: ";",
@@ -282,6 +472,25 @@ var tokenDefinitions = []TokenDef{
 		Name:   "RParen",
 		String: "\\)",
 	},
+	{
+		Name:   "LBracket",
+		String: "\\[",
+	},
+	{
+		Name:   "RBracket",
+		String: "\\]",
+	},
+	{
+		Name:   "Concat",
+		String: regexp.QuoteMeta(CONCAT_SYMB),
+	},
+	{
+		Name: "Compare",
+		OneOf: []string{
+			regexp.QuoteMeta(EQUALS_SYMB),
+			regexp.QuoteMeta(NOT_EQUALS_SYMB),
+		},
+	},
 	{
 		Name: "BinaryOpString",
 		OneOf: []string{
@@ -312,14 +521,19 @@ var tokenDefinitions = []TokenDef{
 			regexp.QuoteMeta(IMPLIED_BY_SYMB),
 
 			LEFT_TEXT_WB.String(),
-			regexp.QuoteMeta(LEFT_SYMB),
+			LEFT_SYMB_WB.String(),
 			RIGHT_TEXT_WB.String(),
-			regexp.QuoteMeta(RIGHT_SYMB),
+			RIGHT_SYMB_WB.String(),
 
 			NOT_LEFT_TEXT_WB.String(),
 			regexp.QuoteMeta(NOT_LEFT_SYMB),
 			NOT_RIGHT_TEXT_WB.String(),
 			regexp.QuoteMeta(NOT_RIGHT_SYMB),
+
+			UNTIL_TEXT_WB.String(),
+			UNTIL_SYMB_WB.String(),
+			RELEASE_TEXT_WB.String(),
+			RELEASE_SYMB_WB.String(),
 		},
 	},
 	{
@@ -330,6 +544,13 @@ var tokenDefinitions = []TokenDef{
 			NULLIFY_TEXT_WB.String(),
 			TRUIFY_TEXT_WB.String(),
 			ID_TEXT_WB.String(),
+
+			NEXT_TEXT_WB.String(),
+			NEXT_SYMB_WB.String(),
+			GLOBALLY_TEXT_WB.String(),
+			GLOBALLY_SYMB_WB.String(),
+			FINALLY_TEXT_WB.String(),
+			FINALLY_SYMB_WB.String(),
 		},
 	},
 	{
@@ -339,10 +560,93 @@ var tokenDefinitions = []TokenDef{
 			FALSE_WB.String(),
 		},
 	},
+	{
+		Name: "Shift",
+		OneOf: []string{
+			regexp.QuoteMeta(SHIFT_LEFT_SYMB),
+			regexp.QuoteMeta(SHIFT_RIGHT_SYMB),
+			ROTATE_LEFT_TEXT_WB.String(),
+			ROTATE_RIGHT_TEXT_WB.String(),
+		},
+	},
+	{
+		Name:   "Popcount",
+		String: POPCOUNT_TEXT_WB.String(),
+	},
+	{
+		Name:   "LBrace",
+		String: "\\{",
+	},
+	{
+		Name:   "RBrace",
+		String: "\\}",
+	},
+	{
+		Name:   "Comma",
+		String: ",",
+	},
+	{
+		Name:   "Bar",
+		String: regexp.QuoteMeta(SUCH_THAT_SYMB),
+	},
+	{
+		Name:   "In",
+		String: IN_TEXT_WB.String(),
+	},
+	{
+		Name: "SetOp",
+		OneOf: []string{
+			UNION_TEXT_WB.String(),
+			INTERSECT_TEXT_WB.String(),
+		},
+	},
+	{
+		Name:   "Subset",
+		String: SUBSET_TEXT_WB.String(),
+	},
+	{
+		Name: "Relation",
+		OneOf: []string{
+			regexp.QuoteMeta(LESS_EQUAL_SYMB),
+			regexp.QuoteMeta(GREATER_EQUAL_SYMB),
+			regexp.QuoteMeta(LESS_SYMB),
+			regexp.QuoteMeta(GREATER_SYMB),
+		},
+	},
+	{
+		Name: "ArithOp",
+		OneOf: []string{
+			regexp.QuoteMeta(PLUS_SYMB),
+			regexp.QuoteMeta(MINUS_SYMB),
+			regexp.QuoteMeta(TIMES_SYMB),
+			regexp.QuoteMeta(DIV_SYMB),
+			MOD_TEXT_WB.String(),
+		},
+	},
+	{
+		Name:   "Range",
+		String: regexp.QuoteMeta(RANGE_SYMB),
+	},
+	{
+		Name:  "BitsString",
+		Regex: `\b(0b[01]+|0x[0-9a-fA-F]+)\b`,
+	},
+	{
+		Name:  "DecimalString",
+		Regex: `[0-9]+\.[0-9]+`,
+	},
+	{
+		Name:  "IntString",
+		Regex: `[0-9]+`,
+	},
 	{
 		Name:  "Newline",
 		Regex: `(\r)?\n`,
 	},
+	{
+		Name:  "Misspelling",
+		Regex: MISSPELLING,
+	},
 	{
 		Name:  "Ident",
 		Regex: `\b([a-zA-Z_][a-zA-Z0-9_]*)\b`,
@@ -351,4 +655,69 @@ var tokenDefinitions = []TokenDef{
 
 var simpleRules = BuildSimpleRules(tokenDefinitions)
 
-var BooleanLexer = participleLexer.MustSimple(simpleRules)
+var BooleanLexer = Definition{participleLexer.MustSimple(simpleRules)}
+
+// Definition is the simple lexer built from tokenDefinitions, with `<s`
+// split into `<` and `s` where it touches the word before it.
+type Definition struct {
+	*participleLexer.StatefulDefinition
+}
+
+func (d Definition) Lex(filename string, r io.Reader) (participleLexer.Lexer, error) {
+	inner, err := d.StatefulDefinition.Lex(filename, r)
+	return d.wrap(inner), err
+}
+
+func (d Definition) LexString(filename string, input string) (participleLexer.Lexer, error) {
+	inner, err := d.StatefulDefinition.LexString(filename, input)
+	return d.wrap(inner), err
+}
+
+func (d Definition) wrap(inner participleLexer.Lexer) participleLexer.Lexer {
+	if inner == nil {
+		return nil
+	}
+	symbols := d.Symbols()
+	return &splittingLexer{
+		inner:    inner,
+		words:    map[participleLexer.TokenType]bool{symbols["Ident"]: true, symbols["IntString"]: true},
+		binaryOp: symbols["BinaryOpString"],
+		relation: symbols["Relation"],
+		ident:    symbols["Ident"],
+	}
+}
+
+type splittingLexer struct {
+	inner    participleLexer.Lexer
+	words    map[participleLexer.TokenType]bool
+	binaryOp participleLexer.TokenType
+	relation participleLexer.TokenType
+	ident    participleLexer.TokenType
+	prev     participleLexer.Token
+	pending  []participleLexer.Token
+}
+
+func (l *splittingLexer) Next() (participleLexer.Token, error) {
+	if 0 < len(l.pending) {
+		tok := l.pending[0]
+		l.pending = l.pending[1:]
+		l.prev = tok
+		return tok, nil
+	}
+	tok, err := l.inner.Next()
+	if err != nil {
+		return tok, err
+	}
+	if tok.Type == l.binaryOp && tok.Value == LEFT_SYMB && l.words[l.prev.Type] {
+		name := tok
+		name.Type = l.ident
+		name.Value = LEFT_SYMB[len(LESS_SYMB):]
+		name.Pos.Offset += len(LESS_SYMB)
+		name.Pos.Column += len(LESS_SYMB)
+		l.pending = append(l.pending, name)
+		tok.Type = l.relation
+		tok.Value = LESS_SYMB
+	}
+	l.prev = tok
+	return tok, nil
+}
@@ -1 +1,49 @@
 package lexer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lex(t *testing.T, input string) []string {
+	t.Helper()
+	symbols := map[int]string{}
+	for name, tokenType := range BooleanLexer.Symbols() {
+		symbols[int(tokenType)] = name
+	}
+	lx, err := BooleanLexer.LexString("", input)
+	assert.NoError(t, err)
+	acc := []string{}
+	for {
+		tok, err := lx.Next()
+		assert.NoError(t, err, input)
+		if err != nil || tok.EOF() {
+			return acc
+		}
+		if symbols[int(tok.Type)] != "Whitespace" {
+			acc = append(acc, symbols[int(tok.Type)]+" "+tok.Value)
+		}
+	}
+}
+
+func TestLetterSymbols(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"p <s q", []string{"Ident p", "BinaryOpString <s", "Ident q"}},
+		{"p s> q", []string{"Ident p", "BinaryOpString s>", "Ident q"}},
+		{"x<s", []string{"Ident x", "Relation <", "Ident s"}},
+		{"3<s", []string{"IntString 3", "Relation <", "Ident s"}},
+		{"x <sy", []string{"Ident x", "Relation <", "Ident sy"}},
+		{"s>3", []string{"Ident s", "Relation >", "IntString 3"}},
+		{"(p)<s(q)", []string{"LParen (", "Ident p", "RParen )", "BinaryOpString <s", "LParen (", "Ident q", "RParen )"}},
+		{"x =< y", []string{"Ident x", "Relation =<", "Ident y"}},
+		{"x <= y", []string{"Ident x", "BinaryOpString <=", "Ident y"}},
+		{"x > 0.5", []string{"Ident x", "Relation >", "DecimalString 0.5"}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, lex(t, test.input), test.input)
+	}
+}
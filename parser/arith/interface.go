@@ -0,0 +1,279 @@
+package arith
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ast/arith"
+	"github.com/alecthomas/participle/v2"
+)
+
+// Regd. Parsing
+
+var ExprParser = participle.MustBuild[arith.Sum](
+	participle.Lexer(lexer.BooleanLexer),
+	participle.Elide("Whitespace", "Comment"),
+)
+
+// comparisonLine puts a comparison below the root: participle prefixes
+// a second position to the errors of a custom parser at the root.
+type comparisonLine struct {
+	Cmp *arith.Comparison `parser:"@@"`
+}
+
+var comparisonParser = participle.MustBuild[comparisonLine](
+	participle.Lexer(lexer.BooleanLexer),
+	participle.Elide("Whitespace", "Comment"),
+)
+
+// ParseComparison reads a lone comparison such as `x + 1 > y`.
+func ParseComparison(filename string, input string) (*arith.Comparison, error) {
+	line, err := comparisonParser.ParseString(filename, input)
+	if err != nil {
+		return nil, err
+	}
+	return line.Cmp, nil
+}
+
+// Regd. Evaluation
+
+type Env map[string]int64
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+// EvalComparison compares two integer expressions.
+func EvalComparison(expr *arith.Comparison, env Env) (types.Bool, error) {
+	if expr == nil {
+		return false, fmt.Errorf("invalid comparison 'nil'")
+	}
+	left, err := EvalSum(expr.Left, env)
+	if err != nil {
+		return false, err
+	}
+	right, err := EvalSum(expr.Right, env)
+	if err != nil {
+		return false, err
+	}
+	switch expr.Op {
+	case lexer.LESS_SYMB:
+		return left < right, nil
+	case lexer.LESS_EQUAL_SYMB:
+		return left <= right, nil
+	case lexer.GREATER_SYMB:
+		return left > right, nil
+	case lexer.GREATER_EQUAL_SYMB:
+		return left >= right, nil
+	case lexer.EQUALS_SYMB:
+		return left == right, nil
+	case lexer.NOT_EQUALS_SYMB:
+		return left != right, nil
+	default:
+		return false, errorAt(expr.Pos, "invalid comparison '%s'", expr.Op)
+	}
+}
+
+func EvalSum(expr *arith.Sum, env Env) (types.Int, error) {
+	if expr == nil {
+		return 0, fmt.Errorf("invalid integer expression 'nil'")
+	}
+	acc, err := evalTerm(expr.Head, env)
+	if err != nil {
+		return 0, err
+	}
+	for _, rest := range expr.Rest {
+		term, err := evalTerm(rest.Term, env)
+		if err != nil {
+			return 0, err
+		}
+		switch rest.Op {
+		case lexer.PLUS_SYMB:
+			acc, err = exact(rest.Pos, (*big.Int).Add, acc, term)
+		case lexer.MINUS_SYMB:
+			acc, err = exact(rest.Pos, (*big.Int).Sub, acc, term)
+		default:
+			return 0, errorAt(rest.Pos, "invalid integer operator '%s'", rest.Op)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return acc, nil
+}
+
+func evalTerm(expr *arith.Term, env Env) (types.Int, error) {
+	acc, err := evalFactor(expr.Head, env)
+	if err != nil {
+		return 0, err
+	}
+	for _, rest := range expr.Rest {
+		factor, err := evalFactor(rest.Factor, env)
+		if err != nil {
+			return 0, err
+		}
+		switch rest.Op {
+		case lexer.TIMES_SYMB:
+			acc, err = exact(rest.Pos, (*big.Int).Mul, acc, factor)
+			if err != nil {
+				return 0, err
+			}
+		case lexer.DIV_SYMB, lexer.MOD_TEXT:
+			if factor == 0 {
+				return 0, errorAt(rest.Pos, "division by zero")
+			}
+			if rest.Op == lexer.DIV_SYMB && acc == math.MinInt64 && factor == -1 {
+				return 0, errorAt(rest.Pos, "integer overflow")
+			}
+			quot, rem := floorDivMod(acc, factor)
+			acc = quot
+			if rest.Op == lexer.MOD_TEXT {
+				acc = rem
+			}
+		default:
+			return 0, errorAt(rest.Pos, "invalid integer operator '%s'", rest.Op)
+		}
+	}
+	return acc, nil
+}
+
+// exact applies op to a and b without wrapping around, and fails if the
+// result does not fit in an Int.
+func exact(pos types.Position, op func(z, x, y *big.Int) *big.Int, a types.Int, b types.Int) (types.Int, error) {
+	z := op(new(big.Int), big.NewInt(int64(a)), big.NewInt(int64(b)))
+	if !z.IsInt64() {
+		return 0, errorAt(pos, "integer overflow")
+	}
+	return types.Int(z.Int64()), nil
+}
+
+// floorDivMod rounds the quotient down, so the remainder takes the sign of
+// the divisor: -7 / 2 is -4 and -7 mod 2 is 1.
+func floorDivMod(a types.Int, b types.Int) (types.Int, types.Int) {
+	quot, rem := a/b, a%b
+	if rem != 0 && (rem < 0) != (b < 0) {
+		quot--
+		rem += b
+	}
+	return quot, rem
+}
+
+func evalFactor(expr *arith.Factor, env Env) (types.Int, error) {
+	var acc types.Int
+	switch {
+	case expr.Paren != nil:
+		val, err := EvalSum(expr.Paren.Expr, env)
+		if err != nil {
+			return 0, err
+		}
+		acc = val
+	case expr.Var != "":
+		val, ok := env[expr.Var]
+		if !ok {
+			return 0, errorAt(expr.Pos, "unbound variable '%s'", expr.Var)
+		}
+		acc = types.Int(val)
+	default:
+		// The sign is read with the digits, so the smallest Int is written
+		// as it is.
+		lit := expr.Int
+		if expr.Neg {
+			lit = lexer.MINUS_SYMB + lit
+		}
+		val, err := strconv.ParseInt(lit, 10, 64)
+		if err != nil {
+			return 0, errorAt(expr.Pos, "integer '%s' is too large", expr.Int)
+		}
+		return types.Int(val), nil
+	}
+	if expr.Neg {
+		if acc == math.MinInt64 {
+			return 0, errorAt(expr.Pos, "integer overflow")
+		}
+		acc = -acc
+	}
+	return acc, nil
+}
+
+// Regd. Variables
+
+// Vars lists the names a comparison reads, in order of first appearance.
+func Vars(expr *arith.Comparison) []string {
+	seen := map[string]bool{}
+	acc := []string{}
+	collectVars(expr.Left, seen, &acc)
+	collectVars(expr.Right, seen, &acc)
+	return acc
+}
+
+func SumVars(expr *arith.Sum) []string {
+	seen := map[string]bool{}
+	acc := []string{}
+	collectVars(expr, seen, &acc)
+	return acc
+}
+
+func collectVars(expr *arith.Sum, seen map[string]bool, acc *[]string) {
+	visitFactor := func(factor *arith.Factor) {
+		switch {
+		case factor.Paren != nil:
+			collectVars(factor.Paren.Expr, seen, acc)
+		case factor.Var != "" && !seen[factor.Var]:
+			seen[factor.Var] = true
+			*acc = append(*acc, factor.Var)
+		}
+	}
+	terms := []*arith.Term{expr.Head}
+	for _, rest := range expr.Rest {
+		terms = append(terms, rest.Term)
+	}
+	for _, term := range terms {
+		visitFactor(term.Head)
+		for _, rest := range term.Rest {
+			visitFactor(rest.Factor)
+		}
+	}
+}
+
+// Regd. Printing
+
+func Format(expr *arith.Comparison) string {
+	return FormatSum(expr.Left) + " " + expr.Op + " " + FormatSum(expr.Right)
+}
+
+func FormatSum(expr *arith.Sum) string {
+	var sb strings.Builder
+	formatTerm(&sb, expr.Head)
+	for _, rest := range expr.Rest {
+		sb.WriteString(" " + rest.Op + " ")
+		formatTerm(&sb, rest.Term)
+	}
+	return sb.String()
+}
+
+func formatTerm(sb *strings.Builder, expr *arith.Term) {
+	formatFactor(sb, expr.Head)
+	for _, rest := range expr.Rest {
+		sb.WriteString(" " + rest.Op + " ")
+		formatFactor(sb, rest.Factor)
+	}
+}
+
+func formatFactor(sb *strings.Builder, expr *arith.Factor) {
+	if expr.Neg {
+		sb.WriteString(lexer.MINUS_SYMB)
+	}
+	switch {
+	case expr.Paren != nil:
+		sb.WriteString("(" + FormatSum(expr.Paren.Expr) + ")")
+	case expr.Var != "":
+		sb.WriteString(expr.Var)
+	default:
+		sb.WriteString(expr.Int)
+	}
+}
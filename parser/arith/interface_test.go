@@ -0,0 +1,141 @@
+package arith
+
+import (
+	"math"
+	"testing"
+
+	"acornlang.dev/lang/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvalSum(t *testing.T) {
+	env := Env{"x": 7, "y": -2}
+	tests := []struct {
+		input    string
+		expected types.Int
+	}{
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"x / 2", 3},
+		{"-x / 2", -4},
+		{"-x mod 2", 1},
+		{"x mod y", -1},
+		{"x * y + 1", -13},
+		{"-(x - 10)", 3},
+	}
+	for _, test := range tests {
+		expr, err := ExprParser.ParseString("", test.input)
+		assert.NoError(t, err, test.input)
+		val, err := EvalSum(expr, env)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, val, test.input)
+	}
+}
+
+func TestEvalComparison(t *testing.T) {
+	env := Env{"x": 3, "y": 4}
+	tests := []struct {
+		input    string
+		expected types.Bool
+	}{
+		{"x + y > 6", true},
+		{"x + y >= 8", false},
+		{"x < y", true},
+		{"x =< 3", true},
+		{"x * 2 == y + 2", true},
+		{"x mod 2 != 1", false},
+	}
+	for _, test := range tests {
+		expr, err := ParseComparison("", test.input)
+		assert.NoError(t, err, test.input)
+		val, err := EvalComparison(expr, env)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, val, test.input)
+		assert.Equal(t, test.input, Format(expr))
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x / (y - y) > 0", "1:3: division by zero"},
+		{"z > 1", "1:1: unbound variable 'z'"},
+		{"99999999999999999999 > 1", "1:1: integer '99999999999999999999' is too large"},
+		{"9223372036854775807 + 1 > 0", "1:21: integer overflow"},
+		{"-9223372036854775807 - 2 < 0", "1:22: integer overflow"},
+		{"4611686018427387904 * 2 > 0", "1:21: integer overflow"},
+		{"-(-9223372036854775807 - 1) > 0", "1:1: integer overflow"},
+		{"(-9223372036854775807 - 1) / -1 > 0", "1:28: integer overflow"},
+	}
+	for _, test := range tests {
+		expr, err := ParseComparison("", test.input)
+		assert.NoError(t, err, test.input)
+		_, err = EvalComparison(expr, Env{"x": 1, "y": 2})
+		assert.EqualError(t, err, test.expected, test.input)
+	}
+}
+
+func TestEvalAtTheLimits(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected types.Int
+	}{
+		{"9223372036854775806 + 1", math.MaxInt64},
+		{"-9223372036854775808", math.MinInt64},
+		{"(-9223372036854775807 - 1) mod -1", 0},
+	}
+	for _, test := range tests {
+		expr, err := ExprParser.ParseString("", test.input)
+		assert.NoError(t, err, test.input)
+		val, err := EvalSum(expr, Env{})
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, val, test.input)
+	}
+}
+
+func TestVars(t *testing.T) {
+	expr, err := ParseComparison("", "x + y * (z - x) > y")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x", "y", "z"}, Vars(expr))
+}
+
+func TestImpliedByIsNotARelation(t *testing.T) {
+	_, err := ParseComparison("", "x - y <= 0")
+	assert.Error(t, err)
+	_, err = ParseComparison("", "x =< y")
+	assert.NoError(t, err)
+}
+
+func TestUnspacedRelations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"s>3", "s > 3"},
+		{"x<s", "x < s"},
+		{"x>s", "x > s"},
+		{"s=<y", "s =< y"},
+	}
+	for _, test := range tests {
+		expr, err := ParseComparison("", test.input)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, Format(expr))
+	}
+}
+
+func TestDegreeOperandFail(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x > 0.5", "1:5: '0.5' is a truth degree, but comparisons take integers"},
+		{"0.5 =< x", "1:1: '0.5' is a truth degree, but comparisons take integers"},
+	}
+	for _, test := range tests {
+		_, err := ParseComparison("", test.input)
+		assert.EqualError(t, err, test.expected, test.input)
+	}
+}
@@ -0,0 +1,237 @@
+package bits
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	bitsAst "acornlang.dev/lang/types/ast/bits"
+	"acornlang.dev/lang/types/ir"
+	"github.com/alecthomas/participle/v2"
+)
+
+const MAX_WIDTH = 64
+
+// Regd. Parsing
+
+var ExprParser = participle.MustBuild[bitsAst.Expr](
+	participle.Lexer(lexer.BooleanLexer),
+	participle.Elide("Whitespace", "Comment"),
+)
+
+// ParseLiteral reads `0b1011`, one bit per digit, or `0xF0`, four bits
+// per digit; leading zeros count towards the width.
+func ParseLiteral(lit string) (types.Bits, error) {
+	var digits string
+	var base, perDigit int
+	switch {
+	case strings.HasPrefix(lit, lexer.BINARY_PREFIX):
+		digits, base, perDigit = lit[len(lexer.BINARY_PREFIX):], 2, 1
+	case strings.HasPrefix(lit, lexer.HEX_PREFIX):
+		digits, base, perDigit = lit[len(lexer.HEX_PREFIX):], 16, 4
+	default:
+		return types.Bits{}, fmt.Errorf("invalid bit-vector '%s'", lit)
+	}
+	width := len(digits) * perDigit
+	if width > MAX_WIDTH {
+		return types.Bits{}, fmt.Errorf("bit-vector '%s' is wider than %d bits", lit, MAX_WIDTH)
+	}
+	val, err := strconv.ParseUint(digits, base, MAX_WIDTH)
+	if err != nil {
+		return types.Bits{}, fmt.Errorf("invalid bit-vector '%s'", lit)
+	}
+	return types.Bits{Value: val, Width: width}, nil
+}
+
+// ParseShift reads the amount of a shift or rotation.
+func ParseShift(rest *bitsAst.ShiftRest) (int, error) {
+	amount, err := strconv.Atoi(rest.Amount)
+	if err != nil || amount > MAX_WIDTH {
+		return 0, errorAt(rest.Pos, "cannot %s by %s, at most %d", verb(rest.Op), rest.Amount, MAX_WIDTH)
+	}
+	return amount, nil
+}
+
+func verb(op string) string {
+	switch op {
+	case lexer.ROTATE_LEFT_TEXT, lexer.ROTATE_RIGHT_TEXT:
+		return "rotate"
+	default:
+		return "shift"
+	}
+}
+
+// Regd. Evaluation
+
+type Env map[string]types.Bits
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+func errorEvalResult(pos types.Position, err error) boolean.EvalResult {
+	return boolean.EvalResult{
+		Pos:     pos,
+		Payload: types.Bits{},
+		Err:     err,
+	}
+}
+
+func successEvalResult(pos types.Position, payload types.Value) boolean.EvalResult {
+	return boolean.EvalResult{
+		Pos:     pos,
+		Payload: payload,
+		Err:     nil,
+	}
+}
+
+// EvalExpr evaluates to Bits, or to an Int for `popcount(...)`.
+func EvalExpr(expr *bitsAst.Expr, env Env) boolean.EvalResult {
+	if expr == nil {
+		return errorEvalResult(types.Position{}, fmt.Errorf("invalid bit-vector expression 'nil'"))
+	}
+	if expr.Count != nil {
+		val, err := EvalBitwise(expr.Count.Expr, env)
+		if err != nil {
+			return errorEvalResult(expr.Pos, err)
+		}
+		return successEvalResult(expr.Pos, types.Int(bits.OnesCount64(val.Value)))
+	}
+	val, err := EvalBitwise(expr.Body, env)
+	if err != nil {
+		return errorEvalResult(expr.Pos, err)
+	}
+	return successEvalResult(expr.Pos, val)
+}
+
+func EvalBitwise(expr *bitsAst.BitwiseExpr, env Env) (types.Bits, error) {
+	left, err := evalUnary(expr.Unary, env)
+	if err != nil || expr.Rest == nil {
+		return left, err
+	}
+	right, err := EvalBitwise(expr.Rest.Expr, env)
+	if err != nil {
+		return types.Bits{}, err
+	}
+	if left.Width != right.Width {
+		return types.Bits{}, errorAt(expr.Rest.Pos, "'%s' needs two bit-vectors of the same width, not %d and %d bits", expr.Rest.Op, left.Width, right.Width)
+	}
+	apply, ok := Connective(expr.Rest.Op)
+	if !ok {
+		return types.Bits{}, errorAt(expr.Rest.Pos, "'%s' does not apply bit by bit", expr.Rest.Op)
+	}
+	return types.Bits{Value: apply(left.Value, right.Value) & mask(left.Width), Width: left.Width}, nil
+}
+
+// Connective gives a binary connective that applies bit by bit its
+// meaning on whole words, read off its truth table; the result still has
+// to be masked to the width. Temporal operators have none.
+func Connective(spelling string) (func(a uint64, b uint64) uint64, bool) {
+	op, ok := ir.ParseOp(spelling)
+	if !ok {
+		return nil, false
+	}
+	table, ok := op.Table()
+	if !ok {
+		return nil, false
+	}
+	return func(a, b uint64) uint64 {
+		var acc uint64
+		if table&0b1000 != 0 {
+			acc |= a & b
+		}
+		if table&0b0100 != 0 {
+			acc |= a &^ b
+		}
+		if table&0b0010 != 0 {
+			acc |= ^a & b
+		}
+		if table&0b0001 != 0 {
+			acc |= ^a &^ b
+		}
+		return acc
+	}, true
+}
+
+func mask(width int) uint64 {
+	if width == MAX_WIDTH {
+		return ^uint64(0)
+	}
+	return 1<<width - 1
+}
+
+func evalUnary(expr *bitsAst.UnaryExpr, env Env) (types.Bits, error) {
+	acc, err := evalShift(expr.Expr, env)
+	if err != nil {
+		return types.Bits{}, err
+	}
+	for idx := len(expr.Ops) - 1; idx >= 0; idx-- {
+		op, _ := ir.ParseOp(expr.Ops[idx].Op)
+		switch op {
+		case ir.NOT:
+			acc.Value = ^acc.Value & mask(acc.Width)
+		case ir.NULLIFY:
+			acc.Value = 0
+		case ir.TRUIFY:
+			acc.Value = mask(acc.Width)
+		case ir.ID:
+			// No change
+		default:
+			return types.Bits{}, errorAt(expr.Ops[idx].Pos, "'%s' does not apply bit by bit", expr.Ops[idx].Op)
+		}
+	}
+	return acc, nil
+}
+
+// evalShift shifts in zeros; a rotation wraps around the width.
+func evalShift(expr *bitsAst.ShiftExpr, env Env) (types.Bits, error) {
+	acc, err := evalPrimary(expr.Head, env)
+	if err != nil {
+		return types.Bits{}, err
+	}
+	for _, rest := range expr.Rest {
+		amount, err := ParseShift(&rest)
+		if err != nil {
+			return types.Bits{}, err
+		}
+		width := acc.Width
+		switch rest.Op {
+		case lexer.SHIFT_LEFT_SYMB:
+			acc.Value = acc.Value << amount & mask(width)
+		case lexer.SHIFT_RIGHT_SYMB:
+			acc.Value = acc.Value >> amount
+		case lexer.ROTATE_LEFT_TEXT, lexer.ROTATE_RIGHT_TEXT:
+			amount %= width
+			if rest.Op == lexer.ROTATE_RIGHT_TEXT {
+				amount = (width - amount) % width
+			}
+			acc.Value = (acc.Value<<amount | acc.Value>>(width-amount)) & mask(width)
+		default:
+			return types.Bits{}, errorAt(rest.Pos, "invalid shift '%s'", rest.Op)
+		}
+	}
+	return acc, nil
+}
+
+func evalPrimary(expr *bitsAst.PrimaryExpr, env Env) (types.Bits, error) {
+	switch {
+	case expr.Paren != nil:
+		return EvalBitwise(expr.Paren.Expr, env)
+	case expr.Var != "":
+		val, ok := env[expr.Var]
+		if !ok {
+			return types.Bits{}, errorAt(expr.Pos, "unbound variable '%s'", expr.Var)
+		}
+		return val, nil
+	default:
+		val, err := ParseLiteral(expr.Lit)
+		if err != nil {
+			return types.Bits{}, errorAt(expr.Pos, "%s", err.Error())
+		}
+		return val, nil
+	}
+}
@@ -0,0 +1,91 @@
+package bits
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ir"
+	"github.com/stretchr/testify/assert"
+)
+
+func evalBits(t *testing.T, input string, env Env) boolean.EvalResult {
+	expr, err := ExprParser.ParseString("", input)
+	assert.NoError(t, err, input)
+	return EvalExpr(expr, env)
+}
+
+func TestEvalBits(t *testing.T) {
+	env := Env{"flags": {Value: 0b1010, Width: 4}}
+	tests := []struct {
+		input    string
+		expected types.Value
+	}{
+		{"0b1011", types.Bits{Value: 0b1011, Width: 4}},
+		{"0x0F", types.Bits{Value: 0x0F, Width: 8}},
+		{"0b1100 and 0b1010", types.Bits{Value: 0b1000, Width: 4}},
+		{"0b1100 nand 0b1010", types.Bits{Value: 0b0111, Width: 4}},
+		{"0b1100 inhibits 0b1010", types.Bits{Value: 0b0100, Width: 4}},
+		{"0b1100 not left 0b1010", types.Bits{Value: 0b0011, Width: 4}},
+		{"not flags", types.Bits{Value: 0b0101, Width: 4}},
+		{"truify 0x0", types.Bits{Value: 0xF, Width: 4}},
+		{"flags << 1", types.Bits{Value: 0b0100, Width: 4}},
+		{"flags >> 3", types.Bits{Value: 0b0001, Width: 4}},
+		{"0b1001 rotl 1", types.Bits{Value: 0b0011, Width: 4}},
+		{"0b1001 rotr 5", types.Bits{Value: 0b1100, Width: 4}},
+		{"not flags << 1", types.Bits{Value: 0b1011, Width: 4}},
+		{"(0xF0 xor 0xFF) rotl 4", types.Bits{Value: 0xF0, Width: 8}},
+		{"popcount(flags or 0b0001)", types.Int(3)},
+	}
+	for _, test := range tests {
+		res := evalBits(t, test.input, env)
+		assert.NoError(t, res.Err, test.input)
+		assert.Equal(t, test.expected, res.Payload, test.input)
+	}
+}
+
+// spellings lists every binary connective, in words and in symbols.
+func spellings() []string {
+	acc := []string{lexer.XNOR_TEXT}
+	for op := ir.AND; op <= ir.NOT_RIGHT; op++ {
+		acc = append(acc, op.String(), op.Symbol())
+	}
+	return acc
+}
+
+// TestConnectivesAgreeWithBooleans checks every connective bit by bit
+// against the boolean evaluator.
+func TestConnectivesAgreeWithBooleans(t *testing.T) {
+	a, b := types.Bits{Value: 0b0011, Width: 4}, types.Bits{Value: 0b0101, Width: 4}
+	for _, op := range spellings() {
+		res := evalBits(t, "a "+op+" b", Env{"a": a, "b": b})
+		assert.NoError(t, res.Err, op)
+		got := res.Payload.(types.Bits)
+		for bit := 0; bit < 4; bit++ {
+			env := boolean.Env{"p": a.Value>>bit&1 == 1, "q": b.Value>>bit&1 == 1}
+			parsed, err := boolean.ExprParser.ParseString("", "p "+op+" q")
+			assert.NoError(t, err, op)
+			expected := boolean.EvalExpr(parsed, env).Payload == types.Bool(true)
+			assert.Equal(t, expected, got.Value>>bit&1 == 1, "%s at bit %d", op, bit)
+		}
+	}
+}
+
+func TestEvalBitsErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0b1 and 0x1", "1:5: 'and' needs two bit-vectors of the same width, not 1 and 4 bits"},
+		{"0b1 until 0b0", "1:5: 'until' does not apply bit by bit"},
+		{"next 0b1", "1:1: 'next' does not apply bit by bit"},
+		{"flags << 1", "1:1: unbound variable 'flags'"},
+		{"0b1 rotl 99", "1:5: cannot rotate by 99, at most 64"},
+		{"0x00000000000000000", "1:1: bit-vector '0x00000000000000000' is wider than 64 bits"},
+	}
+	for _, test := range tests {
+		res := evalBits(t, test.input, Env{})
+		assert.EqualError(t, res.Err, test.expected, test.input)
+	}
+}
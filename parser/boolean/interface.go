@@ -5,8 +5,12 @@ import (
 	"fmt"
 
 	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/arith"
 	"acornlang.dev/lang/types"
+	arithAst "acornlang.dev/lang/types/ast/arith"
 	"acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ast/walk"
+	"acornlang.dev/lang/types/ir"
 	"github.com/alecthomas/participle/v2"
 )
 
@@ -14,26 +18,28 @@ import (
 
 var ExprParser = participle.MustBuild[boolean.Expr](
 	participle.Lexer(lexer.BooleanLexer),
-	participle.Elide("Whitespace"),
+	participle.Elide("Whitespace", "Comment"),
 )
 
 // Regd. Evaluation
 
+type Env map[string]bool
+
 type EvalResult struct {
 	Pos     types.Position
-	Payload bool
+	Payload types.Value
 	Err     error
 }
 
 func errorEvalResult(pos types.Position, msg string) EvalResult {
 	return EvalResult{
 		Pos:     pos,
-		Payload: false,
+		Payload: types.Bool(false),
 		Err:     errors.New(msg),
 	}
 }
 
-func successEvalResult(pos types.Position, payload bool) EvalResult {
+func successEvalResult(pos types.Position, payload types.Value) EvalResult {
 	return EvalResult{
 		Pos:     pos,
 		Payload: payload,
@@ -41,128 +47,201 @@ func successEvalResult(pos types.Position, payload bool) EvalResult {
 	}
 }
 
-func errNotImplemented(pos types.Position, thing string) EvalResult {
-	return errorEvalResult(pos, "TODO: implement evaluation of "+thing)
-}
-
 func errInvalid(pos types.Position, thing string, invalidThing any) EvalResult {
 	errMsg := fmt.Errorf("invalid %s '%s'", thing, invalidThing).Error()
 	return errorEvalResult(pos, errMsg)
 }
 
-func EvalPrimaryExpr(expr *boolean.PrimaryExpr) EvalResult {
-	if expr == nil {
-		return errInvalid(types.Position{}, "primary expression", "nil")
-	}
-	if expr.Paren != nil && expr.Lit != "" {
-		return errInvalid(expr.Pos, "primary expression", "both Lit and Paren")
-	}
-	if expr.Paren != nil {
-		return EvalParenExpr(expr.Paren)
+func errTemporal(pos types.Position, op string) EvalResult {
+	errMsg := fmt.Errorf("temporal operator '%s' can only be evaluated over a trace", op).Error()
+	return errorEvalResult(pos, errMsg)
+}
+
+func errDegree(pos types.Position, degree string) EvalResult {
+	errMsg := fmt.Errorf("truth degree '%s' needs a fuzzy logic", degree).Error()
+	return errorEvalResult(pos, errMsg)
+}
+
+func errUnbound(pos types.Position, name string) EvalResult {
+	errMsg := fmt.Errorf("unbound variable '%s'", name).Error()
+	return errorEvalResult(pos, errMsg)
+}
+
+// EvalExpr lowers expr to its semantic tree and evaluates that.
+func EvalExpr(expr *boolean.Expr, env Env) EvalResult {
+	node, err := ir.Lower(expr)
+	if err != nil {
+		var lowerErr *ir.Error
+		if errors.As(err, &lowerErr) {
+			return errorEvalResult(lowerErr.Pos, lowerErr.Msg)
+		}
+		return errorEvalResult(types.Position{}, err.Error())
 	}
-	switch expr.Lit {
-	case lexer.TRUE:
-		return successEvalResult(expr.Pos, true)
-	case lexer.FALSE:
-		return successEvalResult(expr.Pos, false)
+	return Eval(node, env)
+}
+
+// Eval evaluates a semantic tree. The right operand of a connective is
+// evaluated first, and its error wins.
+func Eval(node ir.Node, env Env) EvalResult {
+	switch n := node.(type) {
+	case *ir.Const:
+		return successEvalResult(n.Pos, types.Bool(n.Value))
+	case *ir.Var:
+		val, ok := env[n.Name]
+		if !ok {
+			return errUnbound(n.Pos, n.Name)
+		}
+		return successEvalResult(n.Pos, types.Bool(val))
+	case *ir.Degree:
+		return errDegree(n.Pos, n.Text)
+	case *ir.Cmp:
+		val, err := arith.EvalComparison(n.Cmp, nil)
+		if err != nil {
+			return EvalResult{Pos: n.Pos, Payload: types.Bool(false), Err: err}
+		}
+		return successEvalResult(n.Pos, val)
+	case *ir.Unary:
+		res := Eval(n.X, env)
+		if res.Err != nil {
+			return res
+		}
+		operand, ok := res.Payload.(types.Bool)
+		if !ok {
+			return errInvalid(n.Pos, "boolean operand", res.Payload)
+		}
+		if n.Op.IsTemporal() {
+			return errTemporal(n.Pos, n.Text)
+		}
+		return successEvalResult(n.Pos, types.Bool(n.Op.ApplyUnary(bool(operand))))
+	case *ir.Binary:
+		rightRes := Eval(n.Y, env)
+		if rightRes.Err != nil {
+			return rightRes
+		}
+		leftRes := Eval(n.X, env)
+		if leftRes.Err != nil {
+			return leftRes
+		}
+		left, ok := leftRes.Payload.(types.Bool)
+		if !ok {
+			return errInvalid(leftRes.Pos, "boolean operand", leftRes.Payload)
+		}
+		right, ok := rightRes.Payload.(types.Bool)
+		if !ok {
+			return errInvalid(rightRes.Pos, "boolean operand", rightRes.Payload)
+		}
+		if n.Op.IsTemporal() {
+			return errTemporal(n.OpPos, n.Text)
+		}
+		return successEvalResult(n.Pos, types.Bool(n.Op.Apply(bool(left), bool(right))))
 	default:
-		return errInvalid(expr.Pos, "boolean literal", expr.Lit)
+		return errInvalid(types.Position{}, "boolean expression", node)
 	}
 }
 
-func EvalParenExpr(expr *boolean.ParenExpr) EvalResult {
-	booleanExprRes := EvalExpr(expr.Expr)
-	if booleanExprRes.Err != nil {
-		return booleanExprRes
+// EvalPrimaryExpr evaluates a literal, variable or bracketed expression.
+//
+// Deprecated: lower the whole expression with ir.Lower and call Eval.
+func EvalPrimaryExpr(expr *boolean.PrimaryExpr, env Env) EvalResult {
+	if expr == nil {
+		return errInvalid(types.Position{}, "primary expression", "nil")
 	}
-	return successEvalResult(expr.Pos, booleanExprRes.Payload)
+	return EvalUnaryExpr(&boolean.UnaryExpr{Pos: expr.Pos, Expr: expr}, env)
 }
 
-func EvalUnaryExpr(expr *boolean.UnaryExpr) EvalResult {
+// EvalParenExpr evaluates a bracketed expression at the bracket's position.
+//
+// Deprecated: lower the whole expression with ir.Lower and call Eval.
+func EvalParenExpr(expr *boolean.ParenExpr, env Env) EvalResult {
 	if expr == nil {
-		return errInvalid(types.Position{}, "unary expression", "nil")
-	}
-	exprRes := EvalPrimaryExpr(expr.Expr)
-	if exprRes.Err != nil {
-		return exprRes
+		return errInvalid(types.Position{}, "parenthesized expression", "nil")
 	}
-	acc := exprRes.Payload
-	for idx := len(expr.Ops) - 1; idx >= 0; idx-- {
-		switch expr.Ops[idx].Op {
-		case lexer.NOT_TEXT, lexer.NOT_SYMB:
-			acc = !acc
-		case lexer.NULLIFY_TEXT:
-			acc = false
-		case lexer.TRUIFY_TEXT:
-			acc = true
-		case lexer.ID_TEXT:
-			// No change
-		default:
-			return errInvalid(expr.Pos, "unary operator", expr.Ops[idx].Op)
-		}
+	res := EvalExpr(expr.Expr, env)
+	if res.Err != nil {
+		return res
 	}
-	return successEvalResult(expr.Pos, acc)
+	return successEvalResult(expr.Pos, res.Payload)
 }
 
-func EvalExpr(expr *boolean.Expr) EvalResult {
+// EvalUnaryExpr evaluates a primary expression under its unary operators.
+//
+// Deprecated: lower the whole expression with ir.Lower and call Eval.
+func EvalUnaryExpr(expr *boolean.UnaryExpr, env Env) EvalResult {
 	if expr == nil {
-		return errInvalid(types.Position{}, "boolean expression", "nil")
+		return errInvalid(types.Position{}, "unary expression", "nil")
 	}
-	unaryRes := EvalUnaryExpr(expr.Unary)
-	return TransmogrifyUnaryResBasedOnRest(expr.Rest)(unaryRes)
+	return EvalExpr(&boolean.Expr{Pos: expr.Pos, Unary: expr}, env)
 }
 
-func TransmogrifyUnaryResBasedOnRest(rest *boolean.ExprRest) func(EvalResult) EvalResult {
+// TransmogrifyUnaryResBasedOnRest returns a function that combines the
+// value of a left operand with the value of rest.
+//
+// Deprecated: lower the whole expression with ir.Lower and call Eval.
+func TransmogrifyUnaryResBasedOnRest(rest *boolean.ExprRest, env Env) func(EvalResult) EvalResult {
 	if rest == nil {
 		return func(unaryRes EvalResult) EvalResult {
 			return unaryRes
 		}
 	}
-	exprRes := EvalExpr(rest.Expr)
-	if exprRes.Err != nil {
-		return func(unaryRes EvalResult) EvalResult {
+	exprRes := EvalExpr(rest.Expr, env)
+	return func(unaryRes EvalResult) EvalResult {
+		if exprRes.Err != nil {
 			return exprRes
 		}
-	}
-	return func(unaryRes EvalResult) EvalResult {
 		if unaryRes.Err != nil {
 			return unaryRes
 		}
-		left := unaryRes.Payload
-		right := exprRes.Payload
-		var resPayload bool
-		switch rest.Op {
-		case lexer.AND_TEXT, lexer.AND_SYMB:
-			resPayload = left && right
-		case lexer.NAND_TEXT, lexer.NAND_SYMB:
-			resPayload = !(left && right)
-		case lexer.OR_TEXT, lexer.OR_SYMB:
-			resPayload = left || right
-		case lexer.NOR_TEXT, lexer.NOR_SYMB:
-			resPayload = !(left || right)
-		case lexer.IMPLIES_TEXT, lexer.IMPLIES_SYMB:
-			resPayload = !left || right
-		case lexer.IMPLIED_BY_TEXT, lexer.IMPLIED_BY_SYMB:
-			resPayload = left || !right
-		case lexer.INHIBITS_TEXT, lexer.INHIBITS_SYMB:
-			resPayload = !left || !right
-		case lexer.INHIBITED_BY_TEXT, lexer.INHIBITED_BY_SYMB:
-			resPayload = !left || !right
-		case lexer.LEFT_TEXT, lexer.LEFT_SYMB:
-			// no change
-		case lexer.RIGHT_TEXT, lexer.RIGHT_SYMB:
-			resPayload = right
-		case lexer.NOT_LEFT_TEXT, lexer.NOT_LEFT_SYMB:
-			resPayload = !left
-		case lexer.NOT_RIGHT_TEXT, lexer.NOT_RIGHT_SYMB:
-			resPayload = !right
-		case lexer.XNOR_TEXT, lexer.XNOR_SYMB, lexer.IFF_TEXT:
-			resPayload = left == right
-		case lexer.XOR_TEXT, lexer.XOR_SYMB:
-			resPayload = left != right
-		default:
-			return errInvalid(exprRes.Pos, "binary operation", rest.Op)
-		}
-		return successEvalResult(unaryRes.Pos, resPayload)
+		op, ok := ir.ParseOp(rest.Op)
+		if !ok || op.IsUnary() {
+			return errInvalid(rest.Pos, "binary operation", rest.Op)
+		}
+		if op.IsTemporal() {
+			return errTemporal(rest.Pos, rest.Op)
+		}
+		left, ok := unaryRes.Payload.(types.Bool)
+		if !ok {
+			return errInvalid(unaryRes.Pos, "boolean operand", unaryRes.Payload)
+		}
+		right, ok := exprRes.Payload.(types.Bool)
+		if !ok {
+			return errInvalid(exprRes.Pos, "boolean operand", exprRes.Payload)
+		}
+		return successEvalResult(unaryRes.Pos, types.Bool(op.Apply(bool(left), bool(right))))
+	}
+}
+
+// Regd. Variables
+
+// FreeVars lists the truth-valued names of expr; the names compared as
+// integers are listed by IntVars.
+func FreeVars(expr *boolean.Expr) []string {
+	seen := map[string]bool{}
+	acc := []string{}
+	if expr == nil {
+		return acc
 	}
+	walk.Inspect(expr, func(node walk.Node) bool {
+		if primary, ok := node.(*boolean.PrimaryExpr); ok && primary.Var != "" && !seen[primary.Var] {
+			seen[primary.Var] = true
+			acc = append(acc, primary.Var)
+		}
+		return true
+	})
+	return acc
+}
+
+func IntVars(expr *boolean.Expr) []string {
+	seen := map[string]bool{}
+	acc := []string{}
+	if expr == nil {
+		return acc
+	}
+	walk.Inspect(expr, func(node walk.Node) bool {
+		if factor, ok := node.(*arithAst.Factor); ok && factor.Var != "" && !seen[factor.Var] {
+			seen[factor.Var] = true
+			acc = append(acc, factor.Var)
+		}
+		return true
+	})
+	return acc
 }
@@ -466,3 +466,157 @@ func produceSingleBinopTestCaseSet(binop string) []SuccessfulBinopTestCase {
 		},
 	}
 }
+
+type EvalBinopTestCase struct {
+	binop    string
+	expected [4]bool
+}
+
+func TestEvalBinops(t *testing.T) {
+	// expected holds the results for FF, FT, TF and TT in that order
+	tests := []EvalBinopTestCase{
+		{lexer.AND_TEXT, [4]bool{false, false, false, true}},
+		{lexer.NAND_SYMB, [4]bool{true, true, true, false}},
+		{lexer.OR_TEXT, [4]bool{false, true, true, true}},
+		{lexer.NOR_SYMB, [4]bool{true, false, false, false}},
+		{lexer.XNOR_TEXT, [4]bool{true, false, false, true}},
+		{lexer.IFF_TEXT, [4]bool{true, false, false, true}},
+		{lexer.XOR_SYMB, [4]bool{false, true, true, false}},
+		{lexer.IMPLIES_SYMB, [4]bool{true, true, false, true}},
+		{lexer.IMPLIED_BY_TEXT, [4]bool{true, false, true, true}},
+		{lexer.INHIBITS_TEXT, [4]bool{false, false, true, false}},
+		{lexer.INHIBITED_BY_SYMB, [4]bool{false, true, false, false}},
+		{lexer.LEFT_SYMB, [4]bool{false, false, true, true}},
+		{lexer.RIGHT_TEXT, [4]bool{false, true, false, true}},
+		{lexer.NOT_LEFT_TEXT, [4]bool{true, true, false, false}},
+		{lexer.NOT_RIGHT_SYMB, [4]bool{true, false, true, false}},
+	}
+	for _, test := range tests {
+		for idx, operands := range [][2]string{
+			{lexer.FALSE, lexer.FALSE},
+			{lexer.FALSE, lexer.TRUE},
+			{lexer.TRUE, lexer.FALSE},
+			{lexer.TRUE, lexer.TRUE},
+		} {
+			input := operands[0] + " " + test.binop + " " + operands[1]
+			parsed, err := ExprParser.ParseString("", input)
+			assert.NoError(t, err)
+			res := EvalExpr(parsed, Env{})
+			assert.NoError(t, res.Err)
+			assert.Equal(t, types.Bool(test.expected[idx]), res.Payload, input)
+		}
+	}
+}
+
+func TestEvalVar(t *testing.T) {
+	parsed, err := ExprParser.ParseString("", "p and not q")
+	assert.NoError(t, err)
+	res := EvalExpr(parsed, Env{"p": true, "q": false})
+	assert.NoError(t, res.Err)
+	assert.Equal(t, types.Bool(true), res.Payload)
+	res = EvalExpr(parsed, Env{"p": true, "q": true})
+	assert.NoError(t, res.Err)
+	assert.Equal(t, types.Bool(false), res.Payload)
+}
+
+func TestEvalUnboundVar(t *testing.T) {
+	parsed, err := ExprParser.ParseString("", "True and q")
+	assert.NoError(t, err)
+	res := EvalExpr(parsed, Env{})
+	assert.EqualError(t, res.Err, "unbound variable 'q'")
+	assert.Equal(t, 10, res.Pos.Column)
+}
+
+func TestFreeVars(t *testing.T) {
+	parsed, err := ExprParser.ParseString("", "b and (a or not b) => (True xor c)")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a", "c"}, FreeVars(parsed))
+}
+
+func TestTemporalOps(t *testing.T) {
+	res, err := ExprParser.ParseString("", "□(req => ◇ack)")
+	assert.NoError(t, err)
+	assert.Equal(t, lexer.GLOBALLY_SYMB, res.Unary.Ops[0].Op)
+	inner := res.Unary.Expr.Paren.Expr
+	assert.Equal(t, lexer.IMPLIES_SYMB, inner.Rest.Op)
+	assert.Equal(t, lexer.FINALLY_SYMB, inner.Rest.Expr.Unary.Ops[0].Op)
+
+	res, err = ExprParser.ParseString("", "next p until q release Go")
+	assert.NoError(t, err)
+	assert.Equal(t, lexer.NEXT_TEXT, res.Unary.Ops[0].Op)
+	assert.Equal(t, lexer.UNTIL_TEXT, res.Rest.Op)
+	assert.Equal(t, lexer.RELEASE_TEXT, res.Rest.Expr.Rest.Op)
+	assert.Equal(t, "Go", res.Rest.Expr.Rest.Expr.Unary.Expr.Var)
+}
+
+func TestTemporalLettersAreVariables(t *testing.T) {
+	res, err := ExprParser.ParseString("", "F and X")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"F", "X"}, FreeVars(res))
+	for _, input := range []string{"G or U", "R => F"} {
+		_, err = ExprParser.ParseString("", input)
+		assert.NoError(t, err, input)
+	}
+}
+
+func TestComparisons(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected types.Bool
+	}{
+		{"2 + 3 > 4 and not False", true},
+		{"(1 + 1) * 2 >= 5", false},
+		{"(3 < 4) or False", true},
+		{"7 mod 3 == 1 => 2 - 3 < 0", true},
+		{"not (10 / 3 != 3)", true},
+	}
+	for _, test := range tests {
+		parsed, err := ExprParser.ParseString("", test.input)
+		assert.NoError(t, err, test.input)
+		res := EvalExpr(parsed, Env{})
+		assert.NoError(t, res.Err, test.input)
+		assert.Equal(t, test.expected, res.Payload, test.input)
+	}
+}
+
+func TestImpliedByStaysBoolean(t *testing.T) {
+	parsed, err := ExprParser.ParseString("", "p <= q and r")
+	assert.NoError(t, err)
+	assert.Nil(t, parsed.Unary.Expr.Cmp)
+	assert.Equal(t, lexer.IMPLIED_BY_SYMB, parsed.Rest.Op)
+
+	parsed, err = ExprParser.ParseString("", "x =< 3 and r")
+	assert.NoError(t, err)
+	assert.Equal(t, lexer.LESS_EQUAL_SYMB, parsed.Unary.Expr.Cmp.Op)
+	assert.Equal(t, lexer.AND_TEXT, parsed.Rest.Op)
+
+	parsed, err = ExprParser.ParseString("", "p <s q and x<s")
+	assert.NoError(t, err)
+	assert.Equal(t, lexer.LEFT_SYMB, parsed.Rest.Op)
+	assert.Equal(t, lexer.LESS_SYMB, parsed.Rest.Expr.Rest.Expr.Unary.Expr.Cmp.Op)
+}
+
+func TestIntVars(t *testing.T) {
+	parsed, err := ExprParser.ParseString("", "x + y > 3 and not flag or (z == x)")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"flag"}, FreeVars(parsed))
+	assert.Equal(t, []string{"x", "y", "z"}, IntVars(parsed))
+}
+
+func TestDeprecatedEvalAgreesWithEval(t *testing.T) {
+	parsed, err := ExprParser.ParseString("", "not (p /=> q) xor p")
+	assert.NoError(t, err)
+	env := Env{"p": true, "q": false}
+	expected := EvalExpr(parsed, env)
+	assert.NoError(t, expected.Err)
+
+	unaryRes := EvalUnaryExpr(parsed.Unary, env)
+	assert.NoError(t, unaryRes.Err)
+	assert.Equal(t, types.Bool(false), unaryRes.Payload)
+	assert.Equal(t, expected.Payload, TransmogrifyUnaryResBasedOnRest(parsed.Rest, env)(unaryRes).Payload)
+
+	parenRes := EvalParenExpr(parsed.Unary.Expr.Paren, env)
+	assert.Equal(t, types.Bool(true), parenRes.Payload)
+	assert.Equal(t, parsed.Unary.Expr.Paren.Pos, parenRes.Pos)
+	assert.Equal(t, parenRes.Payload, EvalPrimaryExpr(parsed.Unary.Expr, env).Payload)
+}
@@ -0,0 +1,155 @@
+package data
+
+import (
+	"fmt"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	dataAst "acornlang.dev/lang/types/ast/data"
+	"github.com/alecthomas/participle/v2"
+)
+
+// Regd. Parsing
+
+// ExprParser looks ahead as far as it needs to: `(p, q)` and `(p) and q`
+// only differ after the first operand.
+var ExprParser = participle.MustBuild[dataAst.Expr](
+	participle.Lexer(lexer.BooleanLexer),
+	participle.Elide("Whitespace", "Comment"),
+	participle.UseLookahead(participle.MaxLookahead),
+)
+
+// Regd. Evaluation
+
+// Env maps names to values of any type; the names bound to a Bool are
+// also visible to the boolean expressions at the leaves.
+type Env map[string]types.Value
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+func EvalExpr(expr *dataAst.Expr, env Env) boolean.EvalResult {
+	if expr == nil {
+		return boolean.EvalResult{Payload: types.Tuple{}, Err: fmt.Errorf("invalid data expression 'nil'")}
+	}
+	val, err := Eval(expr, env)
+	return boolean.EvalResult{Pos: expr.Pos, Payload: val, Err: err}
+}
+
+func Eval(expr *dataAst.Expr, env Env) (types.Value, error) {
+	switch {
+	case expr.Match != nil:
+		return evalMatch(expr.Match, env)
+	case expr.Tuple != nil:
+		elems, err := evalAll(expr.Tuple.Elems, env)
+		return types.Tuple{Elems: elems}, err
+	case expr.List != nil:
+		elems, err := evalAll(expr.List.Elems, env)
+		return types.List{Elems: elems}, err
+	case expr.Paren != nil:
+		return Eval(expr.Paren.Expr, env)
+	default:
+		return evalBool(expr.Bool, env)
+	}
+}
+
+func evalAll(exprs []*dataAst.Expr, env Env) ([]types.Value, error) {
+	acc := []types.Value{}
+	for _, expr := range exprs {
+		val, err := Eval(expr, env)
+		if err != nil {
+			return nil, err
+		}
+		acc = append(acc, val)
+	}
+	return acc, nil
+}
+
+// evalBool gives a lone name its value whatever its type; any other
+// boolean expression only sees the names bound to a Bool.
+func evalBool(expr *booleanAst.Expr, env Env) (types.Value, error) {
+	if name, ok := BareVar(expr); ok {
+		if val, ok := env[name]; ok {
+			return val, nil
+		}
+	}
+	bools := boolean.Env{}
+	for name, val := range env {
+		if b, ok := val.(types.Bool); ok {
+			bools[name] = bool(b)
+		}
+	}
+	for _, name := range boolean.FreeVars(expr) {
+		if val, ok := env[name]; ok && !val.Type().Equal(types.BOOL) {
+			return nil, errorAt(expr.Pos, "'%s' is %s, so it cannot be used as a truth value", name, val.Type())
+		}
+	}
+	res := boolean.EvalExpr(expr, bools)
+	return res.Payload, res.Err
+}
+
+// BareVar reports whether expr is a lone name.
+func BareVar(expr *booleanAst.Expr) (string, bool) {
+	if expr.Rest != nil || len(expr.Unary.Ops) != 0 || expr.Unary.Expr.Var == "" {
+		return "", false
+	}
+	return expr.Unary.Expr.Var, true
+}
+
+func evalMatch(expr *dataAst.Match, env Env) (types.Value, error) {
+	val, err := Eval(expr.Expr, env)
+	if err != nil {
+		return nil, err
+	}
+	for _, arm := range expr.Arms {
+		binds := Env{}
+		if !Matches(arm.Pattern, val, binds) {
+			continue
+		}
+		inner := Env{}
+		for name, val := range env {
+			inner[name] = val
+		}
+		for name, val := range binds {
+			inner[name] = val
+		}
+		return Eval(arm.Body, inner)
+	}
+	return nil, errorAt(expr.Pos, "no pattern matches %s", val)
+}
+
+// Matches reports whether val has the shape of pattern, adding the names
+// the pattern binds to binds.
+func Matches(pattern *dataAst.Pattern, val types.Value, binds Env) bool {
+	switch {
+	case pattern.Tuple != nil:
+		tuple, ok := val.(types.Tuple)
+		return ok && matchesAll(pattern.Tuple.Elems, tuple.Elems, binds)
+	case pattern.List != nil:
+		list, ok := val.(types.List)
+		return ok && matchesAll(pattern.List.Elems, list.Elems, binds)
+	case pattern.Lit != "":
+		b, ok := val.(types.Bool)
+		return ok && b.String() == pattern.Lit
+	default:
+		if pattern.Var != lexer.WILDCARD_SYMB {
+			binds[pattern.Var] = val
+		}
+		return true
+	}
+}
+
+func matchesAll(patterns []*dataAst.Pattern, vals []types.Value, binds Env) bool {
+	if len(patterns) != len(vals) {
+		return false
+	}
+	for idx, pattern := range patterns {
+		if !Matches(pattern, vals[idx], binds) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package data
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func evalData(t *testing.T, input string, env Env) (types.Value, error) {
+	expr, err := ExprParser.ParseString("", input)
+	assert.NoError(t, err, input)
+	return Eval(expr, env)
+}
+
+func tuple(elems ...types.Value) types.Tuple {
+	return types.Tuple{Elems: elems}
+}
+
+func TestEvalData(t *testing.T) {
+	env := Env{"p": types.Bool(true), "q": types.Bool(false)}
+	tests := []struct {
+		input    string
+		expected types.Value
+	}{
+		{"(True, False)", tuple(types.Bool(true), types.Bool(false))},
+		{"(p and q, (p, not q))", tuple(types.Bool(false), tuple(types.Bool(true), types.Bool(true)))},
+		{"[p, q, p xor q]", types.List{Elems: []types.Value{types.Bool(true), types.Bool(false), types.Bool(true)}}},
+		{"[]", types.List{Elems: []types.Value{}}},
+		{"(p) and q", types.Bool(false)},
+		{"match (p, q) with (True, True) -> False | (True, _) -> True | _ -> False", types.Bool(true)},
+		{"match (q, (p, q)) with | (False, pair) -> pair | _ -> (q, q)", tuple(types.Bool(true), types.Bool(false))},
+		{"match [p, q] with [] -> False | [a] -> a | [a, b] -> a inhibits b", types.Bool(true)},
+		{"match p with x -> (match q with True -> x | y -> (x, y))", tuple(types.Bool(true), types.Bool(false))},
+	}
+	for _, test := range tests {
+		val, err := evalData(t, test.input, env)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, val, test.input)
+	}
+}
+
+func TestEvalDataErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (True, False) with (True, True) -> True", "1:1: no pattern matches (True, False)"},
+		{"match (True, False) with pair -> pair and True", "1:34: 'pair' is (Bool, Bool), so it cannot be used as a truth value"},
+		{"(r, True)", "unbound variable 'r'"},
+	}
+	for _, test := range tests {
+		_, err := evalData(t, test.input, Env{})
+		assert.EqualError(t, err, test.expected, test.input)
+	}
+}
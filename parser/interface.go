@@ -8,5 +8,10 @@ import (
 
 var FileParser = participle.MustBuild[ast.File](
 	participle.Lexer(lexer.BooleanLexer),
-	participle.Elide("Whitespace"),
+	participle.Elide("Whitespace", "Comment"),
+)
+
+var RuleParser = participle.MustBuild[ast.Rule](
+	participle.Lexer(lexer.BooleanLexer),
+	participle.Elide("Whitespace", "Comment"),
 )
@@ -483,3 +483,33 @@ func TestOneBinaryLiteralAndThenOneBinaryBooleanExpression(t *testing.T) {
 		assert.Equal(t, test.expectedSecond, res.Tail[0].Expr.Bool.Rest.Expr.Unary.Expr.Lit)
 	}
 }
+
+func TestDefinitionThenExpression(t *testing.T) {
+	input := "let carry := a and b\ncarry or c"
+	res, err := FileParser.ParseString("", input)
+	assert.NoError(t, err)
+	assert.Nil(t, res.Head.Bool)
+	assert.Equal(t, "carry", res.Head.Def.Name)
+	assert.Equal(t, "a", res.Head.Def.Expr.Unary.Expr.Var)
+	assert.Equal(t, lexer.AND_TEXT, res.Head.Def.Expr.Rest.Op)
+	assert.Len(t, res.Tail, 1)
+	assert.Nil(t, res.Tail[0].Expr.Def)
+	assert.Equal(t, "carry", res.Tail[0].Expr.Bool.Unary.Expr.Var)
+}
+
+func TestDefinitionMissingAssignFail(t *testing.T) {
+	input := "let carry a and b"
+	_, err := FileParser.ParseString("", input)
+	assert.EqualError(t, err, "1:11: unexpected token \"a\" (expected <assign> (Expr | Expr))")
+}
+
+func TestRuleDeclaration(t *testing.T) {
+	input := "rule demorgan: not (a and b) -> not a or not b\nnot (p and q)"
+	res, err := FileParser.ParseString("", input)
+	assert.NoError(t, err)
+	assert.Equal(t, "demorgan", res.Head.Rule.Name)
+	assert.Equal(t, lexer.NOT_TEXT, res.Head.Rule.Lhs.Unary.Ops[0].Op)
+	assert.Nil(t, res.Head.Rule.Lhs.Rest)
+	assert.Equal(t, lexer.OR_TEXT, res.Head.Rule.Rhs.Rest.Op)
+	assert.NotNil(t, res.Tail[0].Expr.Bool)
+}
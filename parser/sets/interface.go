@@ -0,0 +1,357 @@
+package sets
+
+import (
+	"fmt"
+	"strconv"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	arithAst "acornlang.dev/lang/types/ast/arith"
+	setsAst "acornlang.dev/lang/types/ast/sets"
+	"acornlang.dev/lang/types/ir"
+	"github.com/alecthomas/participle/v2"
+)
+
+// MAX_RANGE is the most elements a range such as `1..100` may have.
+const MAX_RANGE = 1 << 16
+
+// Regd. Parsing
+
+// ExprParser looks ahead as far as it needs to: `{x in D | P}` and `{x}`
+// only differ at their third token, and `(a in s)` and `(s) subset t`
+// only after the parenthesis closes.
+var ExprParser = participle.MustBuild[setsAst.Expr](
+	participle.Lexer(lexer.BooleanLexer),
+	participle.Elide("Whitespace", "Comment"),
+	participle.UseLookahead(participle.MaxLookahead),
+)
+
+// Regd. Evaluation
+
+// Env holds the named sets and the universe that complements are taken
+// in; without a universe, only the connectives that keep to the elements
+// of their operands apply.
+type Env struct {
+	Sets     map[string]types.Set
+	Universe *types.Set
+}
+
+// bound maps the variables of the enclosing builders to their elements.
+type bound map[string]string
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
+// EvalExpr evaluates to a Bool for a predicate and to a Set otherwise.
+func EvalExpr(expr *setsAst.Expr, env Env) boolean.EvalResult {
+	if expr == nil {
+		return boolean.EvalResult{Payload: types.Set{}, Err: fmt.Errorf("invalid set expression 'nil'")}
+	}
+	if expr.Pred != nil {
+		val, err := evalPredicate(expr.Pred, env, bound{})
+		return boolean.EvalResult{Pos: expr.Pos, Payload: types.Bool(val), Err: err}
+	}
+	val, err := EvalSet(expr.Set, env)
+	return boolean.EvalResult{Pos: expr.Pos, Payload: val, Err: err}
+}
+
+func EvalSet(expr *setsAst.SetExpr, env Env) (types.Set, error) {
+	return evalSet(expr, env, bound{})
+}
+
+// connective gives the truth table of a binary connective; union and
+// intersection are or and and.
+func connective(pos types.Position, spelling string) (func(a bool, b bool) bool, error) {
+	switch spelling {
+	case lexer.UNION_TEXT:
+		spelling = lexer.OR_TEXT
+	case lexer.INTERSECT_TEXT:
+		spelling = lexer.AND_TEXT
+	}
+	op, ok := ir.ParseOp(spelling)
+	if _, hasTable := op.Table(); !ok || !hasTable {
+		return nil, errorAt(pos, "'%s' does not apply to sets", spelling)
+	}
+	return op.Apply, nil
+}
+
+func unaryOp(expr *setsAst.UnaryOp) (func(a bool) bool, error) {
+	op, ok := ir.ParseOp(expr.Op)
+	if !ok || !op.IsUnary() || op.IsTemporal() {
+		return nil, errorAt(expr.Pos, "'%s' does not apply to sets", expr.Op)
+	}
+	return op.ApplyUnary, nil
+}
+
+func evalPredicate(expr *setsAst.Predicate, env Env, vars bound) (bool, error) {
+	left, err := evalPredUnary(expr.Unary, env, vars)
+	if err != nil || expr.Rest == nil {
+		return left, err
+	}
+	right, err := evalPredicate(expr.Rest.Pred, env, vars)
+	if err != nil {
+		return false, err
+	}
+	apply, err := connective(expr.Rest.Pos, expr.Rest.Op)
+	if err != nil {
+		return false, err
+	}
+	return apply(left, right), nil
+}
+
+func evalPredUnary(expr *setsAst.PredUnary, env Env, vars bound) (bool, error) {
+	acc, err := evalAtom(expr.Atom, env, vars)
+	if err != nil {
+		return false, err
+	}
+	for idx := len(expr.Ops) - 1; idx >= 0; idx-- {
+		apply, err := unaryOp(&expr.Ops[idx])
+		if err != nil {
+			return false, err
+		}
+		acc = apply(acc)
+	}
+	return acc, nil
+}
+
+func evalAtom(expr *setsAst.Atom, env Env, vars bound) (bool, error) {
+	switch {
+	case expr.Cmp != nil:
+		return evalComparison(expr.Cmp, vars)
+	case expr.Member != nil:
+		elem, err := element(expr.Member.Elem, vars)
+		if err != nil {
+			return false, err
+		}
+		set, err := evalSetUnary(expr.Member.Set, env, vars)
+		if err != nil {
+			return false, err
+		}
+		return set.Has(elem), nil
+	case expr.Subset != nil:
+		left, err := evalSetUnary(expr.Subset.Left, env, vars)
+		if err != nil {
+			return false, err
+		}
+		right, err := evalSetUnary(expr.Subset.Right, env, vars)
+		if err != nil {
+			return false, err
+		}
+		for _, elem := range left.Elems {
+			if !right.Has(elem) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case expr.Paren != nil:
+		return evalPredicate(expr.Paren.Pred, env, vars)
+	default:
+		return expr.Lit == lexer.TRUE, nil
+	}
+}
+
+// evalComparison compares two lone elements as elements, so `x == red`
+// works for symbols; anything else is compared as integers.
+func evalComparison(expr *arithAst.Comparison, vars bound) (bool, error) {
+	left, leftOk := loneElement(expr.Left, vars)
+	right, rightOk := loneElement(expr.Right, vars)
+	if leftOk && rightOk {
+		switch expr.Op {
+		case lexer.EQUALS_SYMB:
+			return left == right, nil
+		case lexer.NOT_EQUALS_SYMB:
+			return left != right, nil
+		}
+	}
+	env := arith.Env{}
+	for _, name := range arith.Vars(expr) {
+		elem, ok := vars[name]
+		if !ok {
+			continue
+		}
+		val, err := strconv.ParseInt(elem, 10, 64)
+		if err != nil {
+			return false, errorAt(expr.Pos, "'%s' is %s, not an integer", name, elem)
+		}
+		env[name] = val
+	}
+	val, err := arith.EvalComparison(expr, env)
+	return bool(val), err
+}
+
+func loneElement(expr *arithAst.Sum, vars bound) (string, bool) {
+	if len(expr.Rest) != 0 || len(expr.Head.Rest) != 0 || expr.Head.Head.Paren != nil {
+		return "", false
+	}
+	factor := expr.Head.Head
+	if factor.Var != "" {
+		if factor.Neg {
+			return "", false
+		}
+		elem, err := element(&setsAst.Element{Sym: factor.Var}, vars)
+		return elem, err == nil
+	}
+	lit := factor.Int
+	if factor.Neg {
+		lit = lexer.MINUS_SYMB + lit
+	}
+	elem, err := element(&setsAst.Element{Pos: factor.Pos, Int: lit}, vars)
+	return elem, err == nil
+}
+
+// element gives the canonical text of an element: integers without
+// leading zeros, and bound variables replaced by their elements.
+func element(expr *setsAst.Element, vars bound) (string, error) {
+	if expr.Sym != "" {
+		if elem, ok := vars[expr.Sym]; ok {
+			return elem, nil
+		}
+		return expr.Sym, nil
+	}
+	val, err := strconv.ParseInt(expr.Int, 10, 64)
+	if err != nil {
+		return "", errorAt(expr.Pos, "integer '%s' is too large", expr.Int)
+	}
+	return strconv.FormatInt(val, 10), nil
+}
+
+// evalSet lifts a connective to sets element by element. One that holds
+// when neither side does, such as `nor`, needs a universe to pick its
+// elements from.
+func evalSet(expr *setsAst.SetExpr, env Env, vars bound) (types.Set, error) {
+	left, err := evalSetUnary(expr.Unary, env, vars)
+	if err != nil || expr.Rest == nil {
+		return left, err
+	}
+	right, err := evalSet(expr.Rest.Expr, env, vars)
+	if err != nil {
+		return types.Set{}, err
+	}
+	apply, err := connective(expr.Rest.Pos, expr.Rest.Op)
+	if err != nil {
+		return types.Set{}, err
+	}
+	candidates := types.NewSet(append(append([]string{}, left.Elems...), right.Elems...))
+	if apply(false, false) {
+		candidates, err = universe(expr.Rest.Pos, expr.Rest.Op, env, left, right)
+		if err != nil {
+			return types.Set{}, err
+		}
+	}
+	acc := []string{}
+	for _, elem := range candidates.Elems {
+		if apply(left.Has(elem), right.Has(elem)) {
+			acc = append(acc, elem)
+		}
+	}
+	return types.NewSet(acc), nil
+}
+
+func evalSetUnary(expr *setsAst.SetUnary, env Env, vars bound) (types.Set, error) {
+	acc, err := evalSetPrimary(expr.Primary, env, vars)
+	if err != nil {
+		return types.Set{}, err
+	}
+	for idx := len(expr.Ops) - 1; idx >= 0; idx-- {
+		op := &expr.Ops[idx]
+		apply, err := unaryOp(op)
+		if err != nil {
+			return types.Set{}, err
+		}
+		candidates := acc
+		if apply(false) {
+			candidates, err = universe(op.Pos, op.Op, env, acc)
+			if err != nil {
+				return types.Set{}, err
+			}
+		}
+		elems := []string{}
+		for _, elem := range candidates.Elems {
+			if apply(acc.Has(elem)) {
+				elems = append(elems, elem)
+			}
+		}
+		acc = types.NewSet(elems)
+	}
+	return acc, nil
+}
+
+func universe(pos types.Position, op string, env Env, operands ...types.Set) (types.Set, error) {
+	if env.Universe == nil {
+		return types.Set{}, errorAt(pos, "'%s' needs a universe to complement in, declare one with :universe", op)
+	}
+	for _, operand := range operands {
+		for _, elem := range operand.Elems {
+			if !env.Universe.Has(elem) {
+				return types.Set{}, errorAt(pos, "'%s' is not in the universe %s", elem, env.Universe)
+			}
+		}
+	}
+	return *env.Universe, nil
+}
+
+func evalSetPrimary(expr *setsAst.SetPrimary, env Env, vars bound) (types.Set, error) {
+	switch {
+	case expr.Builder != nil:
+		return evalBuilder(expr.Builder, env, vars)
+	case expr.Lit != nil:
+		acc := []string{}
+		for _, elemExpr := range expr.Lit.Elems {
+			elem, err := element(elemExpr, vars)
+			if err != nil {
+				return types.Set{}, err
+			}
+			acc = append(acc, elem)
+		}
+		return types.NewSet(acc), nil
+	case expr.Range != nil:
+		return evalRange(expr.Range)
+	case expr.Paren != nil:
+		return evalSet(expr.Paren.Expr, env, vars)
+	default:
+		set, ok := env.Sets[expr.Var]
+		if !ok {
+			return types.Set{}, errorAt(expr.Pos, "unbound set '%s'", expr.Var)
+		}
+		return set, nil
+	}
+}
+
+func evalBuilder(expr *setsAst.Builder, env Env, vars bound) (types.Set, error) {
+	domain, err := evalSetUnary(expr.Domain, env, vars)
+	if err != nil {
+		return types.Set{}, err
+	}
+	inner := bound{}
+	for name, elem := range vars {
+		inner[name] = elem
+	}
+	acc := []string{}
+	for _, elem := range domain.Elems {
+		inner[expr.Var] = elem
+		holds, err := evalPredicate(expr.Pred, env, inner)
+		if err != nil {
+			return types.Set{}, err
+		}
+		if holds {
+			acc = append(acc, elem)
+		}
+	}
+	return types.NewSet(acc), nil
+}
+
+func evalRange(expr *setsAst.RangeLiteral) (types.Set, error) {
+	lo, loErr := strconv.ParseInt(expr.Lo, 10, 64)
+	hi, hiErr := strconv.ParseInt(expr.Hi, 10, 64)
+	if loErr != nil || hiErr != nil || hi-lo >= MAX_RANGE {
+		return types.Set{}, errorAt(expr.Pos, "range '%s..%s' has more than %d elements", expr.Lo, expr.Hi, MAX_RANGE)
+	}
+	acc := []string{}
+	for val := lo; val <= hi; val++ {
+		acc = append(acc, strconv.FormatInt(val, 10))
+	}
+	return types.NewSet(acc), nil
+}
@@ -0,0 +1,80 @@
+package sets
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func evalSets(t *testing.T, input string, env Env) boolean.EvalResult {
+	expr, err := ExprParser.ParseString("", input)
+	assert.NoError(t, err, input)
+	return EvalExpr(expr, env)
+}
+
+func set(elems ...string) types.Set {
+	return types.NewSet(elems)
+}
+
+func TestEvalSets(t *testing.T) {
+	universe := set("red", "green", "blue", "yellow")
+	env := Env{
+		Sets:     map[string]types.Set{"warm": set("red", "yellow"), "primary": set("red", "green", "blue")},
+		Universe: &universe,
+	}
+	tests := []struct {
+		input    string
+		expected types.Value
+	}{
+		{"{c, a, b, a}", set("a", "b", "c")},
+		{"{}", set()},
+		{"warm union primary", set("red", "yellow", "green", "blue")},
+		{"warm intersect primary", set("red")},
+		{"warm and primary", set("red")},
+		{"warm inhibits primary", set("yellow")},
+		{"warm xor primary", set("yellow", "green", "blue")},
+		{"not warm", set("green", "blue")},
+		{"warm nor primary", set()},
+		{"{x in 1..10 | x mod 3 == 0}", set("3", "6", "9")},
+		{"{c in primary | c != red and not c in warm}", set("green", "blue")},
+		{"{x in -2..2 | x * x == 4}", set("-2", "2")},
+		{"red in warm", types.Bool(true)},
+		{"red in (warm inhibits primary)", types.Bool(false)},
+		{"{red} subset warm and not primary subset warm", types.Bool(true)},
+		{"(3 in 1..5) implies 7 in 1..5", types.Bool(false)},
+		{"{x in 1..3 | x > 1} subset {x in 1..3 | x >= 2}", types.Bool(true)},
+	}
+	for _, test := range tests {
+		res := evalSets(t, test.input, env)
+		assert.NoError(t, res.Err, test.input)
+		assert.Equal(t, test.expected, res.Payload, test.input)
+	}
+}
+
+func TestEvalSetErrors(t *testing.T) {
+	universe := set("a", "b")
+	tests := []struct {
+		input    string
+		universe *types.Set
+		expected string
+	}{
+		{"not {a}", nil, "1:1: 'not' needs a universe to complement in, declare one with :universe"},
+		{"{a} implies {b}", nil, "1:5: 'implies' needs a universe to complement in, declare one with :universe"},
+		{"{a} or {b}", nil, ""},
+		{"{a, c} nor {b}", &universe, "1:8: 'c' is not in the universe {a, b}"},
+		{"{a} until {b}", nil, "1:5: 'until' does not apply to sets"},
+		{"{x in {a, 1} | x > 0}", nil, "1:16: 'x' is a, not an integer"},
+		{"missing union {a}", nil, "1:1: unbound set 'missing'"},
+		{"{x in 1..100000 | True}", nil, "1:7: range '1..100000' has more than 65536 elements"},
+	}
+	for _, test := range tests {
+		res := evalSets(t, test.input, Env{Universe: test.universe})
+		if test.expected == "" {
+			assert.NoError(t, res.Err, test.input)
+			continue
+		}
+		assert.EqualError(t, res.Err, test.expected, test.input)
+	}
+}
@@ -0,0 +1,220 @@
+package strings
+
+import (
+	"fmt"
+	stdstrings "strings"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	stringsAst "acornlang.dev/lang/types/ast/strings"
+	"github.com/alecthomas/participle/v2"
+)
+
+// Regd. Parsing
+
+var ExprParser = participle.MustBuild[stringsAst.Expr](
+	participle.Lexer(lexer.BooleanLexer),
+	participle.Elide("Whitespace", "Comment"),
+)
+
+// Segment is a piece of a string literal: either Text, or an interpolated
+// Expr written as `{expr}`.
+type Segment struct {
+	Text string
+	Expr *booleanAst.Expr
+}
+
+var escapes = map[byte]byte{
+	'"':  '"',
+	'\\': '\\',
+	'n':  '\n',
+	't':  '\t',
+	'{':  '{',
+	'}':  '}',
+}
+
+func errorAt(pos types.Position, offset int, format string, args ...any) error {
+	return fmt.Errorf("%d:%d: %s", pos.Line, pos.Column+offset, fmt.Sprintf(format, args...))
+}
+
+// Segments decodes the escapes in lit and parses its interpolations.
+func Segments(lit *stringsAst.Literal) ([]Segment, error) {
+	raw := lit.Raw[1 : len(lit.Raw)-1]
+	acc := []Segment{}
+	var text stdstrings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			acc = append(acc, Segment{Text: text.String()})
+			text.Reset()
+		}
+	}
+	for idx := 0; idx < len(raw); idx++ {
+		// Offsets count the opening quote.
+		offset := idx + 1
+		switch raw[idx] {
+		case '\\':
+			if idx+1 == len(raw) {
+				return nil, errorAt(lit.Pos, offset, "unfinished escape")
+			}
+			unescaped, ok := escapes[raw[idx+1]]
+			if !ok {
+				return nil, errorAt(lit.Pos, offset, "invalid escape '\\%c'", raw[idx+1])
+			}
+			text.WriteByte(unescaped)
+			idx++
+		case '{':
+			end := stdstrings.IndexByte(raw[idx:], '}')
+			if end == -1 {
+				return nil, errorAt(lit.Pos, offset, "unclosed '{' in string")
+			}
+			source := raw[idx+1 : idx+end]
+			expr, err := boolean.ExprParser.ParseString("", source)
+			if err != nil {
+				return nil, errorAt(lit.Pos, offset, "invalid interpolation '{%s}'", source)
+			}
+			flush()
+			acc = append(acc, Segment{Expr: expr})
+			idx += end
+		case '}':
+			return nil, errorAt(lit.Pos, offset, "unmatched '}' in string, write \\} for a brace")
+		default:
+			text.WriteByte(raw[idx])
+		}
+	}
+	flush()
+	return acc, nil
+}
+
+// Regd. Evaluation
+
+// Env holds the values names can refer to: strings by name from Strings,
+// and everything else through Bools.
+type Env struct {
+	Bools   boolean.Env
+	Strings map[string]string
+}
+
+func errorEvalResult(pos types.Position, err error) boolean.EvalResult {
+	return boolean.EvalResult{
+		Pos:     pos,
+		Payload: types.String(""),
+		Err:     err,
+	}
+}
+
+func successEvalResult(pos types.Position, payload types.Value) boolean.EvalResult {
+	return boolean.EvalResult{
+		Pos:     pos,
+		Payload: payload,
+		Err:     nil,
+	}
+}
+
+// EvalExpr evaluates to a String, or to a Bool when expr ends in a
+// comparison.
+func EvalExpr(expr *stringsAst.Expr, env Env) boolean.EvalResult {
+	if expr == nil {
+		return errorEvalResult(types.Position{}, fmt.Errorf("invalid string expression 'nil'"))
+	}
+	acc, err := evalOperand(expr.Head, env)
+	if err != nil {
+		return errorEvalResult(expr.Pos, err)
+	}
+	rest, err := evalConcat(expr.Rest, env)
+	if err != nil {
+		return errorEvalResult(expr.Pos, err)
+	}
+	acc += rest
+	if expr.Compare == nil {
+		return successEvalResult(expr.Pos, types.String(acc))
+	}
+	right, err := evalOperand(expr.Compare.Right.Head, env)
+	if err != nil {
+		return errorEvalResult(expr.Compare.Pos, err)
+	}
+	rest, err = evalConcat(expr.Compare.Right.Rest, env)
+	if err != nil {
+		return errorEvalResult(expr.Compare.Pos, err)
+	}
+	right += rest
+	switch expr.Compare.Op {
+	case lexer.EQUALS_SYMB:
+		return successEvalResult(expr.Pos, types.Bool(acc == right))
+	case lexer.NOT_EQUALS_SYMB:
+		return successEvalResult(expr.Pos, types.Bool(acc != right))
+	default:
+		return errorEvalResult(expr.Compare.Pos, fmt.Errorf("invalid comparison '%s'", expr.Compare.Op))
+	}
+}
+
+func evalConcat(rests []stringsAst.ConcatRest, env Env) (string, error) {
+	var acc stdstrings.Builder
+	for _, rest := range rests {
+		operand, err := evalOperand(rest.Operand, env)
+		if err != nil {
+			return "", err
+		}
+		acc.WriteString(operand)
+	}
+	return acc.String(), nil
+}
+
+func evalOperand(operand *stringsAst.Operand, env Env) (string, error) {
+	switch {
+	case operand.Lit != nil:
+		return evalLiteral(operand.Lit, env)
+	case operand.Paren != nil:
+		res := EvalExpr(operand.Paren.Expr, env)
+		if res.Err != nil {
+			return "", res.Err
+		}
+		str, ok := res.Payload.(types.String)
+		if !ok {
+			return "", fmt.Errorf("%d:%d: cannot concatenate %s", operand.Pos.Line, operand.Pos.Column, res.Payload.Type())
+		}
+		return string(str), nil
+	default:
+		str, ok := env.Strings[operand.Var]
+		if !ok {
+			return "", fmt.Errorf("%d:%d: unbound string '%s'", operand.Pos.Line, operand.Pos.Column, operand.Var)
+		}
+		return str, nil
+	}
+}
+
+// evalLiteral interpolates every `{expr}`: a name bound to a string gives
+// that string, anything else is evaluated as a boolean expression.
+func evalLiteral(lit *stringsAst.Literal, env Env) (string, error) {
+	segments, err := Segments(lit)
+	if err != nil {
+		return "", err
+	}
+	var acc stdstrings.Builder
+	for _, segment := range segments {
+		if segment.Expr == nil {
+			acc.WriteString(segment.Text)
+			continue
+		}
+		if name, ok := bareVar(segment.Expr); ok {
+			if str, ok := env.Strings[name]; ok {
+				acc.WriteString(str)
+				continue
+			}
+		}
+		res := boolean.EvalExpr(segment.Expr, env.Bools)
+		if res.Err != nil {
+			return "", fmt.Errorf("%d:%d: in interpolation: %s", lit.Pos.Line, lit.Pos.Column, res.Err.Error())
+		}
+		acc.WriteString(res.Payload.String())
+	}
+	return acc.String(), nil
+}
+
+func bareVar(expr *booleanAst.Expr) (string, bool) {
+	if expr.Rest != nil || len(expr.Unary.Ops) != 0 || expr.Unary.Expr.Var == "" {
+		return "", false
+	}
+	return expr.Unary.Expr.Var, true
+}
@@ -0,0 +1,81 @@
+package strings
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func evalString(t *testing.T, input string, env Env) boolean.EvalResult {
+	expr, err := ExprParser.ParseString("", input)
+	assert.NoError(t, err, input)
+	return EvalExpr(expr, env)
+}
+
+func TestEvalStrings(t *testing.T) {
+	env := Env{
+		Bools:   boolean.Env{"a": true, "b": false},
+		Strings: map[string]string{"name": "half adder"},
+	}
+	tests := []struct {
+		input    string
+		expected types.Value
+	}{
+		{`"hello"`, types.String("hello")},
+		{`"tab\there \"quoted\" \\ \{braces\}"`, types.String("tab\there \"quoted\" \\ {braces}")},
+		{`"carry: {a and b}, sum: {a xor b}"`, types.String("carry: False, sum: True")},
+		{`"{name}" ++ " ok"`, types.String("half adder ok")},
+		{`"the " ++ name ++ ("!" ++ "!")`, types.String("the half adder!!")},
+		{`"{a}" == "True"`, types.Bool(true)},
+		{`"x" != "x" ++ ""`, types.Bool(false)},
+		{`"half adder" == name`, types.Bool(true)},
+		{`name ++ "!"`, types.String("half adder!")},
+		{`name == "half adder"`, types.Bool(true)},
+		{`("{b}") ++ name`, types.String("Falsehalf adder")},
+	}
+	for _, test := range tests {
+		res := evalString(t, test.input, env)
+		assert.NoError(t, res.Err, test.input)
+		assert.Equal(t, test.expected, res.Payload, test.input)
+	}
+}
+
+func TestEvalStringErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"bad \q"`, "1:6: invalid escape '\\q'"},
+		{`"open {a and"`, "1:7: unclosed '{' in string"},
+		{`"close }"`, "1:8: unmatched '}' in string, write \\} for a brace"},
+		{`"{a and}"`, "1:2: invalid interpolation '{a and}'"},
+		{`"{c}"`, "1:1: in interpolation: unbound variable 'c'"},
+		{`"x" ++ y`, "1:8: unbound string 'y'"},
+	}
+	for _, test := range tests {
+		res := evalString(t, test.input, Env{Bools: boolean.Env{"a": true}})
+		assert.EqualError(t, res.Err, test.expected, test.input)
+	}
+}
+
+func TestNamesAloneAreNotStrings(t *testing.T) {
+	for _, input := range []string{"name", "a and b", "x == y", "x == 1", "(a)"} {
+		_, err := ExprParser.ParseString("", input)
+		assert.Error(t, err, input)
+	}
+	_, err := ExprParser.ParseString("", `"x" ++`)
+	assert.EqualError(t, err, `1:5: unexpected token "++"`)
+}
+
+func TestSegments(t *testing.T) {
+	expr, err := ExprParser.ParseString("", `"p = {p}!"`)
+	assert.NoError(t, err)
+	segments, err := Segments(expr.Head.Lit)
+	assert.NoError(t, err)
+	assert.Len(t, segments, 3)
+	assert.Equal(t, "p = ", segments[0].Text)
+	assert.Equal(t, "p", segments[1].Expr.Unary.Expr.Var)
+	assert.Equal(t, "!", segments[2].Text)
+}
@@ -0,0 +1,217 @@
+package english
+
+import (
+	"strings"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/arith"
+	"acornlang.dev/lang/types"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ir"
+	participleLexer "github.com/alecthomas/participle/v2/lexer"
+)
+
+// Regd. Operators
+
+// binaryForms reads `a op b` with %[1]s for a and %[2]s for b. The
+// correlatives ("both", "neither", "if ... then") keep the reading
+// unambiguous without brackets on the left.
+var binaryForms = map[ir.Op]string{
+	ir.NAND:         "not both %[1]s and %[2]s",
+	ir.NOR:          "neither %[1]s nor %[2]s",
+	ir.IFF:          "%[1]s if and only if %[2]s",
+	ir.XOR:          "exactly one of %[1]s and %[2]s",
+	ir.IMPLIES:      "if %[1]s then %[2]s",
+	ir.IMPLIED_BY:   "%[1]s if %[2]s",
+	ir.INHIBITS:     "%[1]s unless %[2]s",
+	ir.INHIBITED_BY: "not %[1]s but %[2]s",
+	ir.LEFT:         "%[1]s regardless of %[2]s",
+	ir.RIGHT:        "%[2]s regardless of %[1]s",
+	ir.NOT_LEFT:     "not %[1]s regardless of %[2]s",
+	ir.NOT_RIGHT:    "not %[2]s regardless of %[1]s",
+	ir.UNTIL:        "%[1]s until %[2]s",
+	ir.RELEASE:      "%[1]s releases %[2]s",
+}
+
+// unaryPrefixes read `op a` as the prefix followed by a. nullify and
+// truify end in "regardless of", which swallows whatever follows, so
+// their readings count as compound.
+var unaryPrefixes = map[ir.Op]string{
+	ir.NOT:      "not ",
+	ir.ID:       "",
+	ir.NULLIFY:  "false regardless of ",
+	ir.TRUIFY:   "true regardless of ",
+	ir.NEXT:     "next ",
+	ir.GLOBALLY: "always ",
+	ir.FINALLY:  "eventually ",
+}
+
+var relations = map[string]string{
+	lexer.LESS_SYMB:          "is less than",
+	lexer.LESS_EQUAL_SYMB:    "is at most",
+	lexer.GREATER_SYMB:       "is greater than",
+	lexer.GREATER_EQUAL_SYMB: "is at least",
+	lexer.EQUALS_SYMB:        "equals",
+	lexer.NOT_EQUALS_SYMB:    "does not equal",
+}
+
+// Regd. Rendering
+
+// Render reads expr out in English: `~(p /\ q)` is "not both p and q" and
+// `p /=> q` is "p unless q". Brackets appear only where an operand that
+// is itself compound would otherwise be ambiguous. A tree the parser did
+// not build may not lower, and reads as nothing.
+func Render(expr *booleanAst.Expr) string {
+	node, err := ir.Lower(expr)
+	if err != nil {
+		return ""
+	}
+	return RenderNode(node)
+}
+
+func RenderNode(node ir.Node) string {
+	text, _ := read(node)
+	return text
+}
+
+// read reads node and reports whether the reading is compound, that
+// is, needs brackets when it is an operand.
+func read(node ir.Node) (string, bool) {
+	switch n := node.(type) {
+	case *ir.Binary:
+		return readBinary(n)
+	case *ir.Unary:
+		return readUnary(n)
+	case *ir.Cmp:
+		relation, ok := relations[n.Cmp.Op]
+		if !ok {
+			relation = n.Cmp.Op
+		}
+		return arith.FormatSum(n.Cmp.Left) + " " + relation + " " + arith.FormatSum(n.Cmp.Right), false
+	case *ir.Var:
+		return n.Name, false
+	case *ir.Degree:
+		return n.Text, false
+	case *ir.Const:
+		return strings.ToLower(types.Bool(n.Value).String()), false
+	default:
+		return "", false
+	}
+}
+
+func readBinary(n *ir.Binary) (string, bool) {
+	switch n.Op {
+	case ir.AND, ir.OR:
+		operands := []string{operand(n.X)}
+		right := n.Y
+		for next, ok := right.(*ir.Binary); ok && next.Op == n.Op; next, ok = right.(*ir.Binary) {
+			operands = append(operands, operand(next.X))
+			right = next.Y
+		}
+		operands = append(operands, operand(right))
+		return list(operands, n.Op.String()), true
+	case ir.IMPLIES:
+		// "then" closes the condition, so it only needs brackets around
+		// an "if" of its own.
+		condition, _ := read(n.X)
+		if left, ok := n.X.(*ir.Binary); ok && left.Op == ir.IMPLIES {
+			condition = "(" + condition + ")"
+		}
+		return fillForm(binaryForms[n.Op], condition, operand(n.Y)), true
+	}
+	form, ok := binaryForms[n.Op]
+	if !ok {
+		form = "%[1]s " + n.Op.String() + " %[2]s"
+	}
+	return fillForm(form, operand(n.X), operand(n.Y)), true
+}
+
+func operand(node ir.Node) string {
+	text, compound := read(node)
+	if compound {
+		return "(" + text + ")"
+	}
+	return text
+}
+
+func fillForm(form string, left string, right string) string {
+	return strings.NewReplacer("%[1]s", left, "%[2]s", right).Replace(form)
+}
+
+func list(operands []string, conj string) string {
+	last := len(operands) - 1
+	return strings.Join(operands[:last], ", ") + " " + conj + " " + operands[last]
+}
+
+// readUnary reads a `not` straight on an `and` or `or`, which was
+// bracketed in the source, as "not both" or "neither".
+func readUnary(n *ir.Unary) (string, bool) {
+	text, compound := read(n.X)
+	if inner, ok := n.X.(*ir.Binary); ok && n.Op == ir.NOT {
+		switch inner.Op {
+		case ir.AND:
+			return fillForm(binaryForms[ir.NAND], operand(inner.X), operand(inner.Y)), compound
+		case ir.OR:
+			return fillForm(binaryForms[ir.NOR], operand(inner.X), operand(inner.Y)), compound
+		}
+	}
+	if compound {
+		text = "(" + text + ")"
+	}
+	prefix, ok := unaryPrefixes[n.Op]
+	if !ok {
+		prefix = n.Op.String() + " "
+	}
+	return prefix + text, n.Op == ir.NULLIFY || n.Op == ir.TRUIFY
+}
+
+// Regd. Words
+
+// Words spells each operator of input as its text form, token by token,
+// for input that does not parse yet, such as a line still being typed.
+// Input the lexer rejects comes back unchanged.
+func Words(input string) string {
+	lex, err := lexer.BooleanLexer.LexString("", input)
+	if err != nil {
+		return input
+	}
+	symbols := lexer.BooleanLexer.Symbols()
+	ops := map[participleLexer.TokenType]bool{
+		symbols["BinaryOpString"]: true,
+		symbols["UnaryOpString"]:  true,
+	}
+	whitespace := symbols["Whitespace"]
+	var sb strings.Builder
+	// A symbol may touch its operands, `~p`, but a word may not.
+	afterSpace, pad := true, false
+	for {
+		token, err := lex.Next()
+		if err != nil {
+			return input
+		}
+		if token.EOF() {
+			return sb.String()
+		}
+		switch {
+		case token.Type == whitespace:
+			sb.WriteString(token.Value)
+			afterSpace, pad = true, false
+		case ops[token.Type]:
+			if !afterSpace {
+				sb.WriteString(" ")
+			}
+			if op, ok := ir.ParseOp(token.Value); ok {
+				sb.WriteString(op.String())
+			} else {
+				sb.WriteString(token.Value)
+			}
+			afterSpace, pad = false, true
+		default:
+			if pad {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(token.Value)
+			afterSpace, pad = false, false
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package english
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/parser/boolean"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"p", "p"},
+		{"~p /\\ True", "not p and true"},
+		{"((p))", "p"},
+		{"~(p /\\ q)", "not both p and q"},
+		{"not (p or q)", "neither p nor q"},
+		{"p nand q", "not both p and q"},
+		{"p ~\\/ q", "neither p nor q"},
+		{"p /=> q", "p unless q"},
+		{"p <=/ q", "not p but q"},
+		{"p => q", "if p then q"},
+		{"(p and q) => r", "if p and q then r"},
+		{"p and q => r", "p and (if q then r)"},
+		{"p => q and r", "if p then (q and r)"},
+		{"(p => q) => r", "if (if p then q) then r"},
+		{"p <= q", "p if q"},
+		{"p <=> q", "p if and only if q"},
+		{"p xnor q", "p if and only if q"},
+		{"p <~> q", "exactly one of p and q"},
+		{"p and q and r", "p, q and r"},
+		{"p or (q or r)", "p, q or r"},
+		{"(p or q) and r", "(p or q) and r"},
+		{"p and q or r", "p and (q or r)"},
+		{"~(p /\\ q) \\/ r", "(not both p and q) or r"},
+		{"~~(p /\\ q)", "not (not both p and q)"},
+		{"~(p => q)", "not (if p then q)"},
+		{"truify p and q", "(true regardless of p) and q"},
+		{"p <s q", "p regardless of q"},
+		{"p s> q", "q regardless of p"},
+		{"p </ q", "not p regardless of q"},
+		{"□(p => ◇q)", "always (if p then eventually q)"},
+		{"p 𝒰 q", "p until q"},
+		{"x + 1 > y and p", "x + 1 is greater than y and p"},
+		{"0.7 and p", "0.7 and p"},
+	}
+	for _, test := range tests {
+		expr, err := boolean.ExprParser.ParseString("", test.input)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, Render(expr), test.input)
+	}
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"p /\\ ", "p and "},
+		{"~p\\/q", "not p or q"},
+		{"~~(p ~/\\ q", "not not (p nand q"},
+		{"p <s q s> (r <=/ ", "p left q right (r is inhibited by "},
+		{":kmap p <~> q", ":kmap p xor q"},
+		{"p ∃", "p ∃"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, Words(test.input), test.input)
+	}
+}
@@ -0,0 +1,351 @@
+package math
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/arith"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ir"
+	participleLexer "github.com/alecthomas/participle/v2/lexer"
+)
+
+// Regd. Notations
+
+// Notation is a set of symbols for the operators and the precedence
+// levels they are read with; a higher level binds tighter. A notation
+// without levels groups the way the parser does: every binary operator at
+// one level, grouping to the right.
+type Notation struct {
+	Name      string
+	Symbols   map[ir.Op]string
+	Levels    map[ir.Op]int
+	True      string
+	False     string
+	Relations map[string]string
+	Ident     func(name string) string
+}
+
+var ASCII = Notation{
+	Name: "ascii",
+	Symbols: map[ir.Op]string{
+		ir.NOT:          lexer.NOT_SYMB,
+		ir.NULLIFY:      lexer.NULLIFY_TEXT,
+		ir.TRUIFY:       lexer.TRUIFY_TEXT,
+		ir.ID:           lexer.ID_TEXT,
+		ir.NEXT:         lexer.NEXT_TEXT,
+		ir.GLOBALLY:     lexer.GLOBALLY_TEXT,
+		ir.FINALLY:      lexer.FINALLY_TEXT,
+		ir.AND:          lexer.AND_SYMB,
+		ir.NAND:         lexer.NAND_SYMB,
+		ir.OR:           lexer.OR_SYMB,
+		ir.NOR:          lexer.NOR_SYMB,
+		ir.IFF:          lexer.XNOR_SYMB,
+		ir.XOR:          lexer.XOR_SYMB,
+		ir.INHIBITS:     lexer.INHIBITS_SYMB,
+		ir.INHIBITED_BY: lexer.INHIBITED_BY_SYMB,
+		ir.IMPLIES:      lexer.IMPLIES_SYMB,
+		ir.IMPLIED_BY:   lexer.IMPLIED_BY_SYMB,
+		ir.LEFT:         lexer.LEFT_SYMB,
+		ir.RIGHT:        lexer.RIGHT_SYMB,
+		ir.NOT_LEFT:     lexer.NOT_LEFT_SYMB,
+		ir.NOT_RIGHT:    lexer.NOT_RIGHT_SYMB,
+		ir.UNTIL:        lexer.UNTIL_TEXT,
+		ir.RELEASE:      lexer.RELEASE_TEXT,
+	},
+	True:  lexer.TRUE,
+	False: lexer.FALSE,
+	Relations: map[string]string{
+		lexer.LESS_SYMB:          lexer.LESS_SYMB,
+		lexer.LESS_EQUAL_SYMB:    lexer.LESS_EQUAL_SYMB,
+		lexer.GREATER_SYMB:       lexer.GREATER_SYMB,
+		lexer.GREATER_EQUAL_SYMB: lexer.GREATER_EQUAL_SYMB,
+		lexer.EQUALS_SYMB:        lexer.EQUALS_SYMB,
+		lexer.NOT_EQUALS_SYMB:    lexer.NOT_EQUALS_SYMB,
+	},
+	Ident: func(name string) string { return name },
+}
+
+// mathLevels is the usual reading of the connectives: negation, then
+// conjunction, disjunction, implication and equivalence. The temporal
+// binary operators bind tighter than any of them.
+var mathLevels = map[ir.Op]int{
+	ir.UNTIL:        6,
+	ir.RELEASE:      6,
+	ir.AND:          5,
+	ir.NAND:         5,
+	ir.OR:           4,
+	ir.NOR:          4,
+	ir.XOR:          4,
+	ir.INHIBITS:     3,
+	ir.INHIBITED_BY: 3,
+	ir.LEFT:         3,
+	ir.RIGHT:        3,
+	ir.NOT_LEFT:     3,
+	ir.NOT_RIGHT:    3,
+	ir.IMPLIES:      2,
+	ir.IMPLIED_BY:   2,
+	ir.IFF:          1,
+}
+
+var UNICODE = Notation{
+	Name: "unicode",
+	Symbols: map[ir.Op]string{
+		ir.NOT:          "¬",
+		ir.NULLIFY:      lexer.NULLIFY_TEXT,
+		ir.TRUIFY:       lexer.TRUIFY_TEXT,
+		ir.ID:           lexer.ID_TEXT,
+		ir.NEXT:         lexer.NEXT_SYMB,
+		ir.GLOBALLY:     lexer.GLOBALLY_SYMB,
+		ir.FINALLY:      lexer.FINALLY_SYMB,
+		ir.AND:          "∧",
+		ir.NAND:         "↑",
+		ir.OR:           "∨",
+		ir.NOR:          "↓",
+		ir.IFF:          "↔",
+		ir.XOR:          "⊕",
+		ir.INHIBITS:     "↛",
+		ir.INHIBITED_BY: "↚",
+		ir.IMPLIES:      "→",
+		ir.IMPLIED_BY:   "←",
+		ir.LEFT:         lexer.LEFT_TEXT,
+		ir.RIGHT:        lexer.RIGHT_TEXT,
+		ir.NOT_LEFT:     lexer.NOT_LEFT_TEXT,
+		ir.NOT_RIGHT:    lexer.NOT_RIGHT_TEXT,
+		ir.UNTIL:        lexer.UNTIL_SYMB,
+		ir.RELEASE:      lexer.RELEASE_SYMB,
+	},
+	Levels: mathLevels,
+	True:   "⊤",
+	False:  "⊥",
+	Relations: map[string]string{
+		lexer.LESS_SYMB:          "<",
+		lexer.LESS_EQUAL_SYMB:    "≤",
+		lexer.GREATER_SYMB:       ">",
+		lexer.GREATER_EQUAL_SYMB: "≥",
+		lexer.EQUALS_SYMB:        "=",
+		lexer.NOT_EQUALS_SYMB:    "≠",
+	},
+	Ident: func(name string) string { return name },
+}
+
+var LATEX = Notation{
+	Name: "latex",
+	Symbols: map[ir.Op]string{
+		ir.NOT:          `\neg`,
+		ir.NULLIFY:      `\operatorname{nullify}`,
+		ir.TRUIFY:       `\operatorname{truify}`,
+		ir.ID:           `\operatorname{id}`,
+		ir.NEXT:         `\bigcirc`,
+		ir.GLOBALLY:     `\Box`,
+		ir.FINALLY:      `\Diamond`,
+		ir.AND:          `\land`,
+		ir.NAND:         `\uparrow`,
+		ir.OR:           `\lor`,
+		ir.NOR:          `\downarrow`,
+		ir.IFF:          `\leftrightarrow`,
+		ir.XOR:          `\oplus`,
+		ir.INHIBITS:     `\nrightarrow`,
+		ir.INHIBITED_BY: `\nleftarrow`,
+		ir.IMPLIES:      `\rightarrow`,
+		ir.IMPLIED_BY:   `\leftarrow`,
+		ir.LEFT:         `\mathbin{\mathrm{left}}`,
+		ir.RIGHT:        `\mathbin{\mathrm{right}}`,
+		ir.NOT_LEFT:     `\mathbin{\mathrm{not\ left}}`,
+		ir.NOT_RIGHT:    `\mathbin{\mathrm{not\ right}}`,
+		ir.UNTIL:        `\mathbin{\mathcal{U}}`,
+		ir.RELEASE:      `\mathbin{\mathcal{R}}`,
+	},
+	Levels: mathLevels,
+	True:   `\top`,
+	False:  `\bot`,
+	Relations: map[string]string{
+		lexer.LESS_SYMB:          "<",
+		lexer.LESS_EQUAL_SYMB:    `\leq`,
+		lexer.GREATER_SYMB:       ">",
+		lexer.GREATER_EQUAL_SYMB: `\geq`,
+		lexer.EQUALS_SYMB:        "=",
+		lexer.NOT_EQUALS_SYMB:    `\neq`,
+	},
+	// A name longer than a letter would read as a product of letters.
+	Ident: func(name string) string {
+		if len(name) == 1 {
+			return name
+		}
+		return `\mathit{` + strings.ReplaceAll(name, "_", `\_`) + `}`
+	},
+}
+
+var NOTATIONS = []Notation{ASCII, UNICODE, LATEX}
+
+func ParseNotation(name string) (Notation, error) {
+	for _, notation := range NOTATIONS {
+		if notation.Name == name {
+			return notation, nil
+		}
+	}
+	return Notation{}, fmt.Errorf("unknown notation '%s', expected ascii, unicode or latex", name)
+}
+
+// associative operators need no brackets around themselves.
+var associative = map[ir.Op]bool{
+	ir.AND: true,
+	ir.OR:  true,
+	ir.XOR: true,
+	ir.IFF: true,
+}
+
+// Regd. Rendering
+
+// node is expr with every leaf spelled: a leaf when it has no kids, a
+// unary operator with one kid, or a binary one with two.
+type node struct {
+	op   ir.Op
+	text string
+	kids []*node
+}
+
+// Render prints expr in the notation, with only the brackets its levels
+// need to keep the meaning of expr. A tree the parser did not build may
+// not lower, and prints as nothing.
+func (n Notation) Render(expr *booleanAst.Expr) string {
+	lowered, err := ir.Lower(expr)
+	if err != nil {
+		return ""
+	}
+	return n.RenderNode(lowered)
+}
+
+func (n Notation) RenderNode(expr ir.Node) string {
+	return n.write(n.spell(expr))
+}
+
+func (n Notation) spell(expr ir.Node) *node {
+	switch e := expr.(type) {
+	case *ir.Binary:
+		return &node{op: e.Op, kids: []*node{n.spell(e.X), n.spell(e.Y)}}
+	case *ir.Unary:
+		return &node{op: e.Op, kids: []*node{n.spell(e.X)}}
+	case *ir.Cmp:
+		relation, ok := n.Relations[e.Cmp.Op]
+		if !ok {
+			relation = e.Cmp.Op
+		}
+		return &node{text: arith.FormatSum(e.Cmp.Left) + " " + relation + " " + arith.FormatSum(e.Cmp.Right)}
+	case *ir.Var:
+		return &node{text: n.Ident(e.Name)}
+	case *ir.Degree:
+		return &node{text: e.Text}
+	case *ir.Const:
+		if e.Value {
+			return &node{text: n.True}
+		}
+		return &node{text: n.False}
+	default:
+		return &node{}
+	}
+}
+
+func (n Notation) symbol(op ir.Op) string {
+	if symbol, ok := n.Symbols[op]; ok {
+		return symbol
+	}
+	return op.String()
+}
+
+func (n Notation) write(expr *node) string {
+	switch len(expr.kids) {
+	case 0:
+		return expr.text
+	case 1:
+		symbol := n.symbol(expr.op)
+		operand := n.write(expr.kids[0])
+		if len(expr.kids[0].kids) == 2 {
+			operand = "(" + operand + ")"
+		}
+		// `~p` and `¬p`, but `\neg p` and `nullify p`.
+		if last := []rune(symbol); unicode.IsLetter(last[len(last)-1]) || strings.HasPrefix(symbol, `\`) {
+			return symbol + " " + operand
+		}
+		return symbol + operand
+	default:
+		left := n.operand(expr.kids[0], expr.op, false)
+		right := n.operand(expr.kids[1], expr.op, true)
+		return left + " " + n.symbol(expr.op) + " " + right
+	}
+}
+
+// operand brackets kid, an operand of the binary operator op, if it
+// needs them.
+func (n Notation) operand(kid *node, op ir.Op, right bool) string {
+	text := n.write(kid)
+	if n.needsBrackets(kid, op, right) {
+		return "(" + text + ")"
+	}
+	return text
+}
+
+// needsBrackets reports whether kid would otherwise be read with a
+// different grouping.
+func (n Notation) needsBrackets(kid *node, op ir.Op, right bool) bool {
+	if len(kid.kids) != 2 {
+		return false
+	}
+	if n.Levels == nil {
+		return !right
+	}
+	kidLevel, level := n.Levels[kid.op], n.Levels[op]
+	switch {
+	case kidLevel != level:
+		return kidLevel < level
+	case kid.op != op:
+		return true
+	case associative[op]:
+		return false
+	default:
+		// Implication groups to the right, as the parser does.
+		return !right || op != ir.IMPLIES
+	}
+}
+
+// Regd. Spelling
+
+// Spell spells each operator and truth value of input in the notation,
+// token by token, for input that does not parse yet, such as a line still
+// being typed. Input the lexer rejects comes back unchanged.
+func (n Notation) Spell(input string) string {
+	lex, err := lexer.BooleanLexer.LexString("", input)
+	if err != nil {
+		return input
+	}
+	symbols := lexer.BooleanLexer.Symbols()
+	ops := map[participleLexer.TokenType]bool{
+		symbols["BinaryOpString"]: true,
+		symbols["UnaryOpString"]:  true,
+	}
+	var sb strings.Builder
+	for {
+		token, err := lex.Next()
+		if err != nil {
+			return input
+		}
+		switch {
+		case token.EOF():
+			return sb.String()
+		case ops[token.Type]:
+			if op, ok := ir.ParseOp(token.Value); ok {
+				sb.WriteString(n.symbol(op))
+			} else {
+				sb.WriteString(token.Value)
+			}
+		case token.Type == symbols["LitString"] && token.Value == lexer.TRUE:
+			sb.WriteString(n.True)
+		case token.Type == symbols["LitString"]:
+			sb.WriteString(n.False)
+		default:
+			sb.WriteString(token.Value)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package math
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		input   string
+		ascii   string
+		unicode string
+		latex   string
+	}{
+		{"not p and q", "~p /\\ q", "¬p ∧ q", `\neg p \land q`},
+		{"(p and q) or r", "(p /\\ q) \\/ r", "p ∧ q ∨ r", `p \land q \lor r`},
+		{"p and q or r", "p /\\ q \\/ r", "p ∧ (q ∨ r)", `p \land (q \lor r)`},
+		{"((p)) and (q)", "p /\\ q", "p ∧ q", `p \land q`},
+		{"not (p or q)", "~(p \\/ q)", "¬(p ∨ q)", `\neg (p \lor q)`},
+		{"p => q => r", "p => q => r", "p → q → r", `p \rightarrow q \rightarrow r`},
+		{"(p => q) => r", "(p => q) => r", "(p → q) → r", `(p \rightarrow q) \rightarrow r`},
+		{"p iff q xnor r", "p <=> q <=> r", "p ↔ q ↔ r", `p \leftrightarrow q \leftrightarrow r`},
+		{"(p nand q) nand r", "(p ~/\\ q) ~/\\ r", "(p ↑ q) ↑ r", `(p \uparrow q) \uparrow r`},
+		{"p nand q nand r", "p ~/\\ q ~/\\ r", "p ↑ (q ↑ r)", `p \uparrow (q \uparrow r)`},
+		{"p and q => r or True", "p /\\ q => r \\/ True", "p ∧ (q → r ∨ ⊤)", `p \land (q \rightarrow r \lor \top)`},
+		{"□(p => ◇q)", "globally (p => finally q)", "□(p → ◇q)", `\Box (p \rightarrow \Diamond q)`},
+		{"p until q", "p until q", "p 𝒰 q", `p \mathbin{\mathcal{U}} q`},
+		{"p ℛ ○q", "p release next q", "p ℛ ○q", `p \mathbin{\mathcal{R}} \bigcirc q`},
+		{"nullify p", "nullify p", "nullify p", `\operatorname{nullify} p`},
+		{"x + 1 >= y", "x + 1 >= y", "x + 1 ≥ y", `x + 1 \geq y`},
+		{"is_on /\\ False", "is_on /\\ False", "is_on ∧ ⊥", `\mathit{is\_on} \land \bot`},
+	}
+	for _, test := range tests {
+		expr, err := boolean.ExprParser.ParseString("", test.input)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.ascii, ASCII.Render(expr), test.input)
+		assert.Equal(t, test.unicode, UNICODE.Render(expr), test.input)
+		assert.Equal(t, test.latex, LATEX.Render(expr), test.input)
+	}
+}
+
+// ASCII output goes back through the parser with the same meaning.
+func TestASCIIRoundTrip(t *testing.T) {
+	inputs := []string{
+		"(p or q) and not (r => p)",
+		"p and (q or r) xor (p nand (q nor r))",
+		"((p <=/ q) /=> r) <s (p s> q)",
+		"not not (p </ q) /> r",
+	}
+	vars := []string{"p", "q", "r"}
+	for _, input := range inputs {
+		expr, err := boolean.ExprParser.ParseString("", input)
+		assert.NoError(t, err, input)
+		printed, err := boolean.ExprParser.ParseString("", ASCII.Render(expr))
+		assert.NoError(t, err, input)
+		for minterm := 0; minterm < 1<<len(vars); minterm++ {
+			env := boolean.Env{}
+			for idx, name := range vars {
+				env[name] = minterm&(1<<idx) != 0
+			}
+			assert.Equal(t, boolean.EvalExpr(expr, env).Payload, boolean.EvalExpr(printed, env).Payload, input)
+			assert.IsType(t, types.Bool(true), boolean.EvalExpr(printed, env).Payload, input)
+		}
+	}
+}
+
+func TestSpell(t *testing.T) {
+	assert.Equal(t, "~ p /\\ ", ASCII.Spell("not p and "))
+	assert.Equal(t, "(p ↑ ⊤", UNICODE.Spell("(p nand True"))
+	assert.Equal(t, ":kmap p \\oplus q", LATEX.Spell(":kmap p xor q"))
+	assert.Equal(t, "p ∃", UNICODE.Spell("p ∃"))
+}
+
+func TestParseNotation(t *testing.T) {
+	notation, err := ParseNotation("latex")
+	assert.NoError(t, err)
+	assert.Equal(t, "latex", notation.Name)
+	_, err = ParseNotation("braille")
+	assert.EqualError(t, err, "unknown notation 'braille', expected ascii, unicode or latex")
+}
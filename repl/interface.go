@@ -1,24 +1,41 @@
 package repl
 
-import "fmt"
+import (
+	"fmt"
+
+	"acornlang.dev/lang/types"
+)
 
 const DEFAULT_INDENTATION uint = 0
 
+const DEFAULT_LOGIC string = "boolean"
+
 type Context interface {
 	ExprNum() uint
 	Scope() string
+	Logic() string
+	Rules() []string
+	Focus() string
+	Universe() string
+	Bindings() map[string]types.Value
 	BumpExprNum() Context
 }
 
 type ReplContext struct {
-	exprNum uint
-	scope   string
+	exprNum  uint
+	scope    string
+	logic    string
+	rules    []string
+	focus    string
+	universe string
+	bindings map[string]types.Value
 }
 
 func NewReplContext() *ReplContext {
 	ctx := ReplContext{
 		exprNum: 1,
 		scope:   "main",
+		logic:   DEFAULT_LOGIC,
 	}
 	return &ctx
 }
@@ -31,10 +48,72 @@ func (replCtx *ReplContext) Scope() string {
 	return replCtx.scope
 }
 
+// Logic names how expressions are evaluated: DEFAULT_LOGIC, or a fuzzy
+// t-norm family.
+func (replCtx *ReplContext) Logic() string {
+	return replCtx.logic
+}
+
+// Rules are the sources of the rewrite rules declared so far, in order.
+func (replCtx *ReplContext) Rules() []string {
+	return replCtx.rules
+}
+
+// Focus is the expression `:step` continues rewriting from.
+func (replCtx *ReplContext) Focus() string {
+	return replCtx.focus
+}
+
+// Universe is the set complements are taken in, as a set literal; empty
+// when none is declared.
+func (replCtx *ReplContext) Universe() string {
+	return replCtx.universe
+}
+
+// Bindings are the values `let` has given names so far.
+func (replCtx *ReplContext) Bindings() map[string]types.Value {
+	return replCtx.bindings
+}
+
 func (replCtx *ReplContext) BumpExprNum() *ReplContext {
-	ctx := ReplContext{
-		exprNum: replCtx.exprNum + 1,
-		scope:   replCtx.scope,
+	ctx := *replCtx
+	ctx.exprNum++
+	return &ctx
+}
+
+func (replCtx *ReplContext) WithLogic(logic string) *ReplContext {
+	ctx := *replCtx
+	ctx.logic = logic
+	return &ctx
+}
+
+func (replCtx *ReplContext) WithRules(rules []string) *ReplContext {
+	ctx := *replCtx
+	ctx.rules = rules
+	return &ctx
+}
+
+func (replCtx *ReplContext) WithFocus(focus string) *ReplContext {
+	ctx := *replCtx
+	ctx.focus = focus
+	return &ctx
+}
+
+func (replCtx *ReplContext) WithUniverse(universe string) *ReplContext {
+	ctx := *replCtx
+	ctx.universe = universe
+	return &ctx
+}
+
+// WithBinding binds name to val, replacing any earlier binding, and leaves
+// the receiver's bindings untouched.
+func (replCtx *ReplContext) WithBinding(name string, val types.Value) *ReplContext {
+	ctx := *replCtx
+	ctx.bindings = map[string]types.Value{name: val}
+	for other, otherVal := range replCtx.bindings {
+		if other != name {
+			ctx.bindings[other] = otherVal
+		}
 	}
 	return &ctx
 }
@@ -1 +1,24 @@
 package repl
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogicSurvivesBump(t *testing.T) {
+	ctx := NewReplContext()
+	assert.Equal(t, DEFAULT_LOGIC, ctx.Logic())
+	bumped := ctx.WithLogic("product").BumpExprNum()
+	assert.Equal(t, "product", bumped.Logic())
+	assert.Equal(t, uint(2), bumped.ExprNum())
+	assert.Equal(t, DEFAULT_LOGIC, ctx.Logic(), "WithLogic modified its receiver")
+}
+
+func TestBindingsAreCopied(t *testing.T) {
+	ctx := NewReplContext().WithBinding("a", types.Degree(0.3))
+	rebound := ctx.WithBinding("a", types.Bool(true)).WithBinding("s", types.String("x"))
+	assert.Equal(t, map[string]types.Value{"a": types.Degree(0.3)}, ctx.Bindings())
+	assert.Equal(t, map[string]types.Value{"a": types.Bool(true), "s": types.String("x")}, rebound.Bindings())
+}
@@ -0,0 +1,118 @@
+package arith
+
+import (
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/types"
+	"github.com/alecthomas/participle/v2"
+	participleLexer "github.com/alecthomas/participle/v2/lexer"
+)
+
+// Comparison relates two integer expressions and is a truth value. It
+// parses itself, see Parse.
+type Comparison struct {
+	Pos   types.Position `json:"pos"`
+	Left  *Sum
+	Op    string
+	Right *Sum
+}
+
+var sumParser = participle.MustBuild[Sum](
+	participle.Lexer(lexer.BooleanLexer),
+	participle.Elide("Whitespace", "Comment"),
+)
+
+var relations = map[string]bool{
+	lexer.LESS_SYMB:          true,
+	lexer.LESS_EQUAL_SYMB:    true,
+	lexer.GREATER_SYMB:       true,
+	lexer.GREATER_EQUAL_SYMB: true,
+	lexer.EQUALS_SYMB:        true,
+	lexer.NOT_EQUALS_SYMB:    true,
+}
+
+var decimalString = lexer.BooleanLexer.Symbols()["DecimalString"]
+
+// errDegreeOperand reports a truth degree on one side of a relation, as
+// in `x > 0.5`, instead of letting the line fail as illegal.
+func errDegreeOperand(degree *participleLexer.Token) error {
+	return participle.Errorf(degree.Pos, "'%s' is a truth degree, but comparisons take integers", degree.Value)
+}
+
+// Parse reads `sum relation sum`, or consumes nothing and returns
+// participle.NextMatch, so a boolean expression that merely starts like a
+// sum, such as `(p and q)`, is parsed as one.
+func (c *Comparison) Parse(lex *participleLexer.PeekingLexer) error {
+	start := lex.MakeCheckpoint()
+	noMatch := func() error {
+		lex.LoadCheckpoint(start)
+		return participle.NextMatch
+	}
+	pos := lex.Peek().Pos
+	if first := lex.Peek(); first.Type == decimalString {
+		lex.Next()
+		if !relations[lex.Peek().Value] {
+			return noMatch()
+		}
+		// Past the relation, so the error wins over reading the degree
+		// alone.
+		lex.Next()
+		return errDegreeOperand(first)
+	}
+	left, err := sumParser.ParseFromLexer(lex, participle.AllowTrailing(true))
+	if err != nil {
+		return noMatch()
+	}
+	op := lex.Peek()
+	if !relations[op.Value] {
+		return noMatch()
+	}
+	lex.Next()
+	if next := lex.Peek(); next.Type == decimalString {
+		return errDegreeOperand(next)
+	}
+	right, err := sumParser.ParseFromLexer(lex, participle.AllowTrailing(true))
+	if err != nil {
+		return noMatch()
+	}
+	*c = Comparison{Pos: types.Position(pos), Left: left, Op: op.Value, Right: right}
+	return nil
+}
+
+// Sum, Term and Factor give `*`, `/` and `mod` precedence over `+` and
+// `-`; operators of the same precedence group to the left.
+type Sum struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Head *Term          `parser:"@@"`
+	Rest []SumRest      `parser:"@@*"`
+}
+
+type SumRest struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Op   string         `parser:"@('+' | '-')"`
+	Term *Term          `parser:"@@"`
+}
+
+type Term struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Head *Factor        `parser:"@@"`
+	Rest []TermRest     `parser:"@@*"`
+}
+
+type TermRest struct {
+	Pos    types.Position `parser:"" json:"pos"`
+	Op     string         `parser:"@('*' | '/' | 'mod')"`
+	Factor *Factor        `parser:"@@"`
+}
+
+type Factor struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Neg   bool           `parser:"@'-'?"`
+	Int   string         `parser:"(@IntString"`
+	Var   string         `parser:"|@Ident"`
+	Paren *ParenExpr     `parser:"|@@)"`
+}
+
+type ParenExpr struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Expr *Sum           `parser:"'(' @@ ')'"`
+}
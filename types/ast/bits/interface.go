@@ -0,0 +1,64 @@
+package bits
+
+import (
+	"acornlang.dev/lang/types"
+)
+
+// Expr is a bit-vector expression, or `popcount(...)` of one, which is an
+// Int.
+type Expr struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Count *ParenExpr     `parser:"Popcount @@"`
+	Body  *BitwiseExpr   `parser:"|@@"`
+}
+
+// BitwiseExpr groups its connectives to the right like a boolean
+// expression does; each one applies bit by bit.
+type BitwiseExpr struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Unary *UnaryExpr     `parser:"@@"`
+	Rest  *BitwiseRest   `parser:"(@@)?"`
+}
+
+type BitwiseRest struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Op   string         `parser:"@BinaryOpString"`
+	Expr *BitwiseExpr   `parser:"@@"`
+}
+
+type UnaryExpr struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Ops  []UnaryOp      `parser:"@@*"`
+	Expr *ShiftExpr     `parser:"@@"`
+}
+
+type UnaryOp struct {
+	Pos types.Position `parser:"" json:"pos"`
+	Op  string         `parser:"@UnaryOpString"`
+}
+
+// ShiftExpr binds tighter than any connective: `not x << 1` shifts
+// before it complements.
+type ShiftExpr struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Head *PrimaryExpr   `parser:"@@"`
+	Rest []ShiftRest    `parser:"@@*"`
+}
+
+type ShiftRest struct {
+	Pos    types.Position `parser:"" json:"pos"`
+	Op     string         `parser:"@Shift"`
+	Amount string         `parser:"@IntString"`
+}
+
+type PrimaryExpr struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Lit   string         `parser:"@BitsString"`
+	Paren *ParenExpr     `parser:"|@@"`
+	Var   string         `parser:"|@Ident"`
+}
+
+type ParenExpr struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Expr *BitwiseExpr   `parser:"'(' @@ ')'"`
+}
@@ -2,38 +2,44 @@ package boolean
 
 import (
 	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ast/arith"
 )
 
 type Expr struct {
-	Pos   types.Position `parser:"", json:"pos"`
+	Pos   types.Position `parser:"" json:"pos"`
 	Unary *UnaryExpr     `parser:"@@"`
 	Rest  *ExprRest      `parser:"(@@)?"`
 }
 
 type UnaryExpr struct {
-	Pos  types.Position `parser:"", json:"pos"`
+	Pos  types.Position `parser:"" json:"pos"`
 	Ops  []UnaryOp      `parser:"@@*"`
 	Expr *PrimaryExpr   `parser:"@@"`
 }
 
 type UnaryOp struct {
-	Pos types.Position `parser:"", json:"pos"`
+	Pos types.Position `parser:"" json:"pos"`
 	Op  string         `parser:"@UnaryOpString"`
 }
 
 type ExprRest struct {
-	Pos  types.Position `parser:"", json:"pos"`
+	Pos  types.Position `parser:"" json:"pos"`
 	Op   string         `parser:"@BinaryOpString"`
 	Expr *Expr          `parser:"@@"`
 }
 
+// PrimaryExpr tries Cmp first: `(x + 1) > y` and `(p)` both start with
+// a parenthesis, and only a relation after it tells them apart.
 type PrimaryExpr struct {
-	Pos   types.Position `parser:"", json:"pos"`
-	Lit   string         `parser:"@LitString"`
-	Paren *ParenExpr     `parser:"|@@"`
+	Pos    types.Position    `parser:"" json:"pos"`
+	Cmp    *arith.Comparison `parser:"@@"`
+	Lit    string            `parser:"|@LitString"`
+	Paren  *ParenExpr        `parser:"|@@"`
+	Var    string            `parser:"|@Ident"`
+	Degree string            `parser:"|@DecimalString"`
 }
 
 type ParenExpr struct {
-	Pos  types.Position `parser:"", json:"pos"`
+	Pos  types.Position `parser:"" json:"pos"`
 	Expr *Expr          `parser:"'(' @@ ')'"`
 }
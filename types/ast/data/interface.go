@@ -0,0 +1,67 @@
+package data
+
+import (
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ast/boolean"
+)
+
+// Expr is a tuple, a list, a match, or a boolean expression as their
+// leaves. A parenthesis holding a comma is a tuple; one without is a
+// boolean parenthesis, or wraps a match.
+type Expr struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Match *Match         `parser:"@@"`
+	Tuple *Tuple         `parser:"|@@"`
+	List  *List          `parser:"|@@"`
+	Bool  *boolean.Expr  `parser:"|@@"`
+	Paren *ParenExpr     `parser:"|@@"`
+}
+
+type Tuple struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Elems []*Expr        `parser:"'(' @@ (',' @@)+ ')'"`
+}
+
+type List struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Elems []*Expr        `parser:"'[' (@@ (',' @@)*)? ']'"`
+}
+
+type ParenExpr struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Expr *Expr          `parser:"'(' @@ ')'"`
+}
+
+// Match tries its arms in order. An arm that is itself a match takes
+// every arm after it, so it needs parentheses unless it comes last.
+type Match struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Expr *Expr          `parser:"'match' @@ 'with' Bar?"`
+	Arms []*Arm         `parser:"@@ (Bar @@)*"`
+}
+
+type Arm struct {
+	Pos     types.Position `parser:"" json:"pos"`
+	Pattern *Pattern       `parser:"@@ Arrow"`
+	Body    *Expr          `parser:"@@"`
+}
+
+// Pattern matches a value of the same shape. A name matches anything and
+// is bound in the arm's body, except `_`, which binds nothing.
+type Pattern struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Tuple *TuplePattern  `parser:"@@"`
+	List  *ListPattern   `parser:"|@@"`
+	Lit   string         `parser:"|@LitString"`
+	Var   string         `parser:"|@Ident"`
+}
+
+type TuplePattern struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Elems []*Pattern     `parser:"'(' @@ (',' @@)+ ')'"`
+}
+
+type ListPattern struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Elems []*Pattern     `parser:"'[' (@@ (',' @@)*)? ']'"`
+}
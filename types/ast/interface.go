@@ -3,10 +3,13 @@ package ast
 import (
 	"acornlang.dev/lang/types"
 	"acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ast/strings"
 )
 
+// File may open with blank and comment lines.
 type File struct {
-	Pos        types.Position       `parser:"", json:"pos"`
+	Pos        types.Position       `parser:"" json:"pos"`
+	Leading    *ExprTerminator      `parser:"(@@)?"`
 	Head       *Expr                `parser:"@@"`
 	Tail       []TerminatorThenExpr `parser:"(@@)*"`
 	Terminator *ExprTerminator      `parser:"(@@)?"`
@@ -14,17 +17,37 @@ type File struct {
 }
 
 type Expr struct {
-	Pos  types.Position `parser:"", json:"pos"`
-	Bool *boolean.Expr  `parser:"@@"`
+	Pos  types.Position `parser:"" json:"pos"`
+	Def  *Definition    `parser:"@@"`
+	Rule *Rule          `parser:"|@@"`
+	Str  *strings.Expr  `parser:"|@@"`
+	Bool *boolean.Expr  `parser:"|@@"`
+}
+
+// Definition binds Name to a string when its value starts with a string
+// literal, and to a boolean expression otherwise.
+type Definition struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Name string         `parser:"'let' @Ident Assign"`
+	Str  *strings.Expr  `parser:"(@@"`
+	Expr *boolean.Expr  `parser:"|@@)"`
+}
+
+// Rule is a rewrite law; identifiers on either side are pattern variables.
+type Rule struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Name string         `parser:"'rule' @Ident Colon"`
+	Lhs  *boolean.Expr  `parser:"@@ Arrow"`
+	Rhs  *boolean.Expr  `parser:"@@"`
 }
 
 type TerminatorThenExpr struct {
-	Pos            types.Position  `parser:"", json:"pos"`
+	Pos            types.Position  `parser:"" json:"pos"`
 	ExprTerminator *ExprTerminator `parser:"@@"`
 	Expr           *Expr           `parser:"@@"`
 }
 
 type ExprTerminator struct {
-	Pos types.Position `parser:"", json:"pos"`
+	Pos types.Position `parser:"" json:"pos"`
 	Val []string       `parser:"@(DoubleSemicolon|Newline)+"`
 }
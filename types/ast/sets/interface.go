@@ -0,0 +1,129 @@
+package sets
+
+import (
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ast/arith"
+)
+
+// Expr is a predicate about sets and their elements, which is a truth
+// value, or else a set.
+type Expr struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Pred *Predicate     `parser:"@@"`
+	Set  *SetExpr       `parser:"|@@"`
+}
+
+// Predicate combines atoms with the boolean connectives, grouping to the
+// right like a boolean expression does.
+type Predicate struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Unary *PredUnary     `parser:"@@"`
+	Rest  *PredRest      `parser:"(@@)?"`
+}
+
+type PredRest struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Op   string         `parser:"@BinaryOpString"`
+	Pred *Predicate     `parser:"@@"`
+}
+
+type PredUnary struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Ops  []UnaryOp      `parser:"@@*"`
+	Atom *Atom          `parser:"@@"`
+}
+
+type UnaryOp struct {
+	Pos types.Position `parser:"" json:"pos"`
+	Op  string         `parser:"@UnaryOpString"`
+}
+
+// Atom compares elements with Cmp: `x == red` compares two elements and
+// `x mod 2 == 0` compares integers.
+type Atom struct {
+	Pos    types.Position    `parser:"" json:"pos"`
+	Cmp    *arith.Comparison `parser:"@@"`
+	Member *Member           `parser:"|@@"`
+	Subset *Subset           `parser:"|@@"`
+	Lit    string            `parser:"|@LitString"`
+	Paren  *ParenPredicate   `parser:"|@@"`
+}
+
+// Member and Subset take a single set on each side; a compound one needs
+// parentheses, as in `red in (warm or cool)`.
+type Member struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Elem *Element       `parser:"@@ In"`
+	Set  *SetUnary      `parser:"@@"`
+}
+
+type Subset struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Left  *SetUnary      `parser:"@@ Subset"`
+	Right *SetUnary      `parser:"@@"`
+}
+
+type ParenPredicate struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Pred *Predicate     `parser:"'(' @@ ')'"`
+}
+
+type SetExpr struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Unary *SetUnary      `parser:"@@"`
+	Rest  *SetRest       `parser:"(@@)?"`
+}
+
+type SetRest struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Op   string         `parser:"@(SetOp | BinaryOpString)"`
+	Expr *SetExpr       `parser:"@@"`
+}
+
+type SetUnary struct {
+	Pos     types.Position `parser:"" json:"pos"`
+	Ops     []UnaryOp      `parser:"@@*"`
+	Primary *SetPrimary    `parser:"@@"`
+}
+
+type SetPrimary struct {
+	Pos     types.Position `parser:"" json:"pos"`
+	Builder *Builder       `parser:"@@"`
+	Lit     *SetLiteral    `parser:"|@@"`
+	Range   *RangeLiteral  `parser:"|@@"`
+	Paren   *ParenSet      `parser:"|@@"`
+	Var     string         `parser:"|@Ident"`
+}
+
+// Builder is `{ x in D | P }`: the elements of D for which P holds.
+type Builder struct {
+	Pos    types.Position `parser:"" json:"pos"`
+	Var    string         `parser:"'{' @Ident In"`
+	Domain *SetUnary      `parser:"@@ Bar"`
+	Pred   *Predicate     `parser:"@@ '}'"`
+}
+
+type SetLiteral struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Elems []*Element     `parser:"'{' (@@ (',' @@)*)? '}'"`
+}
+
+// RangeLiteral is the set of integers from Lo to Hi, both included.
+type RangeLiteral struct {
+	Pos types.Position `parser:"" json:"pos"`
+	Lo  string         `parser:"@('-'? IntString) Range"`
+	Hi  string         `parser:"@('-'? IntString)"`
+}
+
+// Element is an integer or a symbol; a symbol bound by an enclosing
+// builder stands for the element it ranges over.
+type Element struct {
+	Pos types.Position `parser:"" json:"pos"`
+	Int string         `parser:"@('-'? IntString)"`
+	Sym string         `parser:"|@Ident"`
+}
+
+type ParenSet struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Expr *SetExpr       `parser:"'(' @@ ')'"`
+}
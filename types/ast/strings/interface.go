@@ -0,0 +1,101 @@
+package strings
+
+import (
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/types"
+	"github.com/alecthomas/participle/v2"
+	participleLexer "github.com/alecthomas/participle/v2/lexer"
+)
+
+// Expr is a concatenation, possibly compared with another. It parses
+// itself, see Parse.
+type Expr struct {
+	Pos     types.Position `json:"pos"`
+	Head    *Operand
+	Rest    []ConcatRest
+	Compare *CompareRest
+}
+
+// exprGrammar is the shape Expr is read with.
+type exprGrammar struct {
+	Pos     types.Position `parser:"" json:"pos"`
+	Head    *Operand       `parser:"@@"`
+	Rest    []ConcatRest   `parser:"@@*"`
+	Compare *CompareRest   `parser:"(@@)?"`
+}
+
+var exprParser = participle.MustBuild[exprGrammar](
+	participle.Lexer(lexer.BooleanLexer),
+	participle.Elide("Whitespace", "Comment"),
+)
+
+var stringLit = lexer.BooleanLexer.Symbols()["StringLit"]
+
+// Parse reads a string expression, or consumes nothing and returns
+// participle.NextMatch unless it holds a literal, a parenthesized string
+// or `++`, so a bare name, `x == y` included, stays a boolean or integer
+// expression. Past a leading literal its errors are reported.
+func (e *Expr) Parse(lex *participleLexer.PeekingLexer) error {
+	start := lex.MakeCheckpoint()
+	literal := lex.Peek().Type == stringLit
+	parsed, err := exprParser.ParseFromLexer(lex, participle.AllowTrailing(true))
+	if err != nil && literal {
+		return err
+	}
+	if err != nil || !parsed.isString() {
+		lex.LoadCheckpoint(start)
+		return participle.NextMatch
+	}
+	*e = Expr(*parsed)
+	return nil
+}
+
+func (e *exprGrammar) isString() bool {
+	if e.Head.isString() || len(e.Rest) != 0 {
+		return true
+	}
+	return e.Compare != nil && (e.Compare.Right.Head.isString() || len(e.Compare.Right.Rest) != 0)
+}
+
+type ConcatRest struct {
+	Pos     types.Position `parser:"" json:"pos"`
+	Op      string         `parser:"@Concat"`
+	Operand *Operand       `parser:"@@"`
+}
+
+type CompareRest struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Op    string         `parser:"@Compare"`
+	Right *Concat        `parser:"@@"`
+}
+
+// Concat is the right-hand side of a comparison.
+type Concat struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Head *Operand       `parser:"@@"`
+	Rest []ConcatRest   `parser:"@@*"`
+}
+
+// Operand is a literal, a name or a parenthesized string.
+type Operand struct {
+	Pos   types.Position `parser:"" json:"pos"`
+	Lit   *Literal       `parser:"@@"`
+	Var   string         `parser:"|@Ident"`
+	Paren *ParenExpr     `parser:"|@@"`
+}
+
+// Literal is a quoted string as written, escapes and `{...}`
+// interpolations included.
+type Literal struct {
+	Pos types.Position `parser:"" json:"pos"`
+	Raw string         `parser:"@StringLit"`
+}
+
+func (o *Operand) isString() bool {
+	return o.Lit != nil || o.Paren != nil
+}
+
+type ParenExpr struct {
+	Pos  types.Position `parser:"" json:"pos"`
+	Expr *Expr          `parser:"'(' @@ ')'"`
+}
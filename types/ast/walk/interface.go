@@ -0,0 +1,760 @@
+package walk
+
+import (
+	"fmt"
+
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ast"
+	"acornlang.dev/lang/types/ast/arith"
+	"acornlang.dev/lang/types/ast/bits"
+	"acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ast/data"
+	"acornlang.dev/lang/types/ast/sets"
+	"acornlang.dev/lang/types/ast/strings"
+)
+
+// Node is a pointer to a syntax tree node, never a nil pointer: an
+// ast.File or one of its statements, or a node of a boolean, integer,
+// string, bit-vector, set or data expression. Elements of slices, such as
+// the unary operators of a boolean.UnaryExpr, are visited through
+// pointers to them.
+type Node any
+
+// Regd. Traversal
+
+// Children lists the nodes directly under node in source order, leaving
+// out the missing ones; literals, names and operators are fields rather
+// than nodes. Interpolations inside a string literal are text until the
+// string is evaluated, so a strings.Literal has no children.
+func Children(node Node) []Node {
+	acc := []Node{}
+	switch n := node.(type) {
+	case *ast.File:
+		acc = add(acc, n.Leading)
+		acc = add(acc, n.Head)
+		acc = addAll(acc, n.Tail)
+		acc = add(acc, n.Terminator)
+	case *ast.TerminatorThenExpr:
+		acc = add(acc, n.ExprTerminator)
+		acc = add(acc, n.Expr)
+	case *ast.Expr:
+		acc = add(acc, n.Def)
+		acc = add(acc, n.Rule)
+		acc = add(acc, n.Str)
+		acc = add(acc, n.Bool)
+	case *ast.Definition:
+		acc = add(acc, n.Str)
+		acc = add(acc, n.Expr)
+	case *ast.Rule:
+		acc = add(acc, n.Lhs)
+		acc = add(acc, n.Rhs)
+	case *boolean.Expr:
+		acc = add(acc, n.Unary)
+		acc = add(acc, n.Rest)
+	case *boolean.UnaryExpr:
+		acc = addAll(acc, n.Ops)
+		acc = add(acc, n.Expr)
+	case *boolean.ExprRest:
+		acc = add(acc, n.Expr)
+	case *boolean.PrimaryExpr:
+		acc = add(acc, n.Cmp)
+		acc = add(acc, n.Paren)
+	case *boolean.ParenExpr:
+		acc = add(acc, n.Expr)
+	case *arith.Comparison:
+		acc = add(acc, n.Left)
+		acc = add(acc, n.Right)
+	case *arith.Sum:
+		acc = add(acc, n.Head)
+		acc = addAll(acc, n.Rest)
+	case *arith.SumRest:
+		acc = add(acc, n.Term)
+	case *arith.Term:
+		acc = add(acc, n.Head)
+		acc = addAll(acc, n.Rest)
+	case *arith.TermRest:
+		acc = add(acc, n.Factor)
+	case *arith.Factor:
+		acc = add(acc, n.Paren)
+	case *arith.ParenExpr:
+		acc = add(acc, n.Expr)
+	case *strings.Expr:
+		acc = add(acc, n.Head)
+		acc = addAll(acc, n.Rest)
+		acc = add(acc, n.Compare)
+	case *strings.ConcatRest:
+		acc = add(acc, n.Operand)
+	case *strings.CompareRest:
+		acc = add(acc, n.Right)
+	case *strings.Concat:
+		acc = add(acc, n.Head)
+		acc = addAll(acc, n.Rest)
+	case *strings.Operand:
+		acc = add(acc, n.Lit)
+		acc = add(acc, n.Paren)
+	case *strings.ParenExpr:
+		acc = add(acc, n.Expr)
+	case *bits.Expr:
+		acc = add(acc, n.Count)
+		acc = add(acc, n.Body)
+	case *bits.BitwiseExpr:
+		acc = add(acc, n.Unary)
+		acc = add(acc, n.Rest)
+	case *bits.BitwiseRest:
+		acc = add(acc, n.Expr)
+	case *bits.UnaryExpr:
+		acc = addAll(acc, n.Ops)
+		acc = add(acc, n.Expr)
+	case *bits.ShiftExpr:
+		acc = add(acc, n.Head)
+		acc = addAll(acc, n.Rest)
+	case *bits.PrimaryExpr:
+		acc = add(acc, n.Paren)
+	case *bits.ParenExpr:
+		acc = add(acc, n.Expr)
+	case *sets.Expr:
+		acc = add(acc, n.Pred)
+		acc = add(acc, n.Set)
+	case *sets.Predicate:
+		acc = add(acc, n.Unary)
+		acc = add(acc, n.Rest)
+	case *sets.PredRest:
+		acc = add(acc, n.Pred)
+	case *sets.PredUnary:
+		acc = addAll(acc, n.Ops)
+		acc = add(acc, n.Atom)
+	case *sets.Atom:
+		acc = add(acc, n.Cmp)
+		acc = add(acc, n.Member)
+		acc = add(acc, n.Subset)
+		acc = add(acc, n.Paren)
+	case *sets.Member:
+		acc = add(acc, n.Elem)
+		acc = add(acc, n.Set)
+	case *sets.Subset:
+		acc = add(acc, n.Left)
+		acc = add(acc, n.Right)
+	case *sets.ParenPredicate:
+		acc = add(acc, n.Pred)
+	case *sets.SetExpr:
+		acc = add(acc, n.Unary)
+		acc = add(acc, n.Rest)
+	case *sets.SetRest:
+		acc = add(acc, n.Expr)
+	case *sets.SetUnary:
+		acc = addAll(acc, n.Ops)
+		acc = add(acc, n.Primary)
+	case *sets.SetPrimary:
+		acc = add(acc, n.Builder)
+		acc = add(acc, n.Lit)
+		acc = add(acc, n.Range)
+		acc = add(acc, n.Paren)
+	case *sets.Builder:
+		acc = add(acc, n.Domain)
+		acc = add(acc, n.Pred)
+	case *sets.SetLiteral:
+		acc = addPtrs(acc, n.Elems)
+	case *sets.ParenSet:
+		acc = add(acc, n.Expr)
+	case *data.Expr:
+		acc = add(acc, n.Match)
+		acc = add(acc, n.Tuple)
+		acc = add(acc, n.List)
+		acc = add(acc, n.Bool)
+		acc = add(acc, n.Paren)
+	case *data.Tuple:
+		acc = addPtrs(acc, n.Elems)
+	case *data.List:
+		acc = addPtrs(acc, n.Elems)
+	case *data.ParenExpr:
+		acc = add(acc, n.Expr)
+	case *data.Match:
+		acc = add(acc, n.Expr)
+		acc = addPtrs(acc, n.Arms)
+	case *data.Arm:
+		acc = add(acc, n.Pattern)
+		acc = add(acc, n.Body)
+	case *data.Pattern:
+		acc = add(acc, n.Tuple)
+		acc = add(acc, n.List)
+	case *data.TuplePattern:
+		acc = addPtrs(acc, n.Elems)
+	case *data.ListPattern:
+		acc = addPtrs(acc, n.Elems)
+	}
+	return acc
+}
+
+// add appends kid unless it is missing; a nil *T would otherwise be a
+// non-nil Node.
+func add[T any](acc []Node, kid *T) []Node {
+	if kid == nil {
+		return acc
+	}
+	return append(acc, kid)
+}
+
+func addAll[T any](acc []Node, kids []T) []Node {
+	for idx := range kids {
+		acc = append(acc, &kids[idx])
+	}
+	return acc
+}
+
+func addPtrs[T any](acc []Node, kids []*T) []Node {
+	for _, kid := range kids {
+		acc = add(acc, kid)
+	}
+	return acc
+}
+
+// Visitor is called with every node Walk reaches; the Visitor it returns
+// visits the node's children, and none of them are visited when it is nil.
+type Visitor interface {
+	Visit(node Node) Visitor
+}
+
+// Walk visits node with v, then its children with the Visitor v returns,
+// and finally calls that Visitor with nil, as go/ast.Walk does.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+	for _, kid := range Children(node) {
+		Walk(v, kid)
+	}
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect calls f on node and, depth first, on every node under it, and
+// on nil after the children of a node; the children of a node are skipped
+// when f returns false for it.
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// Regd. Rewriting
+
+// Position is where node starts in the source.
+func Position(node Node) types.Position {
+	if pos := position(node); pos != nil {
+		return *pos
+	}
+	return types.Position{}
+}
+
+func position(node Node) *types.Position {
+	switch n := node.(type) {
+	case *ast.File:
+		return &n.Pos
+	case *ast.TerminatorThenExpr:
+		return &n.Pos
+	case *ast.ExprTerminator:
+		return &n.Pos
+	case *ast.Expr:
+		return &n.Pos
+	case *ast.Definition:
+		return &n.Pos
+	case *ast.Rule:
+		return &n.Pos
+	case *boolean.Expr:
+		return &n.Pos
+	case *boolean.UnaryExpr:
+		return &n.Pos
+	case *boolean.UnaryOp:
+		return &n.Pos
+	case *boolean.ExprRest:
+		return &n.Pos
+	case *boolean.PrimaryExpr:
+		return &n.Pos
+	case *boolean.ParenExpr:
+		return &n.Pos
+	case *arith.Comparison:
+		return &n.Pos
+	case *arith.Sum:
+		return &n.Pos
+	case *arith.SumRest:
+		return &n.Pos
+	case *arith.Term:
+		return &n.Pos
+	case *arith.TermRest:
+		return &n.Pos
+	case *arith.Factor:
+		return &n.Pos
+	case *arith.ParenExpr:
+		return &n.Pos
+	case *strings.Expr:
+		return &n.Pos
+	case *strings.ConcatRest:
+		return &n.Pos
+	case *strings.CompareRest:
+		return &n.Pos
+	case *strings.Concat:
+		return &n.Pos
+	case *strings.Operand:
+		return &n.Pos
+	case *strings.Literal:
+		return &n.Pos
+	case *strings.ParenExpr:
+		return &n.Pos
+	case *bits.Expr:
+		return &n.Pos
+	case *bits.BitwiseExpr:
+		return &n.Pos
+	case *bits.BitwiseRest:
+		return &n.Pos
+	case *bits.UnaryExpr:
+		return &n.Pos
+	case *bits.UnaryOp:
+		return &n.Pos
+	case *bits.ShiftExpr:
+		return &n.Pos
+	case *bits.ShiftRest:
+		return &n.Pos
+	case *bits.PrimaryExpr:
+		return &n.Pos
+	case *bits.ParenExpr:
+		return &n.Pos
+	case *sets.Expr:
+		return &n.Pos
+	case *sets.Predicate:
+		return &n.Pos
+	case *sets.PredRest:
+		return &n.Pos
+	case *sets.PredUnary:
+		return &n.Pos
+	case *sets.UnaryOp:
+		return &n.Pos
+	case *sets.Atom:
+		return &n.Pos
+	case *sets.Member:
+		return &n.Pos
+	case *sets.Subset:
+		return &n.Pos
+	case *sets.ParenPredicate:
+		return &n.Pos
+	case *sets.SetExpr:
+		return &n.Pos
+	case *sets.SetRest:
+		return &n.Pos
+	case *sets.SetUnary:
+		return &n.Pos
+	case *sets.SetPrimary:
+		return &n.Pos
+	case *sets.Builder:
+		return &n.Pos
+	case *sets.SetLiteral:
+		return &n.Pos
+	case *sets.RangeLiteral:
+		return &n.Pos
+	case *sets.Element:
+		return &n.Pos
+	case *sets.ParenSet:
+		return &n.Pos
+	case *data.Expr:
+		return &n.Pos
+	case *data.Tuple:
+		return &n.Pos
+	case *data.List:
+		return &n.Pos
+	case *data.ParenExpr:
+		return &n.Pos
+	case *data.Match:
+		return &n.Pos
+	case *data.Arm:
+		return &n.Pos
+	case *data.Pattern:
+		return &n.Pos
+	case *data.TuplePattern:
+		return &n.Pos
+	case *data.ListPattern:
+		return &n.Pos
+	}
+	return nil
+}
+
+// Rewrite gives a copy of root in which f has replaced every node,
+// children first, so f sees a node with its children already rewritten;
+// root itself is left as it is. f returns its argument to keep a node,
+// another node of the same type to replace it, or nil to drop it from
+// where it is optional: a missing Rest, or one less unary operator.
+// Dropping a node the grammar requires panics, as does replacing a node
+// with one of another type. A node f makes with a zero Pos is given the
+// position of the node it replaces, so errors about it still point into
+// the source.
+func Rewrite[T Node](root T, f func(Node) Node) T {
+	res := rewrite(Node(root), f)
+	if res == nil {
+		var zero T
+		return zero
+	}
+	return res.(T)
+}
+
+func rewrite(node Node, f func(Node) Node) Node {
+	var acc Node
+	switch n := node.(type) {
+	case *ast.File:
+		c := *n
+		c.Leading = rewriteOpt(c.Leading, f)
+		c.Head = rewriteKid(c.Head, f)
+		c.Tail = rewriteAll(c.Tail, f)
+		c.Terminator = rewriteOpt(c.Terminator, f)
+		acc = &c
+	case *ast.TerminatorThenExpr:
+		c := *n
+		c.ExprTerminator = rewriteKid(c.ExprTerminator, f)
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *ast.ExprTerminator:
+		c := *n
+		c.Val = append([]string(nil), c.Val...)
+		acc = &c
+	case *ast.Expr:
+		c := *n
+		c.Def = rewriteKid(c.Def, f)
+		c.Rule = rewriteKid(c.Rule, f)
+		c.Str = rewriteKid(c.Str, f)
+		c.Bool = rewriteKid(c.Bool, f)
+		acc = &c
+	case *ast.Definition:
+		c := *n
+		c.Str = rewriteKid(c.Str, f)
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *ast.Rule:
+		c := *n
+		c.Lhs = rewriteKid(c.Lhs, f)
+		c.Rhs = rewriteKid(c.Rhs, f)
+		acc = &c
+	case *boolean.Expr:
+		c := *n
+		c.Unary = rewriteKid(c.Unary, f)
+		c.Rest = rewriteOpt(c.Rest, f)
+		acc = &c
+	case *boolean.UnaryExpr:
+		c := *n
+		c.Ops = rewriteAll(c.Ops, f)
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *boolean.UnaryOp:
+		c := *n
+		acc = &c
+	case *boolean.ExprRest:
+		c := *n
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *boolean.PrimaryExpr:
+		c := *n
+		c.Cmp = rewriteKid(c.Cmp, f)
+		c.Paren = rewriteKid(c.Paren, f)
+		acc = &c
+	case *boolean.ParenExpr:
+		c := *n
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *arith.Comparison:
+		c := *n
+		c.Left = rewriteKid(c.Left, f)
+		c.Right = rewriteKid(c.Right, f)
+		acc = &c
+	case *arith.Sum:
+		c := *n
+		c.Head = rewriteKid(c.Head, f)
+		c.Rest = rewriteAll(c.Rest, f)
+		acc = &c
+	case *arith.SumRest:
+		c := *n
+		c.Term = rewriteKid(c.Term, f)
+		acc = &c
+	case *arith.Term:
+		c := *n
+		c.Head = rewriteKid(c.Head, f)
+		c.Rest = rewriteAll(c.Rest, f)
+		acc = &c
+	case *arith.TermRest:
+		c := *n
+		c.Factor = rewriteKid(c.Factor, f)
+		acc = &c
+	case *arith.Factor:
+		c := *n
+		c.Paren = rewriteKid(c.Paren, f)
+		acc = &c
+	case *arith.ParenExpr:
+		c := *n
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *strings.Expr:
+		c := *n
+		c.Head = rewriteKid(c.Head, f)
+		c.Rest = rewriteAll(c.Rest, f)
+		c.Compare = rewriteOpt(c.Compare, f)
+		acc = &c
+	case *strings.ConcatRest:
+		c := *n
+		c.Operand = rewriteKid(c.Operand, f)
+		acc = &c
+	case *strings.CompareRest:
+		c := *n
+		c.Right = rewriteKid(c.Right, f)
+		acc = &c
+	case *strings.Concat:
+		c := *n
+		c.Head = rewriteKid(c.Head, f)
+		c.Rest = rewriteAll(c.Rest, f)
+		acc = &c
+	case *strings.Operand:
+		c := *n
+		c.Lit = rewriteKid(c.Lit, f)
+		c.Paren = rewriteKid(c.Paren, f)
+		acc = &c
+	case *strings.Literal:
+		c := *n
+		acc = &c
+	case *strings.ParenExpr:
+		c := *n
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *bits.Expr:
+		c := *n
+		c.Count = rewriteKid(c.Count, f)
+		c.Body = rewriteKid(c.Body, f)
+		acc = &c
+	case *bits.BitwiseExpr:
+		c := *n
+		c.Unary = rewriteKid(c.Unary, f)
+		c.Rest = rewriteOpt(c.Rest, f)
+		acc = &c
+	case *bits.BitwiseRest:
+		c := *n
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *bits.UnaryExpr:
+		c := *n
+		c.Ops = rewriteAll(c.Ops, f)
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *bits.UnaryOp:
+		c := *n
+		acc = &c
+	case *bits.ShiftExpr:
+		c := *n
+		c.Head = rewriteKid(c.Head, f)
+		c.Rest = rewriteAll(c.Rest, f)
+		acc = &c
+	case *bits.ShiftRest:
+		c := *n
+		acc = &c
+	case *bits.PrimaryExpr:
+		c := *n
+		c.Paren = rewriteKid(c.Paren, f)
+		acc = &c
+	case *bits.ParenExpr:
+		c := *n
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *sets.Expr:
+		c := *n
+		c.Pred = rewriteKid(c.Pred, f)
+		c.Set = rewriteKid(c.Set, f)
+		acc = &c
+	case *sets.Predicate:
+		c := *n
+		c.Unary = rewriteKid(c.Unary, f)
+		c.Rest = rewriteOpt(c.Rest, f)
+		acc = &c
+	case *sets.PredRest:
+		c := *n
+		c.Pred = rewriteKid(c.Pred, f)
+		acc = &c
+	case *sets.PredUnary:
+		c := *n
+		c.Ops = rewriteAll(c.Ops, f)
+		c.Atom = rewriteKid(c.Atom, f)
+		acc = &c
+	case *sets.UnaryOp:
+		c := *n
+		acc = &c
+	case *sets.Atom:
+		c := *n
+		c.Cmp = rewriteKid(c.Cmp, f)
+		c.Member = rewriteKid(c.Member, f)
+		c.Subset = rewriteKid(c.Subset, f)
+		c.Paren = rewriteKid(c.Paren, f)
+		acc = &c
+	case *sets.Member:
+		c := *n
+		c.Elem = rewriteKid(c.Elem, f)
+		c.Set = rewriteKid(c.Set, f)
+		acc = &c
+	case *sets.Subset:
+		c := *n
+		c.Left = rewriteKid(c.Left, f)
+		c.Right = rewriteKid(c.Right, f)
+		acc = &c
+	case *sets.ParenPredicate:
+		c := *n
+		c.Pred = rewriteKid(c.Pred, f)
+		acc = &c
+	case *sets.SetExpr:
+		c := *n
+		c.Unary = rewriteKid(c.Unary, f)
+		c.Rest = rewriteOpt(c.Rest, f)
+		acc = &c
+	case *sets.SetRest:
+		c := *n
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *sets.SetUnary:
+		c := *n
+		c.Ops = rewriteAll(c.Ops, f)
+		c.Primary = rewriteKid(c.Primary, f)
+		acc = &c
+	case *sets.SetPrimary:
+		c := *n
+		c.Builder = rewriteKid(c.Builder, f)
+		c.Lit = rewriteKid(c.Lit, f)
+		c.Range = rewriteKid(c.Range, f)
+		c.Paren = rewriteKid(c.Paren, f)
+		acc = &c
+	case *sets.Builder:
+		c := *n
+		c.Domain = rewriteKid(c.Domain, f)
+		c.Pred = rewriteKid(c.Pred, f)
+		acc = &c
+	case *sets.SetLiteral:
+		c := *n
+		c.Elems = rewritePtrs(c.Elems, 0, f)
+		acc = &c
+	case *sets.RangeLiteral:
+		c := *n
+		acc = &c
+	case *sets.Element:
+		c := *n
+		acc = &c
+	case *sets.ParenSet:
+		c := *n
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *data.Expr:
+		c := *n
+		c.Match = rewriteKid(c.Match, f)
+		c.Tuple = rewriteKid(c.Tuple, f)
+		c.List = rewriteKid(c.List, f)
+		c.Bool = rewriteKid(c.Bool, f)
+		c.Paren = rewriteKid(c.Paren, f)
+		acc = &c
+	case *data.Tuple:
+		c := *n
+		c.Elems = rewritePtrs(c.Elems, 2, f)
+		acc = &c
+	case *data.List:
+		c := *n
+		c.Elems = rewritePtrs(c.Elems, 0, f)
+		acc = &c
+	case *data.ParenExpr:
+		c := *n
+		c.Expr = rewriteKid(c.Expr, f)
+		acc = &c
+	case *data.Match:
+		c := *n
+		c.Expr = rewriteKid(c.Expr, f)
+		c.Arms = rewritePtrs(c.Arms, 1, f)
+		acc = &c
+	case *data.Arm:
+		c := *n
+		c.Pattern = rewriteKid(c.Pattern, f)
+		c.Body = rewriteKid(c.Body, f)
+		acc = &c
+	case *data.Pattern:
+		c := *n
+		c.Tuple = rewriteKid(c.Tuple, f)
+		c.List = rewriteKid(c.List, f)
+		acc = &c
+	case *data.TuplePattern:
+		c := *n
+		c.Elems = rewritePtrs(c.Elems, 2, f)
+		acc = &c
+	case *data.ListPattern:
+		c := *n
+		c.Elems = rewritePtrs(c.Elems, 0, f)
+		acc = &c
+	default:
+		panic(fmt.Sprintf("walk: %T is not a syntax tree node", node))
+	}
+	res := f(acc)
+	if res == nil {
+		return nil
+	}
+	if pos := position(res); pos != nil && res != acc && pos.Line == 0 {
+		*pos = *position(acc)
+	}
+	return res
+}
+
+// rewriteKid rewrites a child the grammar requires, which f may replace
+// but not drop.
+func rewriteKid[T any](kid *T, f func(Node) Node) *T {
+	if kid == nil {
+		return nil
+	}
+	res := rewriteOpt(kid, f)
+	if res == nil {
+		panic(fmt.Sprintf("walk: %T is required and cannot be dropped", kid))
+	}
+	return res
+}
+
+func rewriteOpt[T any](kid *T, f func(Node) Node) *T {
+	if kid == nil {
+		return nil
+	}
+	res := rewrite(kid, f)
+	if res == nil {
+		return nil
+	}
+	typed, ok := res.(*T)
+	if !ok {
+		panic(fmt.Sprintf("walk: %T cannot replace %T", res, kid))
+	}
+	return typed
+}
+
+// rewritePtrs rewrites a list the grammar requires least elements of.
+func rewritePtrs[T any](kids []*T, least int, f func(Node) Node) []*T {
+	if kids == nil {
+		return nil
+	}
+	acc := []*T{}
+	for _, kid := range kids {
+		if res := rewriteOpt(kid, f); res != nil {
+			acc = append(acc, res)
+		}
+	}
+	if len(acc) < least {
+		panic(fmt.Sprintf("walk: %T needs at least %d elements", kids, least))
+	}
+	return acc
+}
+
+func rewriteAll[T any](kids []T, f func(Node) Node) []T {
+	if kids == nil {
+		return nil
+	}
+	acc := []T{}
+	for idx := range kids {
+		if res := rewriteOpt(&kids[idx], f); res != nil {
+			acc = append(acc, *res)
+		}
+	}
+	return acc
+}
@@ -0,0 +1,226 @@
+package walk
+
+import (
+	"fmt"
+	"testing"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser"
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ast"
+	"acornlang.dev/lang/types/ast/arith"
+	"acornlang.dev/lang/types/ast/bits"
+	"acornlang.dev/lang/types/ast/boolean"
+	"acornlang.dev/lang/types/ast/data"
+	"acornlang.dev/lang/types/ast/sets"
+	"acornlang.dev/lang/types/ast/strings"
+	"github.com/alecthomas/participle/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// The expression parsers of bits, sets and data live with their
+// evaluators, which use this package.
+var (
+	bitsParser = participle.MustBuild[bits.Expr](
+		participle.Lexer(lexer.BooleanLexer),
+		participle.Elide("Whitespace", "Comment"),
+	)
+	setsParser = participle.MustBuild[sets.Expr](
+		participle.Lexer(lexer.BooleanLexer),
+		participle.Elide("Whitespace", "Comment"),
+		participle.UseLookahead(participle.MaxLookahead),
+	)
+	dataParser = participle.MustBuild[data.Expr](
+		participle.Lexer(lexer.BooleanLexer),
+		participle.Elide("Whitespace", "Comment"),
+		participle.UseLookahead(participle.MaxLookahead),
+	)
+)
+
+func parse(t *testing.T, input string) *ast.File {
+	file, err := parser.FileParser.ParseString("", input)
+	assert.NoError(t, err)
+	return file
+}
+
+func TestInspect(t *testing.T) {
+	file := parse(t, "let c := not a and (b or x + 1 > y)\nrule r: p -> p\nlet s := \"a\" ++ label == \"b\"")
+	names := []string{}
+	Inspect(file, func(node Node) bool {
+		switch n := node.(type) {
+		case *boolean.PrimaryExpr:
+			if n.Var != "" {
+				names = append(names, n.Var)
+			}
+		case *arith.Factor:
+			if n.Var != "" {
+				names = append(names, "int "+n.Var)
+			}
+		case *strings.Operand:
+			if n.Var != "" {
+				names = append(names, "string "+n.Var)
+			}
+		case *ast.Rule:
+			return false
+		}
+		return true
+	})
+	assert.Equal(t, []string{"a", "b", "int x", "int y", "string label"}, names)
+}
+
+type counter struct {
+	depth, max int
+	seen       []string
+}
+
+func (c *counter) Visit(node Node) Visitor {
+	if node == nil {
+		c.depth--
+		return nil
+	}
+	c.depth++
+	c.max = max(c.max, c.depth)
+	c.seen = append(c.seen, fmt.Sprintf("%T", node))
+	return c
+}
+
+func TestWalk(t *testing.T) {
+	file := parse(t, "~p")
+	c := &counter{}
+	Walk(c, file.Head.Bool)
+	assert.Equal(t, []string{"*boolean.Expr", "*boolean.UnaryExpr", "*boolean.UnaryOp", "*boolean.PrimaryExpr"}, c.seen)
+	assert.Equal(t, 0, c.depth)
+	assert.Equal(t, 3, c.max)
+	assert.Empty(t, Children(&file.Head.Bool.Unary.Ops[0]))
+	assert.Equal(t, types.Position{Offset: 1, Line: 1, Column: 2}, Position(file.Head.Bool.Unary.Expr))
+}
+
+func TestRewrite(t *testing.T) {
+	file := parse(t, "let c := not not a xor b")
+	// `a xor b` becomes `a <~> b` and double negation is dropped.
+	rewritten := Rewrite(file, func(node Node) Node {
+		switch n := node.(type) {
+		case *boolean.ExprRest:
+			if n.Op == lexer.XOR_TEXT {
+				return &boolean.ExprRest{Op: lexer.XOR_SYMB, Expr: n.Expr}
+			}
+		case *boolean.UnaryExpr:
+			if len(n.Ops) == 2 {
+				return &boolean.UnaryExpr{Pos: n.Pos, Expr: n.Expr}
+			}
+		}
+		return node
+	})
+	expr := rewritten.Head.Def.Expr
+	assert.Empty(t, expr.Unary.Ops)
+	assert.Equal(t, "a", expr.Unary.Expr.Var)
+	assert.Equal(t, lexer.XOR_SYMB, expr.Rest.Op)
+	assert.Equal(t, types.Position{Offset: 19, Line: 1, Column: 20}, expr.Rest.Pos)
+	assert.Equal(t, types.Position{Offset: 23, Line: 1, Column: 24}, expr.Rest.Expr.Pos)
+
+	// The original is left as it was.
+	original := file.Head.Def.Expr
+	assert.Len(t, original.Unary.Ops, 2)
+	assert.Equal(t, lexer.XOR_TEXT, original.Rest.Op)
+	assert.NotSame(t, original.Rest.Expr, expr.Rest.Expr)
+}
+
+func TestRewriteDrops(t *testing.T) {
+	file := parse(t, "~~p and q")
+	expr := Rewrite(file.Head.Bool, func(node Node) Node {
+		switch node.(type) {
+		case *boolean.UnaryOp, *boolean.ExprRest:
+			return nil
+		}
+		return node
+	})
+	assert.Empty(t, expr.Unary.Ops)
+	assert.Nil(t, expr.Rest)
+	assert.PanicsWithValue(t, "walk: *boolean.PrimaryExpr is required and cannot be dropped", func() {
+		Rewrite(file.Head.Bool, func(Node) Node { return nil })
+	})
+	pair, err := dataParser.ParseString("", "(p, q)")
+	assert.NoError(t, err)
+	assert.PanicsWithValue(t, "walk: []*data.Expr needs at least 2 elements", func() {
+		Rewrite(pair, func(node Node) Node {
+			if expr, ok := node.(*data.Expr); ok && expr.Bool != nil {
+				return nil
+			}
+			return node
+		})
+	})
+	assert.PanicsWithValue(t, "walk: *boolean.Expr cannot replace *boolean.UnaryExpr", func() {
+		Rewrite(file.Head.Bool, func(node Node) Node {
+			if _, ok := node.(*boolean.UnaryExpr); ok {
+				return &boolean.Expr{}
+			}
+			return node
+		})
+	})
+}
+
+func appendName(acc []string, name string) []string {
+	if name == "" {
+		return acc
+	}
+	return append(acc, name)
+}
+
+func TestInspectOtherExpressions(t *testing.T) {
+	names := func(node Node) []string {
+		acc := []string{}
+		Inspect(node, func(node Node) bool {
+			switch n := node.(type) {
+			case *bits.PrimaryExpr:
+				acc = appendName(acc, n.Var)
+			case *sets.SetPrimary:
+				acc = appendName(acc, n.Var)
+			case *sets.Element:
+				acc = appendName(acc, n.Sym)
+			case *arith.Factor:
+				acc = appendName(acc, n.Var)
+			case *data.Pattern:
+				acc = appendName(acc, n.Var)
+			case *boolean.PrimaryExpr:
+				acc = appendName(acc, n.Var)
+			}
+			return true
+		})
+		return acc
+	}
+
+	vector, err := bitsParser.ParseString("", "x and not (y << 1)")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x", "y"}, names(vector))
+
+	set, err := setsParser.ParseString("", "{n in 1..9 | n mod 2 == 0} or {red, b}")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"n", "red", "b"}, names(set))
+
+	match, err := dataParser.ParseString("", "match (p, [q]) with (a, [_]) -> a | _ -> False")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"p", "q", "a", "_", "a", "_"}, names(match))
+}
+
+func TestRewriteOtherExpressions(t *testing.T) {
+	list, err := dataParser.ParseString("", "[p, q, r]")
+	assert.NoError(t, err)
+	// q is dropped from the list and p renamed.
+	rewritten := Rewrite(list, func(node Node) Node {
+		switch n := node.(type) {
+		case *data.Expr:
+			if n.Bool != nil && n.Bool.Unary.Expr.Var == "q" {
+				return nil
+			}
+		case *boolean.PrimaryExpr:
+			if n.Var == "p" {
+				return &boolean.PrimaryExpr{Var: "s"}
+			}
+		}
+		return node
+	})
+	assert.Len(t, rewritten.List.Elems, 2)
+	assert.Equal(t, "s", rewritten.List.Elems[0].Bool.Unary.Expr.Var)
+	assert.Equal(t, types.Position{Offset: 1, Line: 1, Column: 2}, rewritten.List.Elems[0].Bool.Unary.Expr.Pos)
+	assert.Len(t, list.List.Elems, 3)
+}
@@ -0,0 +1,713 @@
+package check
+
+import (
+	"fmt"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/parser/arith"
+	parserBits "acornlang.dev/lang/parser/bits"
+	parserStrings "acornlang.dev/lang/parser/strings"
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ast"
+	arithAst "acornlang.dev/lang/types/ast/arith"
+	bitsAst "acornlang.dev/lang/types/ast/bits"
+	"acornlang.dev/lang/types/ast/boolean"
+	dataAst "acornlang.dev/lang/types/ast/data"
+	setsAst "acornlang.dev/lang/types/ast/sets"
+	"acornlang.dev/lang/types/ast/strings"
+	"acornlang.dev/lang/types/ast/walk"
+	"acornlang.dev/lang/types/ir"
+)
+
+// Error is a type error at a position in the source.
+type Error struct {
+	Pos types.Position
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Pos.Line, e.Pos.Column, e.Msg)
+}
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Env maps names to their types. Names missing from it are free inputs,
+// which are Bool.
+type Env map[string]types.Type
+
+// Info is what checking a file found out.
+type Info struct {
+	// Defs holds the type of every `let` definition.
+	Defs Env
+	// Funcs holds every `let` definition as a function of the inputs it
+	// reads, directly or through earlier definitions, in the order the file
+	// first names them.
+	Funcs map[string]types.FuncType
+	// Exprs holds the type of every top-level item in order; nil for rules.
+	Exprs []types.Type
+}
+
+// File checks every item of file in order. A definition is visible to the
+// items after it; naming it above its definition is an error, as is
+// defining a name twice.
+func File(file *ast.File) (Info, error) {
+	items := []*ast.Expr{file.Head}
+	for _, tail := range file.Tail {
+		items = append(items, tail.Expr)
+	}
+	defined := map[string]types.Position{}
+	for _, item := range items {
+		if item.Def != nil {
+			if pos, ok := defined[item.Def.Name]; ok {
+				return Info{}, errorAt(item.Def.Pos, "'%s' is already defined at %d:%d", item.Def.Name, pos.Line, pos.Column)
+			}
+			defined[item.Def.Name] = item.Def.Pos
+		}
+	}
+
+	info := Info{Defs: Env{}, Funcs: map[string]types.FuncType{}}
+	// inputs are the free names in the order the file first names them,
+	// and params the inputs each definition reads.
+	inputs := []string{}
+	inputTypes := Env{}
+	params := map[string][]string{}
+	for _, item := range items {
+		switch {
+		case item.Def != nil:
+			reads := map[string]bool{}
+			read := func(name string, typ types.Type) {
+				if defParams, ok := params[name]; ok {
+					for _, param := range defParams {
+						reads[param] = true
+					}
+					return
+				}
+				if _, ok := inputTypes[name]; !ok {
+					inputs = append(inputs, name)
+					inputTypes[name] = typ
+				}
+				reads[name] = true
+			}
+			typ, err := checkItem(item.Def.Str, item.Def.Expr, info.Defs, defined, read)
+			if err != nil {
+				return Info{}, err
+			}
+			acc := []string{}
+			fn := types.FuncType{Params: []types.Type{}, Result: typ}
+			for _, input := range inputs {
+				if reads[input] {
+					acc = append(acc, input)
+					fn.Params = append(fn.Params, inputTypes[input])
+				}
+			}
+			params[item.Def.Name] = acc
+			info.Defs[item.Def.Name] = typ
+			info.Funcs[item.Def.Name] = fn
+			info.Exprs = append(info.Exprs, typ)
+		case item.Rule != nil:
+			if err := Rule(item.Rule); err != nil {
+				return Info{}, err
+			}
+			info.Exprs = append(info.Exprs, nil)
+		default:
+			typ, err := checkItem(item.Str, item.Bool, info.Defs, defined, func(string, types.Type) {})
+			if err != nil {
+				return Info{}, err
+			}
+			info.Exprs = append(info.Exprs, typ)
+		}
+	}
+	return info, nil
+}
+
+func checkItem(
+	str *strings.Expr,
+	expr *boolean.Expr,
+	env Env,
+	defined map[string]types.Position,
+	read func(name string, typ types.Type),
+) (types.Type, error) {
+	var err error
+	visit := func(name string, pos types.Position, typ types.Type) {
+		if err == nil {
+			err = usedAbove(name, pos, env, defined)
+		}
+		read(name, typ)
+	}
+	if str != nil {
+		visitStringVars(str, visit)
+		if err != nil {
+			return nil, err
+		}
+		return String(str, env)
+	}
+	visitVars(expr, visit)
+	if err != nil {
+		return nil, err
+	}
+	return Expr(expr, env)
+}
+
+// usedAbove reports a name that is defined later in the file but not yet.
+func usedAbove(name string, pos types.Position, env Env, defined map[string]types.Position) error {
+	if _, ok := env[name]; ok {
+		return nil
+	}
+	if def, ok := defined[name]; ok {
+		return errorAt(pos, "'%s' is used above its definition at %d:%d", name, def.Line, def.Column)
+	}
+	return nil
+}
+
+// visitVars visits the names of expr with the type they are read at; the
+// names of a comparison are Int and visited at the comparison's position.
+func visitVars(expr *boolean.Expr, visit func(string, types.Position, types.Type)) {
+	if expr == nil {
+		return
+	}
+	walk.Inspect(expr, func(node walk.Node) bool {
+		primary, ok := node.(*boolean.PrimaryExpr)
+		switch {
+		case !ok:
+		case primary.Cmp != nil:
+			for _, name := range arith.Vars(primary.Cmp) {
+				visit(name, primary.Pos, types.INT)
+			}
+			return false
+		case primary.Var != "":
+			visit(primary.Var, primary.Pos, types.BOOL)
+		}
+		return true
+	})
+}
+
+func visitStringVars(expr *strings.Expr, visit func(string, types.Position, types.Type)) {
+	walk.Inspect(expr, func(node walk.Node) bool {
+		switch n := node.(type) {
+		case *strings.Literal:
+			visitLiteralVars(n, visit)
+		case *strings.Operand:
+			if n.Var != "" {
+				visit(n.Var, n.Pos, types.STRING)
+			}
+		}
+		return true
+	})
+}
+
+// visitLiteralVars visits interpolated names at the literal's position;
+// malformed literals are left for String to report.
+func visitLiteralVars(lit *strings.Literal, visit func(string, types.Position, types.Type)) {
+	segments, err := parserStrings.Segments(lit)
+	if err != nil {
+		return
+	}
+	for _, segment := range segments {
+		visitVars(segment.Expr, func(name string, _ types.Position, typ types.Type) {
+			visit(name, lit.Pos, typ)
+		})
+	}
+}
+
+// Rule checks that both sides of a rewrite rule have the same type, with
+// every pattern variable standing for a Bool.
+func Rule(rule *ast.Rule) error {
+	lhs, err := Expr(rule.Lhs, Env{})
+	if err != nil {
+		return err
+	}
+	rhs, err := Expr(rule.Rhs, Env{})
+	if err != nil {
+		return err
+	}
+	if !lhs.Equal(rhs) {
+		return errorAt(rule.Rhs.Pos, "rule '%s' rewrites %s into %s", rule.Name, lhs, rhs)
+	}
+	return nil
+}
+
+// Regd. Expressions
+
+// Expr infers the type of expr. Connectives accept Bool and Degree
+// operands and give a Degree as soon as one operand is a Degree; temporal
+// operators only accept Bool.
+func Expr(expr *boolean.Expr, env Env) (types.Type, error) {
+	lowered, err := ir.Lower(expr)
+	if err != nil {
+		return nil, err
+	}
+	return node(lowered, env)
+}
+
+func node(expr ir.Node, env Env) (types.Type, error) {
+	switch e := expr.(type) {
+	case *ir.Binary:
+		left, err := node(e.X, env)
+		if err != nil {
+			return nil, err
+		}
+		right, err := node(e.Y, env)
+		if err != nil {
+			return nil, err
+		}
+		if err := truthOperand(e.X.Position(), e.Text, left); err != nil {
+			return nil, err
+		}
+		if err := truthOperand(e.Y.Position(), e.Text, right); err != nil {
+			return nil, err
+		}
+		if e.Op.IsTemporal() {
+			if err := temporalOperand(e.OpPos, e.Text, left); err != nil {
+				return nil, err
+			}
+			if err := temporalOperand(e.OpPos, e.Text, right); err != nil {
+				return nil, err
+			}
+			return types.BOOL, nil
+		}
+		return join(left, right), nil
+	case *ir.Unary:
+		acc, err := node(e.X, env)
+		if err != nil {
+			return nil, err
+		}
+		if err := truthOperand(e.X.Position(), e.Text, acc); err != nil {
+			return nil, err
+		}
+		if e.Op.IsTemporal() {
+			if err := temporalOperand(e.Pos, e.Text, acc); err != nil {
+				return nil, err
+			}
+		}
+		return acc, nil
+	case *ir.Cmp:
+		if err := intVars(e.Pos, arith.Vars(e.Cmp), env); err != nil {
+			return nil, err
+		}
+		return types.BOOL, nil
+	case *ir.Var:
+		if typ, ok := env[e.Name]; ok {
+			return typ, nil
+		}
+		return types.BOOL, nil
+	case *ir.Degree:
+		return types.DEGREE, nil
+	default:
+		return types.BOOL, nil
+	}
+}
+
+// Sum checks an integer expression. Names in it are Int unless env says
+// otherwise.
+func Sum(expr *arithAst.Sum, env Env) (types.Type, error) {
+	if err := intVars(expr.Pos, arith.SumVars(expr), env); err != nil {
+		return nil, err
+	}
+	return types.INT, nil
+}
+
+func intVars(pos types.Position, names []string, env Env) error {
+	for _, name := range names {
+		if typ, ok := env[name]; ok && !typ.Equal(types.INT) {
+			return errorAt(pos, "'%s' is %s, so it cannot be used as an Int", name, typ)
+		}
+	}
+	return nil
+}
+
+func truthOperand(pos types.Position, op string, typ types.Type) error {
+	if !typ.Equal(types.BOOL) && !typ.Equal(types.DEGREE) {
+		return errorAt(pos, "'%s' needs Bool or Degree, not %s", op, typ)
+	}
+	return nil
+}
+
+func temporalOperand(pos types.Position, op string, typ types.Type) error {
+	if !typ.Equal(types.BOOL) {
+		return errorAt(pos, "temporal operator '%s' needs Bool, not %s", op, typ)
+	}
+	return nil
+}
+
+func join(a types.Type, b types.Type) types.Type {
+	if a.Equal(types.DEGREE) || b.Equal(types.DEGREE) {
+		return types.DEGREE
+	}
+	return types.BOOL
+}
+
+// Regd. Strings
+
+// String infers the type of a string expression: String, or Bool when it
+// ends in a comparison. Interpolations may hold any well-typed expression.
+func String(expr *strings.Expr, env Env) (types.Type, error) {
+	op := lexer.CONCAT_SYMB
+	if len(expr.Rest) == 0 && expr.Compare != nil {
+		op = expr.Compare.Op
+	}
+	if err := operand(expr.Head, op, env); err != nil {
+		return nil, err
+	}
+	for _, rest := range expr.Rest {
+		if err := operand(rest.Operand, rest.Op, env); err != nil {
+			return nil, err
+		}
+	}
+	if expr.Compare == nil {
+		return types.STRING, nil
+	}
+	if err := operand(expr.Compare.Right.Head, expr.Compare.Op, env); err != nil {
+		return nil, err
+	}
+	for _, rest := range expr.Compare.Right.Rest {
+		if err := operand(rest.Operand, rest.Op, env); err != nil {
+			return nil, err
+		}
+	}
+	return types.BOOL, nil
+}
+
+func operand(expr *strings.Operand, op string, env Env) error {
+	switch {
+	case expr.Lit != nil:
+		return literal(expr.Lit, env)
+	case expr.Paren != nil:
+		typ, err := String(expr.Paren.Expr, env)
+		if err != nil {
+			return err
+		}
+		if !typ.Equal(types.STRING) {
+			return errorAt(expr.Pos, "'%s' needs String, not %s", op, typ)
+		}
+		return nil
+	default:
+		typ, ok := env[expr.Var]
+		if !ok {
+			typ = types.BOOL
+		}
+		if !typ.Equal(types.STRING) {
+			return errorAt(expr.Pos, "'%s' needs String, not %s", op, typ)
+		}
+		return nil
+	}
+}
+
+func literal(lit *strings.Literal, env Env) error {
+	segments, err := parserStrings.Segments(lit)
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if segment.Expr == nil {
+			continue
+		}
+		if _, err := Expr(segment.Expr, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Regd. Bit-vectors
+
+// Bits infers the width of a bit-vector expression, or Int for
+// `popcount(...)`. Both sides of a connective need the same width, and
+// every name in it must be bound to a bit-vector in env.
+func Bits(expr *bitsAst.Expr, env Env) (types.Type, error) {
+	if expr.Count != nil {
+		if _, err := bitwise(expr.Count.Expr, env); err != nil {
+			return nil, err
+		}
+		return types.INT, nil
+	}
+	return bitwise(expr.Body, env)
+}
+
+func bitwise(expr *bitsAst.BitwiseExpr, env Env) (types.Type, error) {
+	left, err := bitsUnary(expr.Unary, env)
+	if err != nil || expr.Rest == nil {
+		return left, err
+	}
+	right, err := bitwise(expr.Rest.Expr, env)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := parserBits.Connective(expr.Rest.Op); !ok {
+		return nil, errorAt(expr.Rest.Pos, "'%s' does not apply bit by bit", expr.Rest.Op)
+	}
+	if !left.Equal(right) {
+		return nil, errorAt(expr.Rest.Pos, "'%s' needs the same width on both sides, not %s and %s", expr.Rest.Op, left, right)
+	}
+	return left, nil
+}
+
+// pointwise reports the unary operators that apply to each bit or member
+// on its own: all but the temporal ones.
+func pointwise(spelling string) bool {
+	op, ok := ir.ParseOp(spelling)
+	return ok && op.IsUnary() && !op.IsTemporal()
+}
+
+func bitsUnary(expr *bitsAst.UnaryExpr, env Env) (types.Type, error) {
+	for _, op := range expr.Ops {
+		if !pointwise(op.Op) {
+			return nil, errorAt(op.Pos, "'%s' does not apply bit by bit", op.Op)
+		}
+	}
+	acc, err := bitsPrimary(expr.Expr.Head, env)
+	if err != nil {
+		return nil, err
+	}
+	for _, rest := range expr.Expr.Rest {
+		if _, err := parserBits.ParseShift(&rest); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+func bitsPrimary(expr *bitsAst.PrimaryExpr, env Env) (types.Type, error) {
+	switch {
+	case expr.Paren != nil:
+		return bitwise(expr.Paren.Expr, env)
+	case expr.Var != "":
+		typ, ok := env[expr.Var]
+		if !ok {
+			typ = types.BOOL
+		}
+		if _, ok := typ.(types.BitsType); !ok {
+			return nil, errorAt(expr.Pos, "'%s' is %s, so it cannot be used as a bit-vector", expr.Var, typ)
+		}
+		return typ, nil
+	default:
+		val, err := parserBits.ParseLiteral(expr.Lit)
+		if err != nil {
+			return nil, errorAt(expr.Pos, "%s", err.Error())
+		}
+		return val.Type(), nil
+	}
+}
+
+// Regd. Sets
+
+// Set infers the type of a set expression: Bool for a predicate and Set
+// otherwise. Names used as sets must be a Set or a domain in env, and only
+// the connectives that apply bit by bit apply element by element.
+func Set(expr *setsAst.Expr, env Env) (types.Type, error) {
+	if expr.Pred != nil {
+		return types.BOOL, predicate(expr.Pred, env)
+	}
+	return types.SET, setExpr(expr.Set, env)
+}
+
+func predicate(expr *setsAst.Predicate, env Env) error {
+	if err := setOps(expr.Unary.Ops); err != nil {
+		return err
+	}
+	atom := expr.Unary.Atom
+	var err error
+	switch {
+	case atom.Member != nil:
+		err = setUnary(atom.Member.Set, env)
+	case atom.Subset != nil:
+		if err = setUnary(atom.Subset.Left, env); err == nil {
+			err = setUnary(atom.Subset.Right, env)
+		}
+	case atom.Paren != nil:
+		err = predicate(atom.Paren.Pred, env)
+	}
+	if err != nil || expr.Rest == nil {
+		return err
+	}
+	if err := setConnective(expr.Rest.Pos, expr.Rest.Op); err != nil {
+		return err
+	}
+	return predicate(expr.Rest.Pred, env)
+}
+
+func setExpr(expr *setsAst.SetExpr, env Env) error {
+	if err := setUnary(expr.Unary, env); err != nil || expr.Rest == nil {
+		return err
+	}
+	if err := setConnective(expr.Rest.Pos, expr.Rest.Op); err != nil {
+		return err
+	}
+	return setExpr(expr.Rest.Expr, env)
+}
+
+func setUnary(expr *setsAst.SetUnary, env Env) error {
+	if err := setOps(expr.Ops); err != nil {
+		return err
+	}
+	primary := expr.Primary
+	switch {
+	case primary.Builder != nil:
+		if err := setUnary(primary.Builder.Domain, env); err != nil {
+			return err
+		}
+		return predicate(primary.Builder.Pred, env)
+	case primary.Paren != nil:
+		return setExpr(primary.Paren.Expr, env)
+	case primary.Var != "":
+		typ, ok := env[primary.Var]
+		if !ok {
+			typ = types.BOOL
+		}
+		if _, ok := typ.(types.DomainType); !ok && !typ.Equal(types.SET) {
+			return errorAt(primary.Pos, "'%s' is %s, so it cannot be used as a set", primary.Var, typ)
+		}
+	}
+	return nil
+}
+
+func setOps(ops []setsAst.UnaryOp) error {
+	for _, op := range ops {
+		if !pointwise(op.Op) {
+			return errorAt(op.Pos, "'%s' does not apply to sets", op.Op)
+		}
+	}
+	return nil
+}
+
+func setConnective(pos types.Position, op string) error {
+	if _, ok := parserBits.Connective(op); ok || op == lexer.UNION_TEXT || op == lexer.INTERSECT_TEXT {
+		return nil
+	}
+	return errorAt(pos, "'%s' does not apply to sets", op)
+}
+
+// Binding is the type of a name bound to val: a set is a domain named
+// after the binding, and any other value has its own type.
+func Binding(name string, val types.Value) types.Type {
+	if set, ok := val.(types.Set); ok {
+		return types.DomainType{Name: name, Values: set.Elems}
+	}
+	return val.Type()
+}
+
+// Regd. Tuples and lists
+
+// Data infers the type of a tuple, list or match. List elements and the
+// arms of a match must agree, and a pattern must have the shape of the
+// value it matches.
+func Data(expr *dataAst.Expr, env Env) (types.Type, error) {
+	switch {
+	case expr.Match != nil:
+		return match(expr.Match, env)
+	case expr.Tuple != nil:
+		elems, err := dataAll(expr.Tuple.Elems, env)
+		if err != nil {
+			return nil, err
+		}
+		return types.TupleType{Elems: elems}, nil
+	case expr.List != nil:
+		elems, err := dataAll(expr.List.Elems, env)
+		if err != nil {
+			return nil, err
+		}
+		acc := types.ListType{}
+		for idx, elem := range elems {
+			if idx > 0 && !elem.Equal(elems[0]) {
+				return nil, errorAt(expr.List.Elems[idx].Pos, "list elements are %s and %s", elems[0], elem)
+			}
+			acc.Elem = elems[0]
+		}
+		return acc, nil
+	case expr.Paren != nil:
+		return Data(expr.Paren.Expr, env)
+	default:
+		if name, ok := bareVar(expr.Bool); ok {
+			if typ, ok := env[name]; ok {
+				return typ, nil
+			}
+		}
+		return Expr(expr.Bool, env)
+	}
+}
+
+func bareVar(expr *boolean.Expr) (string, bool) {
+	if expr.Rest != nil || len(expr.Unary.Ops) != 0 || expr.Unary.Expr.Var == "" {
+		return "", false
+	}
+	return expr.Unary.Expr.Var, true
+}
+
+func dataAll(exprs []*dataAst.Expr, env Env) ([]types.Type, error) {
+	acc := []types.Type{}
+	for _, expr := range exprs {
+		typ, err := Data(expr, env)
+		if err != nil {
+			return nil, err
+		}
+		acc = append(acc, typ)
+	}
+	return acc, nil
+}
+
+func match(expr *dataAst.Match, env Env) (types.Type, error) {
+	scrutinee, err := Data(expr.Expr, env)
+	if err != nil {
+		return nil, err
+	}
+	var acc types.Type
+	for _, arm := range expr.Arms {
+		inner := Env{}
+		for name, typ := range env {
+			inner[name] = typ
+		}
+		if err := pattern(arm.Pattern, scrutinee, inner, map[string]bool{}); err != nil {
+			return nil, err
+		}
+		typ, err := Data(arm.Body, inner)
+		if err != nil {
+			return nil, err
+		}
+		if acc != nil && !typ.Equal(acc) {
+			return nil, errorAt(arm.Body.Pos, "match arms give %s and %s", acc, typ)
+		}
+		acc = typ
+	}
+	return acc, nil
+}
+
+// pattern binds the names of p in env to the parts of typ they match.
+func pattern(p *dataAst.Pattern, typ types.Type, env Env, seen map[string]bool) error {
+	switch {
+	case p.Tuple != nil:
+		tuple, ok := typ.(types.TupleType)
+		if !ok || len(tuple.Elems) != len(p.Tuple.Elems) {
+			return errorAt(p.Pos, "a %d-tuple pattern cannot match %s", len(p.Tuple.Elems), typ)
+		}
+		for idx, elem := range p.Tuple.Elems {
+			if err := pattern(elem, tuple.Elems[idx], env, seen); err != nil {
+				return err
+			}
+		}
+	case p.List != nil:
+		list, ok := typ.(types.ListType)
+		if !ok {
+			return errorAt(p.Pos, "a list pattern cannot match %s", typ)
+		}
+		for _, elem := range p.List.Elems {
+			if err := pattern(elem, list.Elem, env, seen); err != nil {
+				return err
+			}
+		}
+	case p.Lit != "":
+		if typ != nil && !typ.Equal(types.BOOL) {
+			return errorAt(p.Pos, "'%s' cannot match %s", p.Lit, typ)
+		}
+	case p.Var != lexer.WILDCARD_SYMB:
+		if seen[p.Var] {
+			return errorAt(p.Pos, "'%s' is bound twice in one pattern", p.Var)
+		}
+		seen[p.Var] = true
+		if typ == nil {
+			typ = types.BOOL
+		}
+		env[p.Var] = typ
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package check
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/parser"
+	parserBits "acornlang.dev/lang/parser/bits"
+	parserData "acornlang.dev/lang/parser/data"
+	parserSets "acornlang.dev/lang/parser/sets"
+	"acornlang.dev/lang/types"
+)
+
+func checkFile(t *testing.T, input string) (Info, error) {
+	file, err := parser.FileParser.ParseString("", input)
+	if err != nil {
+		t.Fatalf("parse %q: %v", input, err)
+	}
+	return File(file)
+}
+
+func TestFileTypes(t *testing.T) {
+	info, err := checkFile(t, "let carry := a and b\nlet soft := carry or 0.3\nrule dneg: not not a -> a\nsoft and globally p")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.Defs["carry"].Equal(types.BOOL) || !info.Defs["soft"].Equal(types.DEGREE) {
+		t.Errorf("unexpected definition types %v", info.Defs)
+	}
+	expected := []types.Type{types.BOOL, types.DEGREE, nil, types.DEGREE}
+	for idx, typ := range info.Exprs {
+		if (typ == nil) != (expected[idx] == nil) || (typ != nil && !typ.Equal(expected[idx])) {
+			t.Errorf("item %d: expected %v, got %v", idx, expected[idx], typ)
+		}
+	}
+}
+
+func TestFileFuncTypes(t *testing.T) {
+	info, err := checkFile(t, "let carry := a and b\nlet big := n > 3 or carry\nlet k := False\nlet name := \"{k} {c}\"\nlet soft := k or 0.3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"carry": "(Bool, Bool) -> Bool",
+		"big":   "(Bool, Bool, Int) -> Bool",
+		"k":     "() -> Bool",
+		"name":  "Bool -> String",
+		"soft":  "() -> Degree",
+	}
+	for name, typ := range expected {
+		if got := info.Funcs[name].String(); got != typ {
+			t.Errorf("%s: expected %s, got %s", name, typ, got)
+		}
+	}
+}
+
+func TestFileTypeErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"□0.5", "1:1: temporal operator '□' needs Bool, not Degree"},
+		{"p until (q and 0.5)", "1:3: temporal operator 'until' needs Bool, not Degree"},
+		{"let x := p\nlet x := q", "2:1: 'x' is already defined at 1:1"},
+		{"x or p\nlet x := q", "1:1: 'x' is used above its definition at 2:1"},
+		{"rule soften: a -> a and 0.5", "1:19: rule 'soften' rewrites Bool into Degree"},
+		{"let x := p\nx + 1 > 3", "2:1: 'x' is Bool, so it cannot be used as an Int"},
+		{"let s := \"s\"\nq and 2 * s == 4", "2:7: 's' is String, so it cannot be used as an Int"},
+	}
+	for _, test := range tests {
+		_, err := checkFile(t, test.input)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("%q: expected error %q, got %v", test.input, test.expected, err)
+		}
+	}
+}
+
+func TestStringTypes(t *testing.T) {
+	info, err := checkFile(t, "let name := \"adder\"\nlet label := \"{name}: {a and b}\" ++ name\n\"x\" == label\nname ++ label\nlabel != \"x\"")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.Defs["label"].Equal(types.STRING) || !info.Exprs[2].Equal(types.BOOL) || !info.Exprs[3].Equal(types.STRING) || !info.Exprs[4].Equal(types.BOOL) {
+		t.Errorf("unexpected types %v %v", info.Defs, info.Exprs)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"\"x\" ++ p", "1:8: '++' needs String, not Bool"},
+		{"let name := \"adder\"\nname and p", "2:1: 'and' needs Bool or Degree, not String"},
+		{"\"{□0.5}\"", "1:1: temporal operator '□' needs Bool, not Degree"},
+		{"\"{later}\"\nlet later := p", "1:1: 'later' is used above its definition at 2:1"},
+		{"\"bad \\q\"", "1:6: invalid escape '\\q'"},
+		{"\"x\" == p", "1:8: '==' needs String, not Bool"},
+		{"p ++ \"x\"", "1:1: '++' needs String, not Bool"},
+	}
+	for _, test := range tests {
+		_, err := checkFile(t, test.input)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("%q: expected error %q, got %v", test.input, test.expected, err)
+		}
+	}
+}
+
+func TestComparisonTypes(t *testing.T) {
+	info, err := checkFile(t, "let big := x + y > 3 and not flag\nbig or 0.5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.Defs["big"].Equal(types.BOOL) || !info.Exprs[1].Equal(types.DEGREE) {
+		t.Errorf("unexpected types %v %v", info.Defs, info.Exprs)
+	}
+}
+
+func TestBitsTypes(t *testing.T) {
+	env := Env{"flags": types.BitsType{Width: 8}, "p": types.BOOL}
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0xF0 and not flags << 2", "Bits[8]"},
+		{"popcount(flags)", "Int"},
+		{"0b1011 xor 0x0", "Bits[4]"},
+	}
+	for _, test := range tests {
+		expr, err := parserBits.ExprParser.ParseString("", test.input)
+		if err != nil {
+			t.Fatalf("parse %q: %v", test.input, err)
+		}
+		typ, err := Bits(expr, env)
+		if err != nil || typ.String() != test.expected {
+			t.Errorf("%q: expected %s, got %v (%v)", test.input, test.expected, typ, err)
+		}
+	}
+
+	errs := []struct {
+		input    string
+		expected string
+	}{
+		{"flags or 0b1", "1:7: 'or' needs the same width on both sides, not Bits[8] and Bits[1]"},
+		{"p and 0b1", "1:1: 'p' is Bool, so it cannot be used as a bit-vector"},
+		{"□0b1", "1:1: '□' does not apply bit by bit"},
+	}
+	for _, test := range errs {
+		expr, err := parserBits.ExprParser.ParseString("", test.input)
+		if err != nil {
+			t.Fatalf("parse %q: %v", test.input, err)
+		}
+		_, err = Bits(expr, env)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("%q: expected error %q, got %v", test.input, test.expected, err)
+		}
+	}
+}
+
+func TestSetTypes(t *testing.T) {
+	env := Env{
+		"warm":  types.SET,
+		"p":     types.BOOL,
+		"Color": Binding("Color", types.NewSet([]string{"red", "green"})),
+	}
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"{x in Color | x != red} union warm", "Set"},
+		{"green in Color", "Bool"},
+		{"{x in 1..9 | x mod 2 == 0} union warm", "Set"},
+		{"red in warm and not {} subset (warm or {blue})", "Bool"},
+		{"p union warm", "1:1: 'p' is Bool, so it cannot be used as a set"},
+		{"{x in warm | ◇x in warm}", "1:14: '◇' does not apply to sets"},
+		{"warm release {a}", "1:6: 'release' does not apply to sets"},
+	}
+	for _, test := range tests {
+		expr, err := parserSets.ExprParser.ParseString("", test.input)
+		if err != nil {
+			t.Fatalf("parse %q: %v", test.input, err)
+		}
+		typ, err := Set(expr, env)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		} else {
+			got = typ.String()
+		}
+		if got != test.expected {
+			t.Errorf("%q: expected %s, got %s", test.input, test.expected, got)
+		}
+	}
+}
+
+func TestBinding(t *testing.T) {
+	color := Binding("Color", types.NewSet([]string{"red", "green"}))
+	if !color.Equal(types.DomainType{Name: "Color"}) || color.Equal(types.DomainType{Name: "Shade"}) {
+		t.Errorf("domains should be equal by name, got %v", color)
+	}
+	if color.String() != "Color" || !Binding("n", types.Int(3)).Equal(types.INT) {
+		t.Errorf("unexpected binding types %v", color)
+	}
+}
+
+func TestDataTypes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(p, [q, 0.5])", "1:9: list elements are Bool and Degree"},
+		{"(p, [q, r])", "(Bool, [Bool])"},
+		{"match (p, q) with (True, x) -> (x, x) | _ -> (p, q)", "(Bool, Bool)"},
+		{"match [p] with [x] -> x | _ -> [p]", "1:32: match arms give Bool and [Bool]"},
+		{"match (p, q) with (x, y, z) -> x", "1:19: a 3-tuple pattern cannot match (Bool, Bool)"},
+		{"match (p, q) with (x, x) -> x", "1:23: 'x' is bound twice in one pattern"},
+		{"match (p, q) with pair -> pair and p", "1:27: 'and' needs Bool or Degree, not (Bool, Bool)"},
+	}
+	for _, test := range tests {
+		expr, err := parserData.ExprParser.ParseString("", test.input)
+		if err != nil {
+			t.Fatalf("parse %q: %v", test.input, err)
+		}
+		typ, err := Data(expr, Env{})
+		got := ""
+		if err != nil {
+			got = err.Error()
+		} else {
+			got = typ.String()
+		}
+		if got != test.expected {
+			t.Errorf("%q: expected %s, got %s", test.input, test.expected, got)
+		}
+	}
+}
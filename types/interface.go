@@ -1,7 +1,348 @@
 package types
 
 import (
-	"github.com/alecthomas/participle/v2/lexer"
+	"sort"
+	"strconv"
+	"strings"
+
+	"acornlang.dev/lang/lexer"
+	participleLexer "github.com/alecthomas/participle/v2/lexer"
+)
+
+type Position participleLexer.Position
+
+// Value is anything an expression can evaluate to.
+type Value interface {
+	String() string
+	Type() Type
+}
+
+type Bool bool
+
+func (b Bool) String() string {
+	if b {
+		return lexer.TRUE
+	}
+	return lexer.FALSE
+}
+
+func (b Bool) Type() Type {
+	return BOOL
+}
+
+// Degree is a fuzzy truth value in [0, 1].
+type Degree float64
+
+func (d Degree) String() string {
+	return strconv.FormatFloat(float64(d), 'g', 6, 64)
+}
+
+func (d Degree) Type() Type {
+	return DEGREE
+}
+
+type Int int64
+
+func (i Int) String() string {
+	return strconv.FormatInt(int64(i), 10)
+}
+
+func (i Int) Type() Type {
+	return INT
+}
+
+// Bits is a fixed-width bit-vector; bits above Width are always zero.
+type Bits struct {
+	Value uint64
+	Width int
+}
+
+func (b Bits) String() string {
+	digits := strconv.FormatUint(b.Value, 2)
+	return "0b" + strings.Repeat("0", b.Width-len(digits)) + digits
+}
+
+func (b Bits) Type() Type {
+	return BitsType{Width: b.Width}
+}
+
+// Set is a finite set of integers and symbols, kept sorted and without
+// duplicates: integers in order first, then symbols alphabetically.
+type Set struct {
+	Elems []string
+}
+
+// elemLess is the order of set elements: integers in order, then symbols.
+func elemLess(x, y string) bool {
+	a, aErr := strconv.ParseInt(x, 10, 64)
+	b, bErr := strconv.ParseInt(y, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return a < b
+	case aErr == nil || bErr == nil:
+		return aErr == nil
+	default:
+		return x < y
+	}
+}
+
+func NewSet(elems []string) Set {
+	sorted := append([]string{}, elems...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return elemLess(sorted[i], sorted[j])
+	})
+	acc := []string{}
+	for idx, elem := range sorted {
+		if idx == 0 || elem != sorted[idx-1] {
+			acc = append(acc, elem)
+		}
+	}
+	return Set{Elems: acc}
+}
+
+// Has searches the sorted elements, so set operations stay n log n.
+func (s Set) Has(elem string) bool {
+	idx := sort.Search(len(s.Elems), func(i int) bool {
+		return !elemLess(s.Elems[i], elem)
+	})
+	return idx < len(s.Elems) && s.Elems[idx] == elem
+}
+
+func (s Set) String() string {
+	return "{" + strings.Join(s.Elems, ", ") + "}"
+}
+
+func (s Set) Type() Type {
+	return SET
+}
+
+// Tuple is a fixed number of values of possibly different types.
+type Tuple struct {
+	Elems []Value
+}
+
+func (t Tuple) String() string {
+	return "(" + joinValues(t.Elems) + ")"
+}
+
+func (t Tuple) Type() Type {
+	elems := make([]Type, len(t.Elems))
+	for idx, elem := range t.Elems {
+		elems[idx] = elem.Type()
+	}
+	return TupleType{Elems: elems}
+}
+
+// List is any number of values of one type.
+type List struct {
+	Elems []Value
+}
+
+func (l List) String() string {
+	return "[" + joinValues(l.Elems) + "]"
+}
+
+func (l List) Type() Type {
+	if len(l.Elems) == 0 {
+		return ListType{}
+	}
+	return ListType{Elem: l.Elems[0].Type()}
+}
+
+func joinValues(values []Value) string {
+	acc := make([]string, len(values))
+	for idx, val := range values {
+		acc[idx] = val.String()
+	}
+	return strings.Join(acc, ", ")
+}
+
+// String is a text value. It prints as a literal that reads back as
+// itself.
+type String string
+
+var stringEscapes = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\t", `\t`,
+	"{", `\{`,
+	"}", `\}`,
 )
 
-type Position lexer.Position
+func (s String) String() string {
+	return `"` + stringEscapes.Replace(string(s)) + `"`
+}
+
+func (s String) Type() Type {
+	return STRING
+}
+
+// Regd. Types
+
+// Type classifies values; the checker assigns one to every expression
+// before it is evaluated.
+type Type interface {
+	String() string
+	Equal(other Type) bool
+}
+
+var (
+	BOOL   Type = BoolType{}
+	DEGREE Type = DegreeType{}
+	STRING Type = StringType{}
+	INT    Type = IntType{}
+	SET    Type = SetType{}
+)
+
+type BoolType struct{}
+
+func (BoolType) String() string {
+	return "Bool"
+}
+
+func (BoolType) Equal(other Type) bool {
+	_, ok := other.(BoolType)
+	return ok
+}
+
+type DegreeType struct{}
+
+func (DegreeType) String() string {
+	return "Degree"
+}
+
+func (DegreeType) Equal(other Type) bool {
+	_, ok := other.(DegreeType)
+	return ok
+}
+
+type StringType struct{}
+
+func (StringType) String() string {
+	return "String"
+}
+
+func (StringType) Equal(other Type) bool {
+	_, ok := other.(StringType)
+	return ok
+}
+
+type IntType struct{}
+
+func (IntType) String() string {
+	return "Int"
+}
+
+func (IntType) Equal(other Type) bool {
+	_, ok := other.(IntType)
+	return ok
+}
+
+type SetType struct{}
+
+func (SetType) String() string {
+	return "Set"
+}
+
+func (SetType) Equal(other Type) bool {
+	_, ok := other.(SetType)
+	return ok
+}
+
+type BitsType struct {
+	Width int
+}
+
+func (b BitsType) String() string {
+	return "Bits[" + strconv.Itoa(b.Width) + "]"
+}
+
+func (b BitsType) Equal(other Type) bool {
+	o, ok := other.(BitsType)
+	return ok && b.Width == o.Width
+}
+
+type FuncType struct {
+	Params []Type
+	Result Type
+}
+
+func (f FuncType) String() string {
+	params := TupleType{Elems: f.Params}.String()
+	if len(f.Params) == 1 {
+		params = f.Params[0].String()
+		if _, ok := f.Params[0].(FuncType); ok {
+			params = "(" + params + ")"
+		}
+	}
+	return params + " -> " + f.Result.String()
+}
+
+func (f FuncType) Equal(other Type) bool {
+	o, ok := other.(FuncType)
+	return ok && equalAll(f.Params, o.Params) && f.Result.Equal(o.Result)
+}
+
+type TupleType struct {
+	Elems []Type
+}
+
+func (t TupleType) String() string {
+	elems := make([]string, len(t.Elems))
+	for idx, elem := range t.Elems {
+		elems[idx] = elem.String()
+	}
+	return "(" + strings.Join(elems, ", ") + ")"
+}
+
+func (t TupleType) Equal(other Type) bool {
+	o, ok := other.(TupleType)
+	return ok && equalAll(t.Elems, o.Elems)
+}
+
+// ListType has no Elem for the empty list, which fits a list of any
+// type.
+type ListType struct {
+	Elem Type
+}
+
+func (l ListType) String() string {
+	if l.Elem == nil {
+		return "[]"
+	}
+	return "[" + l.Elem.String() + "]"
+}
+
+func (l ListType) Equal(other Type) bool {
+	o, ok := other.(ListType)
+	return ok && (l.Elem == nil || o.Elem == nil || l.Elem.Equal(o.Elem))
+}
+
+// DomainType is a finite, named set of values; domains are equal only when
+// they have the same name.
+type DomainType struct {
+	Name   string
+	Values []string
+}
+
+func (d DomainType) String() string {
+	return d.Name
+}
+
+func (d DomainType) Equal(other Type) bool {
+	o, ok := other.(DomainType)
+	return ok && d.Name == o.Name
+}
+
+func equalAll(as []Type, bs []Type) bool {
+	if len(as) != len(bs) {
+		return false
+	}
+	for idx := range as {
+		if !as[idx].Equal(bs[idx]) {
+			return false
+		}
+	}
+	return true
+}
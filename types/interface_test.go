@@ -1 +1,86 @@
 package types
+
+import "testing"
+
+func TestTypeStrings(t *testing.T) {
+	tests := []struct {
+		typ      Type
+		expected string
+	}{
+		{BOOL, "Bool"},
+		{DEGREE, "Degree"},
+		{INT, "Int"},
+		{BitsType{Width: 8}, "Bits[8]"},
+		{SET, "Set"},
+		{ListType{Elem: TupleType{Elems: []Type{BOOL, BOOL}}}, "[(Bool, Bool)]"},
+		{FuncType{Params: []Type{INT, INT}, Result: BOOL}, "(Int, Int) -> Bool"},
+		{FuncType{Params: []Type{BOOL}, Result: BOOL}, "Bool -> Bool"},
+		{FuncType{Params: []Type{BOOL, DEGREE}, Result: DEGREE}, "(Bool, Degree) -> Degree"},
+		{FuncType{Params: []Type{FuncType{Params: []Type{BOOL}, Result: BOOL}}, Result: BOOL}, "(Bool -> Bool) -> Bool"},
+		{TupleType{Elems: []Type{BOOL, BOOL}}, "(Bool, Bool)"},
+		{DomainType{Name: "Color", Values: []string{"red", "green"}}, "Color"},
+	}
+	for _, test := range tests {
+		if test.typ.String() != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, test.typ.String())
+		}
+	}
+}
+
+func TestTypeEquality(t *testing.T) {
+	pair := TupleType{Elems: []Type{BOOL, DEGREE}}
+	if !pair.Equal(TupleType{Elems: []Type{BOOL, DEGREE}}) {
+		t.Errorf("expected %s to equal itself", pair)
+	}
+	if pair.Equal(TupleType{Elems: []Type{DEGREE, BOOL}}) || BOOL.Equal(DEGREE) {
+		t.Errorf("expected different types to differ")
+	}
+	if (BitsType{Width: 4}).Equal(BitsType{Width: 8}) || !(Bits{Value: 3, Width: 4}).Type().Equal(BitsType{Width: 4}) {
+		t.Errorf("expected bit-vector types to differ by width")
+	}
+	if Bool(true).Type() != BOOL || Degree(0.5).Type() != DEGREE || String("").Type() != STRING || Int(-3).Type() != INT {
+		t.Errorf("expected values to report their types")
+	}
+}
+
+func TestStringQuotesItself(t *testing.T) {
+	s := String("say \"hi\"\n{x}\\")
+	expected := `"say \"hi\"\n\{x\}\\"`
+	if s.String() != expected {
+		t.Errorf("expected %s, got %s", expected, s.String())
+	}
+}
+
+func TestBitsKeepLeadingZeros(t *testing.T) {
+	b := Bits{Value: 0b0101, Width: 6}
+	if b.String() != "0b000101" {
+		t.Errorf("expected 0b000101, got %s", b.String())
+	}
+}
+
+func TestSetsAreSorted(t *testing.T) {
+	s := NewSet([]string{"red", "10", "-2", "blue", "red", "3"})
+	if s.String() != "{-2, 3, 10, blue, red}" {
+		t.Errorf("expected {-2, 3, 10, blue, red}, got %s", s.String())
+	}
+	for _, elem := range s.Elems {
+		if !s.Has(elem) {
+			t.Errorf("%s should have %s", s, elem)
+		}
+	}
+	for _, elem := range []string{"green", "a", "zebra", "4", "-3", "11", ""} {
+		if s.Has(elem) {
+			t.Errorf("%s should not have %s", s, elem)
+		}
+	}
+}
+
+func TestCompoundValues(t *testing.T) {
+	pair := Tuple{Elems: []Value{Bool(true), List{Elems: []Value{Bool(false)}}}}
+	if pair.String() != "(True, [False])" || pair.Type().String() != "(Bool, [Bool])" {
+		t.Errorf("unexpected %s : %s", pair, pair.Type())
+	}
+	if !(List{}).Type().Equal(ListType{Elem: BOOL}) {
+		t.Errorf("expected the empty list to fit a list of Bool")
+	}
+}
@@ -0,0 +1,375 @@
+package ir
+
+import (
+	"fmt"
+	"strings"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ast/arith"
+	"acornlang.dev/lang/types/ast/boolean"
+)
+
+// Regd. Operators
+
+// Op is a connective, whichever way it was spelled.
+type Op uint8
+
+const (
+	NOT Op = iota + 1
+	NULLIFY
+	TRUIFY
+	ID
+	NEXT
+	GLOBALLY
+	FINALLY
+	AND
+	NAND
+	OR
+	NOR
+	XOR
+	IFF
+	IMPLIES
+	IMPLIED_BY
+	INHIBITS
+	INHIBITED_BY
+	LEFT
+	RIGHT
+	NOT_LEFT
+	NOT_RIGHT
+	UNTIL
+	RELEASE
+)
+
+// opSpelling is an operator's text form and its symbol, if it has one.
+type opSpelling struct {
+	text, symb string
+}
+
+var spellings = map[Op]opSpelling{
+	NOT:          {lexer.NOT_TEXT, lexer.NOT_SYMB},
+	NULLIFY:      {lexer.NULLIFY_TEXT, ""},
+	TRUIFY:       {lexer.TRUIFY_TEXT, ""},
+	ID:           {lexer.ID_TEXT, ""},
+	NEXT:         {lexer.NEXT_TEXT, lexer.NEXT_SYMB},
+	GLOBALLY:     {lexer.GLOBALLY_TEXT, lexer.GLOBALLY_SYMB},
+	FINALLY:      {lexer.FINALLY_TEXT, lexer.FINALLY_SYMB},
+	AND:          {lexer.AND_TEXT, lexer.AND_SYMB},
+	NAND:         {lexer.NAND_TEXT, lexer.NAND_SYMB},
+	OR:           {lexer.OR_TEXT, lexer.OR_SYMB},
+	NOR:          {lexer.NOR_TEXT, lexer.NOR_SYMB},
+	XOR:          {lexer.XOR_TEXT, lexer.XOR_SYMB},
+	IFF:          {lexer.IFF_TEXT, lexer.XNOR_SYMB},
+	IMPLIES:      {lexer.IMPLIES_TEXT, lexer.IMPLIES_SYMB},
+	IMPLIED_BY:   {lexer.IMPLIED_BY_TEXT, lexer.IMPLIED_BY_SYMB},
+	INHIBITS:     {lexer.INHIBITS_TEXT, lexer.INHIBITS_SYMB},
+	INHIBITED_BY: {lexer.INHIBITED_BY_TEXT, lexer.INHIBITED_BY_SYMB},
+	LEFT:         {lexer.LEFT_TEXT, lexer.LEFT_SYMB},
+	RIGHT:        {lexer.RIGHT_TEXT, lexer.RIGHT_SYMB},
+	NOT_LEFT:     {lexer.NOT_LEFT_TEXT, lexer.NOT_LEFT_SYMB},
+	NOT_RIGHT:    {lexer.NOT_RIGHT_TEXT, lexer.NOT_RIGHT_SYMB},
+	UNTIL:        {lexer.UNTIL_TEXT, lexer.UNTIL_SYMB},
+	RELEASE:      {lexer.RELEASE_TEXT, lexer.RELEASE_SYMB},
+}
+
+var ops = opsBySpelling()
+
+func opsBySpelling() map[string]Op {
+	acc := map[string]Op{lexer.XNOR_TEXT: IFF}
+	for op, spelling := range spellings {
+		acc[spelling.text] = op
+		if spelling.symb != "" {
+			acc[spelling.symb] = op
+		}
+	}
+	return acc
+}
+
+// ParseOp reads an operator as the lexer gives it, in words or symbols;
+// `xnor`, `iff` and `<=>` are all IFF.
+func ParseOp(spelling string) (Op, bool) {
+	op, ok := ops[spelling]
+	return op, ok
+}
+
+// String is the operator's text form, as in `is implied by`.
+func (op Op) String() string {
+	if spelling, ok := spellings[op]; ok {
+		return spelling.text
+	}
+	return fmt.Sprintf("Op(%d)", op)
+}
+
+// Symbol is the operator's symbol, or its text form if it has none.
+func (op Op) Symbol() string {
+	if spelling := spellings[op]; spelling.symb != "" {
+		return spelling.symb
+	}
+	return op.String()
+}
+
+func (op Op) IsUnary() bool {
+	return op >= NOT && op <= FINALLY
+}
+
+// IsTemporal reports the operators that only have a meaning over a trace.
+func (op Op) IsTemporal() bool {
+	switch op {
+	case NEXT, GLOBALLY, FINALLY, UNTIL, RELEASE:
+		return true
+	}
+	return false
+}
+
+// Table is the truth table of a binary connective: bit 2*a+b holds the
+// result for operands a and b. Temporal operators have none.
+func (op Op) Table() (uint8, bool) {
+	switch op {
+	case AND:
+		return 0b1000, true
+	case NAND:
+		return 0b0111, true
+	case OR:
+		return 0b1110, true
+	case NOR:
+		return 0b0001, true
+	case XOR:
+		return 0b0110, true
+	case IFF:
+		return 0b1001, true
+	case IMPLIES:
+		return 0b1011, true
+	case IMPLIED_BY:
+		return 0b1101, true
+	case INHIBITS:
+		return 0b0100, true
+	case INHIBITED_BY:
+		return 0b0010, true
+	case LEFT:
+		return 0b1100, true
+	case RIGHT:
+		return 0b1010, true
+	case NOT_LEFT:
+		return 0b0011, true
+	case NOT_RIGHT:
+		return 0b0101, true
+	}
+	return 0, false
+}
+
+// Apply is the value of a binary connective for a and b.
+func (op Op) Apply(a bool, b bool) bool {
+	table, _ := op.Table()
+	idx := 0
+	if a {
+		idx += 2
+	}
+	if b {
+		idx++
+	}
+	return table&(1<<idx) != 0
+}
+
+// ApplyUnary is the value of a non-temporal unary operator on a.
+func (op Op) ApplyUnary(a bool) bool {
+	switch op {
+	case NOT:
+		return !a
+	case NULLIFY:
+		return false
+	case TRUIFY:
+		return true
+	default:
+		return a
+	}
+}
+
+// Regd. Nodes
+
+// Node is an expression of the semantic tree: brackets are gone, every
+// operator is an Op, and each node keeps where it came from.
+type Node interface {
+	Position() types.Position
+}
+
+// Unary applies Op to X; Pos is where the operator is, and Text is the
+// operator as written, for messages.
+type Unary struct {
+	Pos  types.Position
+	Op   Op
+	Text string
+	X    Node
+}
+
+// Binary applies Op to X and Y; Pos is where X starts and OpPos where the
+// operator is.
+type Binary struct {
+	Pos   types.Position
+	OpPos types.Position
+	Op    Op
+	Text  string
+	X     Node
+	Y     Node
+}
+
+type Var struct {
+	Pos  types.Position
+	Name string
+}
+
+type Const struct {
+	Pos   types.Position
+	Value bool
+}
+
+// Degree is a fuzzy truth degree, such as `0.7`, as written.
+type Degree struct {
+	Pos  types.Position
+	Text string
+}
+
+// Cmp is a comparison of integer expressions, left as parsed.
+type Cmp struct {
+	Pos types.Position
+	Cmp *arith.Comparison
+}
+
+func (n *Unary) Position() types.Position  { return n.Pos }
+func (n *Binary) Position() types.Position { return n.Pos }
+func (n *Var) Position() types.Position    { return n.Pos }
+func (n *Const) Position() types.Position  { return n.Pos }
+func (n *Degree) Position() types.Position { return n.Pos }
+func (n *Cmp) Position() types.Position    { return n.Pos }
+
+// FreeVars lists the boolean variables of node in the order they first
+// appear; the integers of a comparison are not among them.
+func FreeVars(node Node) []string {
+	acc := []string{}
+	seen := map[string]bool{}
+	var visit func(node Node)
+	visit = func(node Node) {
+		switch n := node.(type) {
+		case *Unary:
+			visit(n.X)
+		case *Binary:
+			visit(n.X)
+			visit(n.Y)
+		case *Var:
+			if !seen[n.Name] {
+				seen[n.Name] = true
+				acc = append(acc, n.Name)
+			}
+		}
+	}
+	visit(node)
+	return acc
+}
+
+// Regd. Lowering
+
+// Error is a node that cannot be lowered, at its position in the source;
+// a missing node has no position.
+type Error struct {
+	Pos types.Position
+	Msg string
+}
+
+func (e *Error) Error() string {
+	if e.Pos.Line == 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("%d:%d: %s", e.Pos.Line, e.Pos.Column, e.Msg)
+}
+
+func errorAt(pos types.Position, format string, args ...any) error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Lower turns a parsed boolean expression into its semantic tree; only a
+// tree that was not built by the parser can fail to lower.
+func Lower(expr *boolean.Expr) (Node, error) {
+	if expr == nil {
+		return nil, errorAt(types.Position{}, "invalid boolean expression 'nil'")
+	}
+	left, err := lowerUnary(expr.Unary)
+	if err != nil || expr.Rest == nil {
+		return left, err
+	}
+	right, err := Lower(expr.Rest.Expr)
+	if err != nil {
+		return nil, err
+	}
+	op, ok := ParseOp(expr.Rest.Op)
+	if !ok || op.IsUnary() {
+		return nil, errorAt(expr.Rest.Pos, "invalid binary operation '%s'", expr.Rest.Op)
+	}
+	return &Binary{Pos: expr.Pos, OpPos: expr.Rest.Pos, Op: op, Text: expr.Rest.Op, X: left, Y: right}, nil
+}
+
+func lowerUnary(expr *boolean.UnaryExpr) (Node, error) {
+	if expr == nil {
+		return nil, errorAt(types.Position{}, "invalid unary expression 'nil'")
+	}
+	acc, err := lowerPrimary(expr.Expr)
+	if err != nil {
+		return nil, err
+	}
+	for idx := len(expr.Ops) - 1; idx >= 0; idx-- {
+		unary := expr.Ops[idx]
+		op, ok := ParseOp(unary.Op)
+		if !ok || !op.IsUnary() {
+			return nil, errorAt(unary.Pos, "invalid unary operator '%s'", unary.Op)
+		}
+		acc = &Unary{Pos: unary.Pos, Op: op, Text: unary.Op, X: acc}
+	}
+	return acc, nil
+}
+
+func lowerPrimary(expr *boolean.PrimaryExpr) (Node, error) {
+	switch {
+	case expr == nil:
+		return nil, errorAt(types.Position{}, "invalid primary expression 'nil'")
+	case expr.Paren != nil && expr.Lit != "":
+		return nil, errorAt(expr.Pos, "invalid primary expression 'both Lit and Paren'")
+	case expr.Paren != nil:
+		return Lower(expr.Paren.Expr)
+	case expr.Cmp != nil:
+		return &Cmp{Pos: expr.Pos, Cmp: expr.Cmp}, nil
+	case expr.Var != "":
+		return &Var{Pos: expr.Pos, Name: expr.Var}, nil
+	case expr.Degree != "":
+		return &Degree{Pos: expr.Pos, Text: expr.Degree}, nil
+	case expr.Lit == lexer.TRUE:
+		return &Const{Pos: expr.Pos, Value: true}, nil
+	case expr.Lit == lexer.FALSE:
+		return &Const{Pos: expr.Pos, Value: false}, nil
+	default:
+		return nil, errorAt(expr.Pos, "invalid boolean literal '%s'", expr.Lit)
+	}
+}
+
+// Regd. Printing
+
+// Format prints node as an S-expression of text operators, as in
+// `(and p (not q))`; it is meant for tests and debugging.
+func Format(node Node) string {
+	switch n := node.(type) {
+	case *Unary:
+		return "(" + strings.ReplaceAll(n.Op.String(), " ", "-") + " " + Format(n.X) + ")"
+	case *Binary:
+		return "(" + strings.ReplaceAll(n.Op.String(), " ", "-") + " " + Format(n.X) + " " + Format(n.Y) + ")"
+	case *Var:
+		return n.Name
+	case *Const:
+		if n.Value {
+			return lexer.TRUE
+		}
+		return lexer.FALSE
+	case *Degree:
+		return n.Text
+	case *Cmp:
+		return "(" + n.Cmp.Op + " ...)"
+	default:
+		return fmt.Sprintf("%v", node)
+	}
+}
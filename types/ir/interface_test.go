@@ -0,0 +1,128 @@
+package ir
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/lexer"
+	"acornlang.dev/lang/types"
+	"acornlang.dev/lang/types/ast/boolean"
+	"github.com/alecthomas/participle/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+var exprParser = participle.MustBuild[boolean.Expr](
+	participle.Lexer(lexer.BooleanLexer),
+	participle.Elide("Whitespace", "Comment"),
+)
+
+func lower(t *testing.T, input string) Node {
+	expr, err := exprParser.ParseString("", input)
+	assert.NoError(t, err)
+	node, err := Lower(expr)
+	assert.NoError(t, err)
+	return node
+}
+
+func TestParseOp(t *testing.T) {
+	for _, spelling := range []string{"xnor", "iff", "<=>"} {
+		op, ok := ParseOp(spelling)
+		assert.True(t, ok, spelling)
+		assert.Equal(t, IFF, op, spelling)
+	}
+	op, ok := ParseOp("~")
+	assert.True(t, ok)
+	assert.Equal(t, NOT, op)
+	assert.True(t, op.IsUnary())
+	_, ok = ParseOp("maybe")
+	assert.False(t, ok)
+}
+
+func TestSpellings(t *testing.T) {
+	for op := NOT; op <= RELEASE; op++ {
+		parsed, ok := ParseOp(op.String())
+		assert.True(t, ok, op.String())
+		assert.Equal(t, op, parsed, op.String())
+		parsed, ok = ParseOp(op.Symbol())
+		assert.True(t, ok, op.Symbol())
+		assert.Equal(t, op, parsed, op.Symbol())
+	}
+}
+
+func TestApply(t *testing.T) {
+	assert.True(t, AND.Apply(true, true))
+	assert.False(t, AND.Apply(true, false))
+	assert.False(t, IMPLIES.Apply(true, false))
+	assert.True(t, IMPLIES.Apply(false, true))
+	assert.True(t, INHIBITS.Apply(true, false))
+	assert.True(t, RIGHT.Apply(false, true))
+	assert.False(t, NOT.ApplyUnary(true))
+	assert.True(t, TRUIFY.ApplyUnary(false))
+	_, ok := UNTIL.Table()
+	assert.False(t, ok)
+	assert.True(t, UNTIL.IsTemporal())
+}
+
+func TestLower(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"p", "p"},
+		{"True and not False", "(and True (not False))"},
+		{"((p))", "p"},
+		{"~(p /\\ q) => r", "(implies (not (and p q)) r)"},
+		{"p or q or r", "(or p (or q r))"},
+		{"p <=> q xnor r", "(iff p (iff q r))"},
+		{"□(p 𝒰 q)", "(globally (until p q))"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, Format(lower(t, test.input)), test.input)
+	}
+}
+
+func TestFreeVars(t *testing.T) {
+	assert.Equal(t, []string{"b", "a", "c"}, FreeVars(lower(t, "b and (a or not b) => (True xor c)")))
+	assert.Empty(t, FreeVars(lower(t, "x + 1 > y")))
+}
+
+func TestLowerPositions(t *testing.T) {
+	node := lower(t, "p and  ~q")
+	binary, ok := node.(*Binary)
+	assert.True(t, ok)
+	assert.Equal(t, "and", binary.Text)
+	assert.Equal(t, 1, binary.Pos.Column)
+	assert.Equal(t, 3, binary.OpPos.Column)
+	unary, ok := binary.Y.(*Unary)
+	assert.True(t, ok)
+	assert.Equal(t, "~", unary.Text)
+	assert.Equal(t, 8, unary.Pos.Column)
+}
+
+func TestLowerErrors(t *testing.T) {
+	_, err := Lower(nil)
+	assert.EqualError(t, err, "invalid boolean expression 'nil'")
+
+	pos := types.Position{Line: 1, Column: 3}
+	expr := &boolean.Expr{
+		Unary: &boolean.UnaryExpr{Expr: &boolean.PrimaryExpr{Var: "p"}},
+		Rest: &boolean.ExprRest{Pos: pos, Op: "not", Expr: &boolean.Expr{
+			Unary: &boolean.UnaryExpr{Expr: &boolean.PrimaryExpr{Var: "q"}},
+		}},
+	}
+	_, err = Lower(expr)
+	assert.EqualError(t, err, "1:3: invalid binary operation 'not'")
+
+	expr = &boolean.Expr{Unary: &boolean.UnaryExpr{
+		Ops:  []boolean.UnaryOp{{Pos: pos, Op: "and"}},
+		Expr: &boolean.PrimaryExpr{Var: "p"},
+	}}
+	_, err = Lower(expr)
+	var lowerErr *Error
+	assert.ErrorAs(t, err, &lowerErr)
+	assert.Equal(t, pos, lowerErr.Pos)
+	assert.Equal(t, "invalid unary operator 'and'", lowerErr.Msg)
+
+	expr = &boolean.Expr{Unary: &boolean.UnaryExpr{Expr: &boolean.PrimaryExpr{Pos: pos, Lit: "maybe"}}}
+	_, err = Lower(expr)
+	assert.EqualError(t, err, "1:3: invalid boolean literal 'maybe'")
+}
@@ -0,0 +1,106 @@
+package wasm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"acornlang.dev/lang/codegen/lisp"
+	"acornlang.dev/lang/parser/boolean"
+	"acornlang.dev/lang/render/english"
+	renderMath "acornlang.dev/lang/render/math"
+	booleanAst "acornlang.dev/lang/types/ast/boolean"
+)
+
+// Every call gives back an object whose "ok" is true along with the call's
+// fields, or false along with an "error". It holds only values syscall/js
+// converts, so lists are []any.
+
+func failure(err error) map[string]any {
+	return map[string]any{"ok": false, "error": err.Error()}
+}
+
+func parse(input string) (*booleanAst.Expr, error) {
+	return boolean.ExprParser.ParseString("", input)
+}
+
+// Regd. API
+
+// Parse gives the syntax tree of a boolean expression as plain objects,
+// positions included, and the names it reads.
+func Parse(input string) map[string]any {
+	expr, err := parse(input)
+	if err != nil {
+		return failure(err)
+	}
+	data, err := json.Marshal(expr)
+	if err != nil {
+		return failure(err)
+	}
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return failure(err)
+	}
+	vars := []any{}
+	for _, name := range boolean.FreeVars(expr) {
+		vars = append(vars, name)
+	}
+	return map[string]any{"ok": true, "ast": tree, "vars": vars}
+}
+
+// Eval evaluates a boolean expression with its names bound by env, and
+// gives the value as the REPL prints it along with its type.
+func Eval(input string, env boolean.Env) map[string]any {
+	expr, err := parse(input)
+	if err != nil {
+		return failure(err)
+	}
+	res := boolean.EvalExpr(expr, env)
+	if res.Err != nil {
+		return failure(res.Err)
+	}
+	return map[string]any{"ok": true, "value": res.Payload.String(), "type": res.Payload.Type().String()}
+}
+
+// NOTATIONS are the names Render takes: the math notations, English and
+// the Lisp flavours.
+var NOTATIONS = notations()
+
+func notations() []string {
+	names := []string{}
+	for _, notation := range renderMath.NOTATIONS {
+		names = append(names, notation.Name)
+	}
+	names = append(names, "english")
+	for _, flavour := range lisp.FLAVOURS {
+		names = append(names, flavour.Name)
+	}
+	return names
+}
+
+func renderer(name string) (func(*booleanAst.Expr) string, error) {
+	if name == "english" {
+		return english.Render, nil
+	}
+	if notation, err := renderMath.ParseNotation(name); err == nil {
+		return notation.Render, nil
+	}
+	if flavour, err := lisp.ParseFlavour(name); err == nil {
+		return flavour.Format, nil
+	}
+	names := NOTATIONS
+	return nil, fmt.Errorf("unknown notation '%s', expected %s or %s", name, strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
+
+// Render prints a boolean expression in the notation.
+func Render(input string, notation string) map[string]any {
+	render, err := renderer(notation)
+	if err != nil {
+		return failure(err)
+	}
+	expr, err := parse(input)
+	if err != nil {
+		return failure(err)
+	}
+	return map[string]any{"ok": true, "text": render(expr)}
+}
@@ -0,0 +1,34 @@
+package wasm
+
+import (
+	"testing"
+
+	"acornlang.dev/lang/parser/boolean"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	res := Parse("a and not b")
+	assert.Equal(t, true, res["ok"])
+	assert.Equal(t, []any{"a", "b"}, res["vars"])
+	tree := res["ast"].(map[string]any)
+	assert.Equal(t, "and", tree["Rest"].(map[string]any)["Op"])
+	assert.Equal(t, float64(3), tree["Rest"].(map[string]any)["pos"].(map[string]any)["Column"])
+
+	res = Parse("a and")
+	assert.Equal(t, false, res["ok"])
+	assert.Contains(t, res["error"], "1:")
+}
+
+func TestEval(t *testing.T) {
+	assert.Equal(t, map[string]any{"ok": true, "value": "True", "type": "Bool"}, Eval("a xor b", boolean.Env{"a": true, "b": false}))
+	assert.Equal(t, map[string]any{"ok": true, "value": "False", "type": "Bool"}, Eval("a => b", boolean.Env{"a": true, "b": false}))
+	assert.Equal(t, map[string]any{"ok": false, "error": "unbound variable 'c'"}, Eval("a or c", boolean.Env{"a": false}))
+}
+
+func TestRender(t *testing.T) {
+	assert.Equal(t, map[string]any{"ok": true, "text": "(and p (not q))"}, Render("p and ~q", "scheme"))
+	assert.Equal(t, map[string]any{"ok": true, "text": "p ∧ ¬q"}, Render("p and ~q", "unicode"))
+	assert.Equal(t, true, Render("p => q", "english")["ok"])
+	assert.Equal(t, map[string]any{"ok": false, "error": "unknown notation 'morse', expected ascii, unicode, latex, english, common-lisp, scheme or clojure"}, Render("p", "morse"))
+}